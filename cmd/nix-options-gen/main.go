@@ -23,6 +23,7 @@ type JSONSchema struct {
 	Required    []string               `json:"required"`
 	AnyOf       []*JSONSchema          `json:"anyOf"`
 	Minimum     *float64               `json:"minimum"`
+	Maximum     *float64               `json:"maximum"`
 	MinItems    *int                   `json:"minItems"`
 	MaxItems    *int                   `json:"maxItems"`
 	MinLength   *int                   `json:"minLength"`
@@ -170,6 +171,9 @@ func (g *generator) baseType(name string, prop *JSONSchema, nullable bool, inden
 		}
 		return "lib.types.int"
 
+	case "boolean":
+		return "lib.types.bool"
+
 	case "array":
 		fieldExpr := ctx.refFn(name)
 		if prop.MinItems != nil {
@@ -233,7 +237,7 @@ func (g *generator) itemType(listName string, items *JSONSchema, listNullable bo
 
 		// anyOf on the items schema (e.g., rule must have position or size)
 		if len(items.AnyOf) > 0 {
-			g.addAnyOfAssertion(items.AnyOf, itemCtx, binding)
+			g.addAnyOfAssertion(items.AnyOf, itemCtx, binding, itemNoun(listName))
 		}
 
 		return g.submoduleType(items, indent, itemCtx)
@@ -262,7 +266,15 @@ func (g *generator) itemType(listName string, items *JSONSchema, listNullable bo
 }
 
 // primitiveType returns a Nix type for a primitive schema (no assertions).
+// An untyped schema with anyOf alternatives (e.g. integer | number | string) maps to lib.types.oneOf.
 func (g *generator) primitiveType(prop *JSONSchema) string {
+	if prop.Type == "" && len(prop.AnyOf) > 0 {
+		alts := make([]string, 0, len(prop.AnyOf))
+		for _, alt := range prop.AnyOf {
+			alts = append(alts, g.primitiveType(alt))
+		}
+		return "lib.types.oneOf [ " + strings.Join(alts, " ") + " ]"
+	}
 	switch prop.Type {
 	case "integer":
 		if prop.Minimum != nil {
@@ -274,6 +286,10 @@ func (g *generator) primitiveType(prop *JSONSchema) string {
 			}
 		}
 		return "lib.types.int"
+	case "number":
+		return "lib.types.number"
+	case "boolean":
+		return "lib.types.bool"
 	case "string":
 		return "lib.types.str"
 	}
@@ -304,8 +320,10 @@ func (g *generator) submoduleType(schema *JSONSchema, indent int, ctx assertCtx)
 }
 
 // addAnyOfAssertion handles anyOf on an items schema (e.g., rule must have position or size).
-func (g *generator) addAnyOfAssertion(anyOf []*JSONSchema, ctx assertCtx, binding string) {
+// noun names an item in the assertion message.
+func (g *generator) addAnyOfAssertion(anyOf []*JSONSchema, ctx assertCtx, binding, noun string) {
 	parts := make([]string, 0, len(anyOf))
+	names := make([]string, 0, len(anyOf))
 	for _, alt := range anyOf {
 		if len(alt.Required) > 0 {
 			fieldParts := make([]string, 0, len(alt.Required))
@@ -313,6 +331,7 @@ func (g *generator) addAnyOfAssertion(anyOf []*JSONSchema, ctx assertCtx, bindin
 				fieldParts = append(fieldParts, binding+"."+req+" != null")
 			}
 			parts = append(parts, "("+strings.Join(fieldParts, " && ")+")")
+			names = append(names, "'"+strings.Join(alt.Required, "' and '")+"'")
 		}
 	}
 	if len(parts) == 0 {
@@ -321,7 +340,7 @@ func (g *generator) addAnyOfAssertion(anyOf []*JSONSchema, ctx assertCtx, bindin
 	inner := strings.Join(parts, " || ")
 	g.asserts = append(g.asserts, nixAssert{
 		condition: makeGuard(ctx.nullGuards) + ctx.wrapFn(inner),
-		message:   "Each preset rule must have at least 'position' or 'size'",
+		message:   "Each " + noun + " must have at least " + joinOr(names),
 	})
 }
 
@@ -333,6 +352,18 @@ func makeGuard(guards []string) string {
 	return strings.Join(guards, " || ") + " || "
 }
 
+// itemNoun returns how assertion messages refer to an item of listName.
+func itemNoun(listName string) string {
+	switch listName {
+	case "rules":
+		return "preset rule"
+	case "screens":
+		return "screen alias"
+	default:
+		return "item of " + listName
+	}
+}
+
 func pickBinding(listName string) string {
 	switch listName {
 	case "presets":
@@ -347,6 +378,14 @@ func pickBinding(listName string) string {
 	}
 }
 
+// joinOr joins names as "a", "a or b", or "a, b or c".
+func joinOr(names []string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
 func joinEnum(vals []string) string {
 	quoted := make([]string, len(vals))
 	for i, v := range vals {
@@ -11,6 +11,8 @@ package ax
 #include <stdlib.h>
 #include <dlfcn.h>
 #include <stdint.h>
+#include <string.h>
+#include <libproc.h>
 
 // --- CGS Private API (SkyLight.framework via dlsym) ---
 
@@ -116,6 +118,11 @@ CFArrayRef cg_list_windows() {
     );
 }
 
+// Window levels of the menu bar and the Dock, whose windows a screen's visible
+// frame leaves out.
+int cg_menubar_level(void) { return (int)CGWindowLevelForKey(kCGMainMenuWindowLevelKey); }
+int cg_dock_level(void)    { return (int)CGWindowLevelForKey(kCGDockWindowLevelKey); }
+
 // Retrieve an int32 value from a CFDictionary
 int cg_dict_int(CFDictionaryRef dict, CFStringRef key, int32_t *out) {
     CFNumberRef num = (CFNumberRef)CFDictionaryGetValue(dict, key);
@@ -160,6 +167,30 @@ char* cf_to_cstr(CFStringRef s) {
     return buf;
 }
 
+// Return the bundle identifier of the app bundle containing the executable of pid,
+// or NULL for processes outside an app bundle (caller must free).
+char* app_bundle_id(pid_t pid) {
+    char path[PROC_PIDPATHINFO_MAXSIZE];
+    if (proc_pidpath(pid, path, sizeof(path)) <= 0) return NULL;
+    // <bundle>.app/Contents/MacOS/<executable>: cut the path at the innermost bundle
+    char *contents = NULL;
+    for (char *p = strstr(path, "/Contents/MacOS/"); p; p = strstr(p + 1, "/Contents/MacOS/")) {
+        contents = p;
+    }
+    if (!contents) return NULL;
+    *contents = '\0';
+
+    CFURLRef url = CFURLCreateFromFileSystemRepresentation(
+        NULL, (const UInt8*)path, (CFIndex)strlen(path), true);
+    if (!url) return NULL;
+    CFBundleRef bundle = CFBundleCreate(NULL, url);
+    CFRelease(url);
+    if (!bundle) return NULL;
+    char *s = cf_to_cstr(CFBundleGetIdentifier(bundle));
+    CFRelease(bundle);
+    return s;
+}
+
 // AX API: retrieve the window array for a PID and return a CFArrayRef that the caller must CFRelease
 CFArrayRef ax_windows_for_pid(pid_t pid) {
     AXUIElementRef app = AXUIElementCreateApplication(pid);
@@ -209,6 +240,41 @@ int ax_set_size(AXUIElementRef win, double w, double h) {
     return err;
 }
 
+// Private AX SPI mapping an AX window element to its CGWindowID.
+// It has been available since macOS 10.x and is widely used by window managers.
+extern AXError _AXUIElementGetWindow(AXUIElementRef element, CGWindowID *out);
+
+// AX API: return the CGWindowID of an AX window (0 if unavailable)
+uint32_t ax_window_id(AXUIElementRef win) {
+    CGWindowID wid = 0;
+    if (_AXUIElementGetWindow(win, &wid) != kAXErrorSuccess) return 0;
+    return (uint32_t)wid;
+}
+
+// AX API: return the index of the window with the given CGWindowID in arr (-1 if absent)
+int ax_index_for_id(CFArrayRef arr, uint32_t wid) {
+    if (!arr || wid == 0) return -1;
+    CFIndex n = CFArrayGetCount(arr);
+    for (CFIndex i = 0; i < n; i++) {
+        AXUIElementRef win = (AXUIElementRef)CFArrayGetValueAtIndex(arr, i);
+        if (ax_window_id(win) == wid) return (int)i;
+    }
+    return -1;
+}
+
+// Look up the owner PID of a window by CGWindowID (0 if not found)
+int32_t cg_window_owner_pid(uint32_t wid) {
+    CFArrayRef arr = CGWindowListCopyWindowInfo(kCGWindowListOptionIncludingWindow, (CGWindowID)wid);
+    if (!arr) return 0;
+    int32_t pid = 0;
+    if (CFArrayGetCount(arr) > 0) {
+        CFDictionaryRef d = (CFDictionaryRef)CFArrayGetValueAtIndex(arr, 0);
+        cg_dict_int(d, kCGWindowOwnerPID, &pid);
+    }
+    CFRelease(arr);
+    return pid;
+}
+
 // Null-check helpers (CF types cannot be compared directly to nil in cgo)
 int cf_array_is_null(CFArrayRef a)       { return a == NULL ? 1 : 0; }
 int cf_string_is_null(CFStringRef s)     { return s == NULL ? 1 : 0; }
@@ -354,7 +420,9 @@ func (s *darwinService) ListWindows(ctx context.Context) ([]Window, error) {
 	// CGWindowList has one entry per window.
 	// When a PID has multiple windows, the AX-side index must be tracked.
 	// AX API window list takes precedence; CGWindowList is used as supplemental info.
-	pidWindowIndex := make(map[uint32]int) // per-PID AX index counter
+	pidWindowIndex := make(map[uint32]int)       // per-PID AX index counter
+	axIndexes := make(map[uint32]map[uint32]int) // per-PID CGWindowID -> AX index
+	bundleIDs := make(map[uint32]string)         // per-PID bundle identifier cache
 
 	for i := 0; i < count; i++ {
 		select {
@@ -369,7 +437,7 @@ func (s *darwinService) ListWindows(ctx context.Context) ([]Window, error) {
 		}
 		dict := C.CFDictionaryRef(dictRef)
 
-		entry := windowFromCGInfo(dict, screens, axCache, pidWindowIndex)
+		entry := windowFromCGInfo(dict, screens, axCache, axIndexes, pidWindowIndex, bundleIDs)
 		if entry == nil {
 			continue
 		}
@@ -436,6 +504,7 @@ func (s *darwinService) ListScreens(ctx context.Context) ([]Screen, error) {
 
 	primaryID := C.CGMainDisplayID()
 	screens := make([]Screen, 0, int(count))
+	bars := systemBars()
 
 	for i := 0; i < int(count); i++ {
 		select {
@@ -447,7 +516,7 @@ func (s *darwinService) ListScreens(ctx context.Context) ([]Screen, error) {
 		id := displayIDs[i]
 		bounds := C.CGDisplayBounds(id)
 
-		screens = append(screens, Screen{
+		scr := Screen{
 			ID:        uint32(id),
 			Name:      fmt.Sprintf("Display %d", uint32(id)),
 			X:         int(bounds.origin.x),
@@ -455,14 +524,16 @@ func (s *darwinService) ListScreens(ctx context.Context) ([]Screen, error) {
 			Width:     int(bounds.size.width),
 			Height:    int(bounds.size.height),
 			IsPrimary: id == primaryID,
-		})
+		}
+		scr.VisibleFrame = visibleFrame(scr.Bounds(), bars)
+		screens = append(screens, scr)
 	}
 
 	return screens, nil
 }
 
 // MoveWindow moves the specified window to a new position (T025).
-func (s *darwinService) MoveWindow(ctx context.Context, pid uint32, title string, x, y int) error {
+func (s *darwinService) MoveWindow(ctx context.Context, id uint32, x, y int) error {
 	if err := s.CheckPermission(); err != nil {
 		return err
 	}
@@ -474,7 +545,7 @@ func (s *darwinService) MoveWindow(ctx context.Context, pid uint32, title string
 		default:
 		}
 
-		win, err := findAXWindow(pid, title)
+		win, err := findAXWindow(id)
 		if err != nil {
 			return err
 		}
@@ -488,7 +559,7 @@ func (s *darwinService) MoveWindow(ctx context.Context, pid uint32, title string
 }
 
 // ResizeWindow resizes the specified window (T025).
-func (s *darwinService) ResizeWindow(ctx context.Context, pid uint32, title string, w, h int) error {
+func (s *darwinService) ResizeWindow(ctx context.Context, id uint32, w, h int) error {
 	if err := s.CheckPermission(); err != nil {
 		return err
 	}
@@ -500,7 +571,7 @@ func (s *darwinService) ResizeWindow(ctx context.Context, pid uint32, title stri
 		default:
 		}
 
-		win, err := findAXWindow(pid, title)
+		win, err := findAXWindow(id)
 		if err != nil {
 			return err
 		}
@@ -521,7 +592,9 @@ func windowFromCGInfo(
 	dict C.CFDictionaryRef,
 	screens []Screen,
 	axCache map[uint32]C.CFArrayRef,
+	axIndexes map[uint32]map[uint32]int,
 	pidWindowIndex map[uint32]int,
+	bundleIDs map[uint32]string,
 ) *windowEntry {
 	// Extract CGWindowID via kCGWindowNumber (needed for CGS Space lookup).
 	var cgWinNum C.int32_t
@@ -549,6 +622,16 @@ func windowFromCGInfo(
 		return nil
 	}
 
+	// Resolve the bundle identifier from the process executable (cached per PID)
+	bundleID, ok := bundleIDs[appPID]
+	if !ok {
+		if cs := C.app_bundle_id(C.pid_t(appPID)); cs != nil {
+			bundleID = C.GoString(cs)
+			C.free(unsafe.Pointer(cs))
+		}
+		bundleIDs[appPID] = bundleID // cache even if empty
+	}
+
 	// Retrieve position and size via kCGWindowBounds
 	boundsDict := C.cg_dict_bounds(dict)
 	var x, y, width, height int
@@ -569,14 +652,20 @@ func windowFromCGInfo(
 	if !ok {
 		axArr = C.ax_windows_for_pid(C.pid_t(appPID))
 		axCache[appPID] = axArr // cache even if nil
+		axIndexes[appPID] = axIndexByID(axArr)
 	}
 
 	title := ""
 	state := StateNormal
 
 	if C.cf_array_is_null(axArr) == 0 {
+		// Pair the CG entry with its AX element by window ID; fall back to
+		// list order when the ID lookup is unavailable.
 		idx := pidWindowIndex[appPID]
 		pidWindowIndex[appPID]++
+		if i, ok := axIndexes[appPID][uint32(cgWinNum)]; ok {
+			idx = i
+		}
 
 		if idx < int(C.CFArrayGetCount(axArr)) {
 			win := C.AXUIElementRef(C.CFArrayGetValueAtIndex(axArr, C.CFIndex(idx)))
@@ -602,7 +691,9 @@ func windowFromCGInfo(
 
 	return &windowEntry{
 		win: &Window{
+			ID:         uint32(cgWinNum),
 			AppName:    appName,
+			BundleID:   bundleID,
 			Title:      title,
 			PID:        appPID,
 			X:          x,
@@ -618,6 +709,79 @@ func windowFromCGInfo(
 	}
 }
 
+// systemBars returns the frames of the on-screen menu bar and Dock windows.
+// They are taken from CGWindowList rather than NSScreen so that no AppKit is needed.
+func systemBars() []Rect {
+	infoList := C.cg_list_windows()
+	if C.cf_array_is_null(infoList) != 0 {
+		return nil
+	}
+	defer C.cf_release_array(infoList)
+
+	menubar, dock := C.cg_menubar_level(), C.cg_dock_level()
+	var bars []Rect
+	for i := 0; i < int(C.CFArrayGetCount(infoList)); i++ {
+		dict := C.CFDictionaryRef(C.CFArrayGetValueAtIndex(infoList, C.CFIndex(i)))
+		var layer C.int32_t
+		if C.cf_dict_is_null(dict) != 0 || C.cg_dict_int(dict, C.kCGWindowLayer, &layer) == 0 {
+			continue
+		}
+		if C.int(layer) != menubar && C.int(layer) != dock {
+			continue
+		}
+		boundsDict := C.cg_dict_bounds(dict)
+		var x, y, w, h C.int
+		if C.cf_dict_is_null(boundsDict) == 0 && C.cg_parse_bounds(boundsDict, &x, &y, &w, &h) != 0 {
+			bars = append(bars, Rect{X: int(x), Y: int(y), W: int(w), H: int(h)})
+		}
+	}
+	return bars
+}
+
+// visibleFrame returns bounds without the parts covered by bars. Each bar is cut
+// off the edge of the screen it lies along: wide bars (the menu bar, a bottom Dock)
+// off the top or bottom, tall bars (a side Dock) off the left or right.
+func visibleFrame(bounds Rect, bars []Rect) Rect {
+	v := bounds
+	for _, b := range bars {
+		x0, y0 := max(b.X, v.X), max(b.Y, v.Y)
+		x1, y1 := min(b.X+b.W, v.X+v.W), min(b.Y+b.H, v.Y+v.H)
+		if x1 <= x0 || y1 <= y0 {
+			continue
+		}
+		switch {
+		case x1-x0 >= y1-y0 && y0-v.Y < v.Y+v.H-y1:
+			v.H -= y1 - v.Y
+			v.Y = y1
+		case x1-x0 >= y1-y0:
+			v.H = y0 - v.Y
+		case x0-v.X < v.X+v.W-x1:
+			v.W -= x1 - v.X
+			v.X = x1
+		default:
+			v.W = x0 - v.X
+		}
+	}
+	return v
+}
+
+// axIndexByID maps the CGWindowID of every window in arr to its index, so that CG
+// entries are paired with AX elements without walking arr once per window.
+func axIndexByID(arr C.CFArrayRef) map[uint32]int {
+	if C.cf_array_is_null(arr) != 0 {
+		return nil
+	}
+	n := int(C.CFArrayGetCount(arr))
+	index := make(map[uint32]int, n)
+	for i := 0; i < n; i++ {
+		win := C.AXUIElementRef(C.CFArrayGetValueAtIndex(arr, C.CFIndex(i)))
+		if id := uint32(C.ax_window_id(win)); id != 0 {
+			index[id] = i
+		}
+	}
+	return index
+}
+
 // deriveScreen returns the screen with the largest intersection area with the given window rectangle.
 func deriveScreen(wx, wy, ww, wh int, screens []Screen) (uint32, string) {
 	maxArea := 0
@@ -642,29 +806,25 @@ func deriveScreen(wx, wy, ww, wh int, screens []Screen) (uint32, string) {
 	return bestID, bestName
 }
 
-// findAXWindow searches for an AXUIElementRef by PID and title (caller must CFRelease).
-func findAXWindow(pid uint32, title string) (C.AXUIElementRef, error) {
+// findAXWindow searches for the AXUIElementRef of the window with the given CGWindowID
+// (caller must CFRelease).
+func findAXWindow(id uint32) (C.AXUIElementRef, error) {
+	pid := C.cg_window_owner_pid(C.uint32_t(id))
+	if pid == 0 {
+		return 0, fmt.Errorf("window not found: id=%d", id)
+	}
+
 	arr := C.ax_windows_for_pid(C.pid_t(pid))
 	if C.cf_array_is_null(arr) != 0 {
 		return 0, fmt.Errorf("no AX windows for pid %d", pid)
 	}
 	defer C.CFRelease(C.CFTypeRef(arr))
 
-	count := int(C.CFArrayGetCount(arr))
-	for i := 0; i < count; i++ {
-		win := C.AXUIElementRef(C.CFArrayGetValueAtIndex(arr, C.CFIndex(i)))
-
-		titleCS := C.ax_window_title(win)
-		if titleCS == nil {
-			continue
-		}
-		t := C.GoString(titleCS)
-		C.free(unsafe.Pointer(titleCS))
-
-		if t == title {
-			C.CFRetain(C.CFTypeRef(win))
-			return win, nil
-		}
+	idx := int(C.ax_index_for_id(arr, C.uint32_t(id)))
+	if idx < 0 {
+		return 0, fmt.Errorf("window not found: pid=%d id=%d", pid, id)
 	}
-	return 0, fmt.Errorf("window not found: pid=%d title=%q", pid, title)
+	win := C.AXUIElementRef(C.CFArrayGetValueAtIndex(arr, C.CFIndex(idx)))
+	C.CFRetain(C.CFTypeRef(win))
+	return win, nil
 }
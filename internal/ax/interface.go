@@ -13,11 +13,11 @@ type WindowService interface {
 	// ListScreens returns all connected displays.
 	ListScreens(ctx context.Context) ([]Screen, error)
 
-	// MoveWindow moves the window identified by the given process and title.
-	MoveWindow(ctx context.Context, pid uint32, title string, x, y int) error
+	// MoveWindow moves the window with the given ID (Window.ID).
+	MoveWindow(ctx context.Context, id uint32, x, y int) error
 
-	// ResizeWindow resizes the window identified by the given process and title.
-	ResizeWindow(ctx context.Context, pid uint32, title string, w, h int) error
+	// ResizeWindow resizes the window with the given ID (Window.ID).
+	ResizeWindow(ctx context.Context, id uint32, w, h int) error
 
 	// CheckPermission verifies that Accessibility permission is granted.
 	// Returns a PermissionError if permission is not available.
@@ -36,15 +36,19 @@ func (m *MockWindowService) ListScreens(_ context.Context) ([]Screen, error) {
 	}
 	result := make([]Screen, len(m.Screens))
 	copy(result, m.Screens)
+	// like the darwin service, always report a visible frame
+	for i := range result {
+		result[i].VisibleFrame = result[i].Visible()
+	}
 	return result, nil
 }
 
 // MoveWindow implements WindowService.MoveWindow.
-func (m *MockWindowService) MoveWindow(_ context.Context, _ uint32, _ string, _, _ int) error {
+func (m *MockWindowService) MoveWindow(_ context.Context, _ uint32, _, _ int) error {
 	return m.MoveErr
 }
 
 // ResizeWindow implements WindowService.ResizeWindow.
-func (m *MockWindowService) ResizeWindow(_ context.Context, _ uint32, _ string, _, _ int) error {
+func (m *MockWindowService) ResizeWindow(_ context.Context, _ uint32, _, _ int) error {
 	return m.ResizeErr
 }
@@ -58,12 +58,12 @@ func TestMockWindowService_ListScreens_Error(t *testing.T) {
 func TestMockWindowService_MoveWindow_Timeout(t *testing.T) {
 	svc := &ax.MockWindowService{
 		Windows: []ax.Window{
-			{AppName: "Terminal", Title: "test", PID: 1, State: ax.StateNormal},
+			{ID: 1, AppName: "Terminal", Title: "test", PID: 1, State: ax.StateNormal},
 		},
 		MoveErr: context.DeadlineExceeded,
 	}
 
-	err := svc.MoveWindow(context.Background(), 1, "test", 0, 0)
+	err := svc.MoveWindow(context.Background(), 1, 0, 0)
 	if !errors.Is(err, context.DeadlineExceeded) {
 		t.Errorf("expected DeadlineExceeded from mock MoveWindow, got %v", err)
 	}
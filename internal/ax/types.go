@@ -13,7 +13,11 @@ const (
 
 // Window represents an individual application window on macOS.
 type Window struct {
+	// ID is the CGWindowID. It is stable for the lifetime of the window and
+	// distinguishes windows of the same app that share a title.
+	ID         uint32      `json:"id"`
 	AppName    string      `json:"app_name"`
+	BundleID   string      `json:"bundle_id"` // e.g. "com.microsoft.VSCode"; empty outside app bundles
 	Title      string      `json:"title"`
 	PID        uint32      `json:"pid"`
 	X          int         `json:"x"`
@@ -28,7 +32,13 @@ type Window struct {
 	Desktop int `json:"desktop"`
 }
 
+// Frame returns the position and size of w.
+func (w Window) Frame() Rect {
+	return Rect{X: w.X, Y: w.Y, W: w.Width, H: w.Height}
+}
+
 // Screen represents an individual display on macOS.
+// X, Y, Width and Height are the full display bounds in global coordinates.
 type Screen struct {
 	ID        uint32 `json:"id"`
 	Name      string `json:"name"`
@@ -37,6 +47,30 @@ type Screen struct {
 	Width     int    `json:"width"`
 	Height    int    `json:"height"`
 	IsPrimary bool   `json:"is_primary"`
+	// VisibleFrame is the usable area of the display: the bounds without the menu bar
+	// and the Dock. It is zero when unknown; use Visible to fall back to the bounds.
+	VisibleFrame Rect `json:"visible_frame"`
+}
+
+// Rect is a rectangle in global coordinates (origin at the top-left of the primary screen).
+type Rect struct {
+	X int `json:"x"      yaml:"x"`
+	Y int `json:"y"      yaml:"y"`
+	W int `json:"width"  yaml:"width"`
+	H int `json:"height" yaml:"height"`
+}
+
+// Bounds returns the full display bounds of s.
+func (s Screen) Bounds() Rect {
+	return Rect{X: s.X, Y: s.Y, W: s.Width, H: s.Height}
+}
+
+// Visible returns the usable area of s, or its full bounds when the usable area is unknown.
+func (s Screen) Visible() Rect {
+	if s.VisibleFrame.W <= 0 || s.VisibleFrame.H <= 0 {
+		return s.Bounds()
+	}
+	return s.VisibleFrame
 }
 
 // Application represents a running application on macOS.
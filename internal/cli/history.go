@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/history"
+	"github.com/peacock0803sz/mado/internal/output"
+)
+
+// newUndoCmd creates the undo subcommand.
+func newUndoCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "undo",
+		Short: "Restore the windows changed by the last move, tile or preset apply",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runRestore(cmd, svc, root, "undo")
+		},
+	}
+}
+
+// newRedoCmd creates the redo subcommand.
+func newRedoCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "redo",
+		Short: "Re-apply the last undone operation",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runRestore(cmd, svc, root, "redo")
+		},
+	}
+}
+
+// newHistoryCmd creates the history subcommand.
+func newHistoryCmd(root *RootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "history",
+		Short: "Show the undo/redo history",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			f := output.New(newOutputFormat(root.Format), os.Stdout, os.Stderr)
+
+			store, err := openHistory()
+			if err != nil {
+				_ = f.PrintError(1, err.Error(), nil)
+				os.Exit(1)
+			}
+			return f.PrintHistory(store.UndoStack, store.RedoStack)
+		},
+	}
+}
+
+// runRestore performs an undo or redo and saves the updated history.
+func runRestore(cmd *cobra.Command, svc ax.WindowService, root *RootFlags, action string) error {
+	f := output.New(newOutputFormat(root.Format), os.Stdout, os.Stderr)
+
+	if err := svc.CheckPermission(); err != nil {
+		msg := err.Error()
+		if permErr, ok := err.(*ax.PermissionError); ok {
+			msg = permErr.Error() + "\n\n" + permErr.Resolution()
+		}
+		_ = f.PrintError(2, msg, nil)
+		os.Exit(2)
+	}
+
+	store, err := openHistory()
+	if err != nil {
+		_ = f.PrintError(1, err.Error(), nil)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), root.Timeout)
+	defer cancel()
+
+	var res *history.RestoreResult
+	if action == "redo" {
+		res, err = store.Redo(ctx, svc)
+	} else {
+		res, err = store.Undo(ctx, svc)
+	}
+	if err != nil {
+		if errors.Is(err, history.ErrNothingToUndo) || errors.Is(err, history.ErrNothingToRedo) {
+			_ = f.PrintError(4, err.Error(), nil)
+			os.Exit(4)
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			_ = f.PrintError(6, "AX operation timed out", nil)
+			os.Exit(6)
+		}
+		return err
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	resp := output.HistoryRestoreResponse{
+		SchemaVersion: 1,
+		Success:       len(res.Missing) == 0,
+		Action:        action,
+		Command:       res.Entry.Command,
+		Restored:      nonNilWindows(res.Restored),
+		Missing:       nonNilWindows(res.Missing),
+	}
+	if len(res.Missing) > 0 {
+		resp.Error = &output.ErrorDetail{
+			Code:    7,
+			Message: fmt.Sprintf("%d window(s) no longer exist", len(res.Missing)),
+		}
+		_ = f.PrintHistoryRestore(resp)
+		os.Exit(7)
+	}
+	return f.PrintHistoryRestore(resp)
+}
+
+// openHistory opens the history store at its default location.
+func openHistory() (*history.Store, error) {
+	path, err := history.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return history.Open(path, history.DefaultLimit)
+}
+
+// recordHistory stores the frames of the windows a command changed so that it can be undone.
+// before is a snapshot taken before the command; after holds the changed windows.
+// History is best-effort: failures are reported as warnings and never fail the command.
+func recordHistory(cmd *cobra.Command, args []string, before, after []ax.Window) {
+	e := history.NewEntry(commandLine(cmd, args), before, after)
+	if len(e.Before) == 0 {
+		return
+	}
+	store, err := openHistory()
+	if err == nil {
+		store.Record(e)
+		err = store.Save()
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: cannot record history: %v\n", err)
+	}
+}
+
+// commandLine describes a command invocation for history, e.g. "preset apply coding".
+func commandLine(cmd *cobra.Command, args []string) string {
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	return strings.Join(append([]string{path}, args...), " ")
+}
+
+func nonNilWindows(ws []ax.Window) []ax.Window {
+	if ws == nil {
+		return []ax.Window{}
+	}
+	return ws
+}
@@ -0,0 +1,52 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+)
+
+func TestMoveUndo(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	svc := &ax.MockWindowService{Windows: listTestWindows}
+
+	if _, err := executeListCmdCapture(t, svc, "", "move", "--app", "Terminal", "--position", "0,0"); err != nil {
+		t.Fatalf("move: unexpected error: %v", err)
+	}
+
+	out, err := executeListCmdCapture(t, svc, "", "history")
+	if err != nil {
+		t.Fatalf("history: unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "undo") || !strings.Contains(out, "move") {
+		t.Errorf("history should list the move, got:\n%s", out)
+	}
+
+	out, err = executeListCmdCapture(t, svc, "", "undo")
+	if err != nil {
+		t.Fatalf("undo: unexpected error: %v", err)
+	}
+	if !strings.Contains(out, `Undid "move"`) || !strings.Contains(out, "Terminal") {
+		t.Errorf("undo should restore Terminal, got:\n%s", out)
+	}
+
+	out, err = executeListCmdCapture(t, svc, "", "history")
+	if err != nil {
+		t.Fatalf("history: unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "redo") {
+		t.Errorf("undone move should be redoable, got:\n%s", out)
+	}
+}
+
+func TestHistory_Empty(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	out, err := executeListCmdCapture(t, &ax.MockWindowService{}, "", "history")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "(no history)") {
+		t.Errorf("expected empty history, got:\n%s", out)
+	}
+}
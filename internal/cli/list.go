@@ -3,7 +3,9 @@ package cli
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -15,8 +17,15 @@ import (
 // newListCmd creates the list subcommand (T023).
 func newListCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
 	var appFilter string
+	var bundleID string
+	var titleRegex string
+	var notTitle string
+	var where string
 	var screenFilter string
 	var desktopFilter int
+	var sortSpec string
+	var columnSpec string
+	var noHeader bool
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -27,6 +36,25 @@ func newListCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
 
 			f := output.New(newOutputFormat(root.Format), os.Stdout, os.Stderr)
 
+			table := output.TableOptions{NoHeader: noHeader}
+			if sortSpec != "" {
+				keys, err := output.ParseSort(sortSpec)
+				if err != nil {
+					_ = f.PrintError(3, fmt.Sprintf("invalid --sort value: %v", err), nil)
+					os.Exit(3)
+				}
+				table.Sort = keys
+			}
+			if columnSpec != "" {
+				cols, err := output.ParseColumns(columnSpec)
+				if err != nil {
+					_ = f.PrintError(3, fmt.Sprintf("invalid --columns value: %v", err), nil)
+					os.Exit(3)
+				}
+				table.Columns = cols
+			}
+			f.SetTableOptions(table)
+
 			if err := svc.CheckPermission(); err != nil {
 				msg := err.Error()
 				if permErr, ok := err.(*ax.PermissionError); ok {
@@ -37,13 +65,18 @@ func newListCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
 			}
 
 			opts := window.ListOptions{
-				AppFilter:    appFilter,
-				ScreenFilter: screenFilter,
+				AppFilter:     appFilter,
+				BundleID:      bundleID,
+				TitleRegex:    titleRegex,
+				NotTitle:      notTitle,
+				Where:         where,
+				ScreenFilter:  screenFilter,
+				ScreenAliases: root.ScreenAliases,
 			}
-			// When --app is explicitly specified, bypass the ignore list.
+			// When --app or --bundle-id is explicitly specified, bypass the ignore list.
 			// The user's intent to inspect a specific app takes precedence
 			// over the ignore_apps config (FR-006).
-			if appFilter == "" {
+			if appFilter == "" && bundleID == "" {
 				opts.IgnoreApps = root.IgnoreApps
 			}
 			// Only apply desktop filter when explicitly specified.
@@ -57,6 +90,16 @@ func newListCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
 
 			windows, err := window.List(ctx, svc, opts)
 			if err != nil {
+				var patErr *window.PatternError
+				if errors.As(err, &patErr) {
+					_ = f.PrintError(3, patErr.Error(), nil)
+					os.Exit(3)
+				}
+				var whereErr *window.WhereError
+				if errors.As(err, &whereErr) {
+					_ = f.PrintError(3, whereErr.Error(), nil)
+					os.Exit(3)
+				}
 				if errors.Is(err, context.DeadlineExceeded) {
 					_ = f.PrintError(6, "AX operation timed out", nil)
 					os.Exit(6)
@@ -64,13 +107,34 @@ func newListCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
 				return err
 			}
 
+			if format := newOutputFormat(root.Format); format == output.FormatMap || format == output.FormatSVG {
+				screens, err := svc.ListScreens(ctx)
+				if err != nil {
+					if errors.Is(err, context.DeadlineExceeded) {
+						_ = f.PrintError(6, "AX operation timed out", nil)
+						os.Exit(6)
+					}
+					return err
+				}
+				if format == output.FormatSVG {
+					return f.PrintWindowSVG(screens, windows)
+				}
+				return f.PrintWindowMap(screens, windows)
+			}
 			return f.PrintWindows(windows)
 		},
 	}
 
-	cmd.Flags().StringVar(&appFilter, "app", "", "filter by app name (case-insensitive, exact match)")
-	cmd.Flags().StringVar(&screenFilter, "screen", "", "filter by screen ID or name (exact match)")
+	cmd.Flags().StringVar(&appFilter, "app", "", "filter by app name (case-insensitive, exact match or glob like \"Google Chrome*\")")
+	cmd.Flags().StringVar(&bundleID, "bundle-id", "", "filter by bundle identifier (case-insensitive, e.g. com.apple.Safari)")
+	cmd.Flags().StringVar(&titleRegex, "title-regex", "", "filter by window title (regular expression)")
+	cmd.Flags().StringVar(&notTitle, "not-title", "", "exclude windows whose title contains this (case-insensitive)")
+	cmd.Flags().StringVar(&where, "where", "", "filter by expression over window fields (e.g. 'app == \"Safari\" && width > 800')")
+	cmd.Flags().StringVar(&screenFilter, "screen", "", "filter by screen ID, name, alias, #N or selector such as primary or not-primary (exact match)")
 	cmd.Flags().IntVar(&desktopFilter, "desktop", 0, "filter by desktop number (1-based, Mission Control order)")
+	cmd.Flags().StringVar(&sortSpec, "sort", "", "sort by comma-separated columns, prefix - for descending (e.g. app,title,-width)")
+	cmd.Flags().StringVar(&columnSpec, "columns", "", "comma-separated columns to show ("+strings.Join(output.ColumnNames(), ", ")+")")
+	cmd.Flags().BoolVar(&noHeader, "no-header", false, "omit the header row")
 
 	return cmd
 }
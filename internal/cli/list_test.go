@@ -1,6 +1,7 @@
 package cli_test
 
 import (
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
@@ -12,9 +13,9 @@ import (
 )
 
 var listTestWindows = []ax.Window{
-	{AppName: "Terminal", Title: "zsh", PID: 100, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
-	{AppName: "Safari", Title: "GitHub", PID: 200, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
-	{AppName: "Finder", Title: "Home", PID: 300, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
+	{ID: 1, AppName: "Terminal", Title: "zsh", PID: 100, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
+	{ID: 2, AppName: "Safari", Title: "GitHub", PID: 200, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
+	{ID: 3, AppName: "Finder", Title: "Home", PID: 300, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
 }
 
 // executeListCmdCapture はコマンド実行し、os.Stdout出力をキャプチャする
@@ -76,3 +77,99 @@ func TestListCmd_IgnoreNoAppFlag(t *testing.T) {
 		t.Errorf("expected Terminal in output, got:\n%s", output)
 	}
 }
+
+func TestListCmd_Where(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: listTestWindows}
+	output, err := executeListCmdCapture(t, svc, "", "list", "--where", `app != "Terminal" && title =~ "^G"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "GitHub") || strings.Contains(output, "Terminal") || strings.Contains(output, "Finder") {
+		t.Errorf("expected only the GitHub window, got:\n%s", output)
+	}
+}
+
+func TestListCmd_SortColumnsNoHeader(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: listTestWindows}
+	output, err := executeListCmdCapture(t, svc, "", "list", "--sort", "-app", "--columns", "app,title", "--no-header")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Terminal  zsh\nSafari    GitHub\nFinder    Home\n"
+	if output != want {
+		t.Errorf("got:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestListCmd_TemplateFormat(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: listTestWindows}
+	output, err := executeListCmdCapture(t, svc, "", "list", "--app", "Safari", "--format", "template={{.app_name}}|{{.title}}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output != "Safari|GitHub\n" {
+		t.Errorf("got %q", output)
+	}
+}
+
+func TestListCmd_MapFormat(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Screens: []ax.Screen{{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900, IsPrimary: true}},
+		Windows: []ax.Window{
+			{ID: 1, AppName: "Terminal", Title: "zsh", PID: 100, X: 0, Y: 0, Width: 720, Height: 900, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
+		},
+	}
+	output, err := executeListCmdCapture(t, svc, "", "list", "--format", "map")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"*Terminal", "#1 Built-in", "Screens:", `Terminal "zsh" (0, 0) 720x900`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestListCmd_SVGFormat(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Screens: []ax.Screen{{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900, IsPrimary: true}},
+		Windows: []ax.Window{
+			{ID: 1, AppName: "Terminal", Title: "zsh", PID: 100, X: 0, Y: 0, Width: 720, Height: 900, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
+		},
+	}
+	output, err := executeListCmdCapture(t, svc, "", "list", "--format", "svg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"<svg ", ">Terminal</text>", ">#1 Built-in 1440x900</text>", "</svg>"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestPresetShowCmd_SVGStoredScreens(t *testing.T) {
+	// the stored screens are drawn without querying the displays
+	svc := &ax.MockWindowService{ScreensErr: errors.New("no display")}
+	config := `presets:
+  - name: coding
+    screens:
+      - name: Built-in
+        x: 0
+        y: 0
+        width: 1440
+        height: 900
+    rules:
+      - app: Code
+        snap: left-half
+`
+	output, err := executeListCmdCapture(t, svc, config, "preset", "show", "coding", "--format", "svg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"<title>mado preset coding</title>", ">Code</text>", ">#1 Built-in 1440x900</text>"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output)
+		}
+	}
+}
@@ -19,11 +19,25 @@ import (
 func newMoveCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
 	var (
 		appFilter     string
+		bundleID      string
 		titleFilter   string
+		titleRegex    string
+		notTitle      string
+		where         string
+		idFilter      uint32
 		screenFilter  string
 		desktopFilter int
 		positionStr   string
 		sizeStr       string
+		snap          string
+		gap           int
+		margin        int
+		toScreen      string
+		moveByStr     string
+		resizeByStr   string
+		resizeFrom    string
+		clamp         bool
+		area          string
 		all           bool
 	)
 
@@ -34,8 +48,32 @@ func newMoveCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
 			f := output.New(newOutputFormat(root.Format), os.Stdout, os.Stderr)
 
 			// T030: exit 3 when neither --position nor --size is specified
-			if positionStr == "" && sizeStr == "" {
-				_ = f.PrintError(3, "--position or --size is required", nil)
+			if positionStr == "" && sizeStr == "" && snap == "" && toScreen == "" && moveByStr == "" && resizeByStr == "" && !clamp {
+				_ = f.PrintError(3, "--position, --size, --snap, --to-screen, --move-by, --resize-by or --clamp is required", nil)
+				os.Exit(3)
+			}
+			if moveByStr != "" && (positionStr != "" || snap != "") {
+				_ = f.PrintError(3, "--move-by cannot be combined with --position or --snap", nil)
+				os.Exit(3)
+			}
+			if resizeByStr != "" && (sizeStr != "" || snap != "") {
+				_ = f.PrintError(3, "--resize-by cannot be combined with --size or --snap", nil)
+				os.Exit(3)
+			}
+			if resizeFrom != "" && resizeByStr == "" {
+				_ = f.PrintError(3, "--resize-from requires --resize-by", nil)
+				os.Exit(3)
+			}
+			if toScreen != "" && (positionStr != "" || sizeStr != "") {
+				_ = f.PrintError(3, "--to-screen cannot be combined with --position or --size", nil)
+				os.Exit(3)
+			}
+			if snap != "" && (positionStr != "" || sizeStr != "") {
+				_ = f.PrintError(3, "--snap cannot be combined with --position or --size", nil)
+				os.Exit(3)
+			}
+			if snap == "" && (cmd.Flags().Changed("gap") || cmd.Flags().Changed("margin")) {
+				_ = f.PrintError(3, "--gap and --margin require --snap", nil)
 				os.Exit(3)
 			}
 
@@ -52,10 +90,26 @@ func newMoveCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
 			}
 
 			opts := window.MoveOptions{
-				AppFilter:    appFilter,
-				TitleFilter:  titleFilter,
-				ScreenFilter: screenFilter,
-				All:          all,
+				AppFilter:     appFilter,
+				BundleID:      bundleID,
+				TitleFilter:   titleFilter,
+				TitleRegex:    titleRegex,
+				NotTitle:      notTitle,
+				Where:         where,
+				ScreenFilter:  screenFilter,
+				ToScreen:      toScreen,
+				ScreenAliases: root.ScreenAliases,
+				ResizeFrom:    resizeFrom,
+				Clamp:         clamp,
+				Area:          area,
+				All:           all,
+			}
+			if cmd.Flags().Changed("id") {
+				if idFilter == 0 {
+					_ = f.PrintError(3, "invalid --id value: must be a positive integer", nil)
+					os.Exit(3)
+				}
+				opts.IDFilter = idFilter
 			}
 			// Only apply desktop filter when explicitly specified.
 			if cmd.Flags().Changed("desktop") {
@@ -88,12 +142,60 @@ func newMoveCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
 				opts.Size = &window.Size{W: w, H: h}
 			}
 
-			affected, err := window.Move(ctx, svc, opts)
+			if moveByStr != "" {
+				dx, dy, err := parseCoords(moveByStr)
+				if err != nil {
+					_ = f.PrintError(3, fmt.Sprintf("invalid --move-by value: %v", err), nil)
+					os.Exit(3)
+				}
+				opts.MoveBy = &window.Point{X: dx, Y: dy}
+			}
+
+			if resizeByStr != "" {
+				dw, dh, err := parseCoords(resizeByStr)
+				if err != nil {
+					_ = f.PrintError(3, fmt.Sprintf("invalid --resize-by value: %v", err), nil)
+					os.Exit(3)
+				}
+				opts.ResizeBy = &window.Size{W: dw, H: dh}
+			}
+			if err := window.ValidateResizeFrom(resizeFrom); err != nil {
+				_ = f.PrintError(3, fmt.Sprintf("invalid --resize-from value: %v", err), nil)
+				os.Exit(3)
+			}
+			if err := window.ValidateArea(area); err != nil {
+				_ = f.PrintError(3, fmt.Sprintf("invalid --area value: %v", err), nil)
+				os.Exit(3)
+			}
+
+			if snap != "" {
+				opts.Snap = &window.SnapOptions{Position: snap, Gap: gap, Margin: margin}
+				if err := window.ValidateSnap(*opts.Snap); err != nil {
+					_ = f.PrintError(3, fmt.Sprintf("invalid --snap value: %v", err), nil)
+					os.Exit(3)
+				}
+			}
+
+			before, affected, err := window.MoveTracked(ctx, svc, opts)
 			if err != nil {
+				// windows changed before the error can still be undone
+				if len(affected) > 0 {
+					recordHistory(cmd, nil, before, affected)
+				}
 				if errors.Is(err, context.DeadlineExceeded) {
 					_ = f.PrintError(6, "AX operation timed out", nil)
 					os.Exit(6)
 				}
+				var patErr *window.PatternError
+				if errors.As(err, &patErr) {
+					_ = f.PrintError(3, patErr.Error(), nil)
+					os.Exit(3)
+				}
+				var whereErr *window.WhereError
+				if errors.As(err, &whereErr) {
+					_ = f.PrintError(3, whereErr.Error(), nil)
+					os.Exit(3)
+				}
 				var fsErr *window.FullscreenError
 				if errors.As(err, &fsErr) {
 					_ = f.PrintError(5, err.Error(), nil)
@@ -106,7 +208,7 @@ func newMoveCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
 					os.Exit(7)
 				}
 				switch e := err.(type) {
-				case *ax.NotFoundError:
+				case *ax.NotFoundError, *window.ScreenNotFoundError:
 					_ = f.PrintError(4, e.Error(), nil)
 					os.Exit(4)
 				case *ax.AmbiguousTargetError:
@@ -117,18 +219,45 @@ func newMoveCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
 				}
 			}
 
+			recordHistory(cmd, nil, before, affected)
 			return f.PrintMoveResult(affected)
 		},
 	}
 
-	cmd.Flags().StringVar(&appFilter, "app", "", "filter by app name (case-insensitive, exact match)")
+	cmd.Flags().StringVar(&appFilter, "app", "", "filter by app name (case-insensitive, exact match or glob like \"Google Chrome*\")")
+	cmd.Flags().StringVar(&bundleID, "bundle-id", "", "filter by bundle identifier (case-insensitive, e.g. com.apple.Safari)")
 	cmd.Flags().StringVar(&titleFilter, "title", "", "filter by title (case-insensitive, partial match)")
-	cmd.Flags().StringVar(&screenFilter, "screen", "", "filter by screen ID or name")
+	cmd.Flags().StringVar(&titleRegex, "title-regex", "", "filter by title (regular expression)")
+	cmd.Flags().StringVar(&notTitle, "not-title", "", "exclude windows whose title contains this (case-insensitive)")
+	cmd.Flags().StringVar(&where, "where", "", "filter by expression over window fields (e.g. 'app == \"Safari\" && width > 800')")
+	cmd.Flags().Uint32Var(&idFilter, "id", 0, "target the window with this ID (see the ID column of mado list)")
+	cmd.Flags().StringVar(&screenFilter, "screen", "", "filter by screen ID, name, alias, #N or selector such as primary or not-primary")
 	cmd.Flags().IntVar(&desktopFilter, "desktop", 0, "scope operation to desktop number (1-based, Mission Control order)")
 	cmd.Flags().StringVar(&positionStr, "position", "", "target position x,y (global coordinates)")
 	cmd.Flags().StringVar(&sizeStr, "size", "", "target size width,height")
+	cmd.Flags().StringVar(&snap, "snap", "", "snap to a named position on the window's screen (e.g. left-half, right-third, top-right-quarter, center, maximize)")
+	cmd.Flags().IntVar(&gap, "gap", 0, "pixels between adjacent snapped windows (with --snap)")
+	cmd.Flags().IntVar(&margin, "margin", 0, "pixels between the screen edge and the snapped window (with --snap)")
+	cmd.Flags().StringVar(&toScreen, "to-screen", "", "move to another screen (ID, name, alias, #N, selector such as primary or largest, next, prev, left or right), keeping the relative placement")
+	cmd.Flags().StringVar(&moveByStr, "move-by", "", "move by dx,dy pixels from the current position (e.g. -50,0)")
+	cmd.Flags().StringVar(&resizeByStr, "resize-by", "", "resize by dw,dh pixels from the current size (negative to shrink)")
+	cmd.Flags().StringVar(&resizeFrom, "resize-from", "", "edge or corner that moves with --resize-by: "+strings.Join(window.ResizeAnchors(), "|")+" (default bottom-right)")
+	cmd.Flags().BoolVar(&clamp, "clamp", false, "keep the window within its screen, shrinking it if needed")
+	cmd.Flags().StringVar(&area, "area", window.AreaFrame, "part of the screen --snap, --to-screen and --clamp work within: frame|visible (without menu bar and Dock)")
 	cmd.Flags().BoolVar(&all, "all", false, "apply to all matching windows when multiple match")
 
+	_ = cmd.RegisterFlagCompletionFunc("snap", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return window.SnapPositions(), cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("resize-from", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return window.ResizeAnchors(), cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("area", cobra.FixedCompletions(
+		[]string{window.AreaFrame, window.AreaVisible}, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("to-screen", cobra.FixedCompletions(
+		append([]string{window.ScreenNext, window.ScreenPrev, window.ScreenLeft, window.ScreenRight}, window.ScreenSelectors()...),
+		cobra.ShellCompDirectiveNoFileComp))
+
 	return cmd
 }
 
@@ -6,34 +6,46 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 
 	"github.com/spf13/cobra"
 	"go.yaml.in/yaml/v4"
 
 	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/config"
 	"github.com/peacock0803sz/mado/internal/output"
 	"github.com/peacock0803sz/mado/internal/preset"
+	"github.com/peacock0803sz/mado/internal/profile"
 )
 
-// newPresetCmd creates the preset command group with apply/list/show/validate subcommands.
+// newPresetCmd creates the preset command group.
 func newPresetCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "preset",
 		Short: "Manage window layout presets",
-		Long:  "Apply, list, show, or validate window layout presets defined in the config file.",
+		Long:  "Apply, auto-apply, diff, check, record, list, show, validate, or edit window layout presets defined in the config file.",
 	}
 
 	cmd.AddCommand(newPresetApplyCmd(svc, flags))
+	cmd.AddCommand(newPresetAutoCmd(svc, flags))
+	cmd.AddCommand(newPresetCheckCmd(svc, flags))
+	cmd.AddCommand(newPresetCpCmd(flags))
+	cmd.AddCommand(newPresetDiffCmd(svc, flags))
+	cmd.AddCommand(newPresetEditCmd(flags))
 	cmd.AddCommand(newPresetListCmd(flags))
+	cmd.AddCommand(newPresetMvCmd(flags))
 	cmd.AddCommand(newPresetRecCmd(svc, flags))
-	cmd.AddCommand(newPresetShowCmd(flags))
+	cmd.AddCommand(newPresetRmCmd(flags))
+	cmd.AddCommand(newPresetShowCmd(svc, flags))
 	cmd.AddCommand(newPresetValidateCmd(flags))
 
 	return cmd
 }
 
 func newPresetApplyCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
-	return &cobra.Command{
+	var dryRun bool
+
+	cmd := &cobra.Command{
 		Use:   "apply <name>",
 		Short: "Apply a preset layout to matching windows",
 		Args:  cobra.ExactArgs(1),
@@ -53,17 +65,176 @@ func newPresetApplyCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
 			ctx, cancel := context.WithTimeout(cmd.Context(), flags.Timeout)
 			defer cancel()
 
-			outcome, err := preset.Apply(ctx, svc, flags.Presets, name, flags.IgnoreApps)
+			return applyPreset(ctx, cmd, args, f, svc, flags, name, dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the plan without moving any window")
+
+	return cmd
+}
+
+func newPresetAutoCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "auto",
+		Short: "Apply the preset of the profile matching the connected screens",
+		Long: `Detect the current display configuration and apply the preset of the best-matching
+profile in the config file. Use "mado profile detect" to print the fingerprint of the
+connected screens for a new profile.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f := output.New(newOutputFormat(flags.Format), os.Stdout, os.Stderr)
 
-			// stderr警告: ignoreされたルールをユーザーに通知
-			emitIgnoredWarnings(cmd.ErrOrStderr(), outcome)
+			if err := svc.CheckPermission(); err != nil {
+				msg := err.Error()
+				if permErr, ok := err.(*ax.PermissionError); ok {
+					msg = permErr.Error() + "\n\n" + permErr.Resolution()
+				}
+				_ = f.PrintError(2, msg, nil)
+				os.Exit(2)
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), flags.Timeout)
+			defer cancel()
+
+			screens, err := svc.ListScreens(ctx)
 			if err != nil {
-				return handleApplyError(f, err, outcome)
+				if errors.Is(err, context.DeadlineExceeded) {
+					_ = f.PrintError(6, "AX operation timed out", nil)
+					os.Exit(6)
+				}
+				return err
+			}
+			p, err := profile.Match(flags.Profiles, screens)
+			if err != nil {
+				_ = f.PrintError(4, err.Error(), nil)
+				os.Exit(4)
+			}
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "profile %q matched, applying preset %q\n", p.Name, p.Preset)
+
+			return applyPreset(ctx, cmd, args, f, svc, flags, p.Preset, dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the plan without moving any window")
+
+	return cmd
+}
+
+// applyPreset applies the named preset, or prints its plan when dryRun is set.
+func applyPreset(ctx context.Context, cmd *cobra.Command, args []string, f *output.Formatter, svc ax.WindowService, flags *RootFlags, name string, dryRun bool) error {
+	if dryRun {
+		outcome, err := preset.Plan(ctx, svc, flags.Presets, name, flags.IgnoreApps, flags.ScreenAliases)
+		if err != nil {
+			return handleApplyError(f, err, outcome)
+		}
+		return f.PrintPresetPlan(buildPlanResponse(name, outcome))
+	}
+
+	outcome, err := preset.Apply(ctx, svc, flags.Presets, name, flags.IgnoreApps, flags.ScreenAliases)
+	recordApplyHistory(cmd, args, outcome)
+
+	// stderr警告: ignoreされたルールをユーザーに通知
+	emitIgnoredWarnings(cmd.ErrOrStderr(), outcome)
+	if err != nil {
+		return handleApplyError(f, err, outcome)
+	}
+
+	return f.PrintPresetApplyResult(buildApplyResponse(name, outcome, true, nil))
+}
+
+func newPresetDiffCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <name>",
+		Short: "Show how far matching windows are from a preset layout",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f := output.New(newOutputFormat(flags.Format), os.Stdout, os.Stderr)
+			outcome := runPresetDiff(cmd, f, svc, flags, args[0])
+			return f.PrintPresetDiff(buildDiffResponse(outcome, nil))
+		},
+	}
+}
+
+func newPresetCheckCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
+	var tolerance int
+
+	cmd := &cobra.Command{
+		Use:   "check <name>",
+		Short: "Exit non-zero when windows drift from a preset layout",
+		Long: `Compare the current window frames with a preset without applying it.
+Exits with code 8 when any matching window is off by more than --tolerance pixels.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f := output.New(newOutputFormat(flags.Format), os.Stdout, os.Stderr)
+
+			if tolerance < 0 {
+				_ = f.PrintError(3, fmt.Sprintf("--tolerance must be >= 0, got %d", tolerance), nil)
+				os.Exit(3)
+			}
+
+			outcome := runPresetDiff(cmd, f, svc, flags, args[0])
+			for _, r := range outcome.Results {
+				if r.Err != nil {
+					_ = f.PrintError(1, r.Err.Error(), nil)
+					os.Exit(1)
+				}
 			}
 
-			return f.PrintPresetApplyResult(buildApplyResponse(name, outcome, true, nil))
+			resp := buildDiffResponse(outcome, &tolerance)
+			if resp.MaxDrift > tolerance {
+				resp.Success = false
+				resp.Error = &output.ErrorDetail{
+					Code:    8,
+					Message: fmt.Sprintf("layout drift %dpx exceeds tolerance %dpx", resp.MaxDrift, tolerance),
+				}
+				_ = f.PrintPresetCheck(resp)
+				os.Exit(8)
+			}
+			return f.PrintPresetCheck(resp)
 		},
 	}
+
+	cmd.Flags().IntVar(&tolerance, "tolerance", 0, "allowed drift in pixels per position/size component")
+
+	return cmd
+}
+
+// runPresetDiff checks permission and computes the drift of the named preset,
+// exiting with the matching code on error.
+func runPresetDiff(cmd *cobra.Command, f *output.Formatter, svc ax.WindowService, flags *RootFlags, name string) *preset.DiffOutcome {
+	if err := svc.CheckPermission(); err != nil {
+		msg := err.Error()
+		if permErr, ok := err.(*ax.PermissionError); ok {
+			msg = permErr.Error() + "\n\n" + permErr.Resolution()
+		}
+		_ = f.PrintError(2, msg, nil)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), flags.Timeout)
+	defer cancel()
+
+	outcome, err := preset.Diff(ctx, svc, flags.Presets, name, flags.IgnoreApps, flags.ScreenAliases)
+	if err != nil {
+		_ = handleApplyError(f, err, nil)
+	}
+	return outcome
+}
+
+// recordApplyHistory records the windows moved by a preset apply so that it can be undone.
+func recordApplyHistory(cmd *cobra.Command, args []string, outcome *preset.ApplyOutcome) {
+	if outcome == nil {
+		return
+	}
+	var before, after []ax.Window
+	for _, r := range outcome.Results {
+		before = append(before, r.Before...)
+		after = append(after, r.Affected...)
+	}
+	recordHistory(cmd, args, before, after)
 }
 
 // emitIgnoredWarnings writes warnings to stderr for rules skipped due to ignore_apps.
@@ -146,12 +317,16 @@ func buildApplyResponse(name string, outcome *preset.ApplyOutcome, success bool,
 
 func newPresetRecCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
 	var screen string
+	var save, replace bool
 
 	cmd := &cobra.Command{
 		Use:   "rec <name> [output-path]",
 		Short: "Record current window layout as a preset",
-		Long:  "Capture the current window positions and sizes and output them as a YAML preset definition.",
-		Args:  cobra.RangeArgs(1, 2),
+		Long: `Capture the current window positions and sizes and output them as a YAML preset definition.
+The connected screens are recorded under screens, so that preset show can draw the layout later.
+With --save the preset is added to the active config file instead, keeping its comments;
+--replace overwrites an existing preset with the same name.`,
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			f := output.New(newOutputFormat(flags.Format), os.Stdout, os.Stderr)
 			name := args[0]
@@ -160,6 +335,11 @@ func newPresetRecCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
 			if len(args) >= 2 {
 				outputPath = args[1]
 			}
+			save = save || replace
+			if save && len(args) >= 2 {
+				_ = f.PrintError(3, "--save and --replace cannot be combined with an output path", nil)
+				os.Exit(3)
+			}
 
 			if err := svc.CheckPermission(); err != nil {
 				msg := err.Error()
@@ -174,7 +354,8 @@ func newPresetRecCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
 			defer cancel()
 
 			p, err := preset.Record(ctx, svc, name, preset.RecordOptions{
-				Screen: screen,
+				Screen:        screen,
+				ScreenAliases: flags.ScreenAliases,
 			})
 			if err != nil {
 				if errors.Is(err, context.DeadlineExceeded) {
@@ -185,6 +366,26 @@ func newPresetRecCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
 				os.Exit(3)
 			}
 
+			if save {
+				path, err := config.SavePreset(*p, replace)
+				if err != nil {
+					msg := err.Error()
+					var exists *config.PresetExistsError
+					if errors.As(err, &exists) {
+						msg += " (use --replace to overwrite it)"
+					}
+					_ = f.PrintError(3, msg, nil)
+					os.Exit(3)
+				}
+				return f.PrintPresetSaved(output.PresetSaveResponse{
+					SchemaVersion: 1,
+					Success:       true,
+					Preset:        p.Name,
+					Path:          path,
+					Rules:         len(p.Rules),
+				})
+			}
+
 			data, err := yaml.Marshal(p)
 			if err != nil {
 				_ = f.PrintError(1, err.Error(), nil)
@@ -204,11 +405,145 @@ func newPresetRecCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&screen, "screen", "", "record only windows on the specified screen (name or ID)")
+	cmd.Flags().StringVar(&screen, "screen", "", "record only windows on the specified screen (name, ID, alias, #N or selector such as primary)")
+	cmd.Flags().BoolVar(&save, "save", false, "add the preset to the active config file")
+	cmd.Flags().BoolVar(&replace, "replace", false, "like --save, but overwrite an existing preset with the same name")
 
 	return cmd
 }
 
+func newPresetRmCmd(flags *RootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm <name>",
+		Aliases: []string{"delete"},
+		Short:   "Delete a preset from the config file",
+		Long:    "Delete a preset from the config file. A preset that a profile uses is not deleted.",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			f := output.New(newOutputFormat(flags.Format), os.Stdout, os.Stderr)
+			path, err := config.DeletePreset(args[0])
+			return printPresetChange(f, "rm", args[0], "", path, err)
+		},
+	}
+}
+
+func newPresetMvCmd(flags *RootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:     "mv <name> <new-name>",
+		Aliases: []string{"rename"},
+		Short:   "Rename a preset in the config file",
+		Long:    "Rename a preset in the config file. Profiles that use the preset are updated as well.",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			f := output.New(newOutputFormat(flags.Format), os.Stdout, os.Stderr)
+			path, err := config.RenamePreset(args[0], args[1])
+			return printPresetChange(f, "mv", args[0], args[1], path, err)
+		},
+	}
+}
+
+func newPresetCpCmd(flags *RootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:     "cp <name> <new-name>",
+		Aliases: []string{"copy"},
+		Short:   "Copy a preset under a new name in the config file",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			f := output.New(newOutputFormat(flags.Format), os.Stdout, os.Stderr)
+			path, err := config.CopyPreset(args[0], args[1])
+			return printPresetChange(f, "cp", args[0], args[1], path, err)
+		},
+	}
+}
+
+func newPresetEditCmd(flags *RootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit <name>",
+		Short: "Edit a single preset in $EDITOR",
+		Long: `Open the preset in $EDITOR (default vi) and write it back to the config file on save.
+The edited preset is validated first; on error the config file is left untouched and the
+edited copy is kept so it can be fixed and retried.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f := output.New(newOutputFormat(flags.Format), os.Stdout, os.Stderr)
+			name := args[0]
+
+			src, err := config.PresetSource(name)
+			if err != nil {
+				return printPresetChange(f, "edit", name, "", "", err)
+			}
+
+			tmp, err := os.CreateTemp("", "mado-preset-*.yaml")
+			if err != nil {
+				_ = f.PrintError(1, err.Error(), nil)
+				os.Exit(1)
+			}
+			_, err = tmp.Write(src)
+			if cerr := tmp.Close(); err == nil {
+				err = cerr
+			}
+			if err == nil {
+				err = runEditor(cmd, tmp.Name())
+			}
+			var edited []byte
+			if err == nil {
+				edited, err = os.ReadFile(tmp.Name())
+			}
+			if err != nil {
+				_ = os.Remove(tmp.Name())
+				_ = f.PrintError(1, err.Error(), nil)
+				os.Exit(1)
+			}
+
+			path, err := config.UpdatePreset(name, edited)
+			if err != nil {
+				err = fmt.Errorf("%w (edited preset kept in %s)", err, tmp.Name())
+			} else {
+				_ = os.Remove(tmp.Name())
+			}
+			return printPresetChange(f, "edit", name, "", path, err)
+		},
+	}
+}
+
+// runEditor opens path in $EDITOR, which may include arguments (e.g. "code --wait").
+func runEditor(cmd *cobra.Command, path string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	c := exec.CommandContext(cmd.Context(), "sh", "-c", editor+` "$1"`, "sh", path) //nolint:gosec // G204: $EDITOR is chosen by the user
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+// printPresetChange reports the result of a config file change made by rm, mv, cp or edit.
+// A missing preset exits with 4; other errors (name conflicts, validation) exit with 3.
+func printPresetChange(f *output.Formatter, action, name, newName, path string, err error) error {
+	if err != nil {
+		var notFound *preset.NotFoundError
+		if errors.As(err, &notFound) {
+			_ = f.PrintError(4, err.Error(), nil)
+			os.Exit(4)
+		}
+		_ = f.PrintError(3, err.Error(), nil)
+		os.Exit(3)
+	}
+	return f.PrintPresetChange(output.PresetChangeResponse{
+		SchemaVersion: 1,
+		Success:       true,
+		Action:        action,
+		Preset:        name,
+		NewName:       newName,
+		Path:          path,
+	})
+}
+
 func newPresetListCmd(flags *RootFlags) *cobra.Command {
 	return &cobra.Command{
 		Use:   "list",
@@ -220,17 +555,30 @@ func newPresetListCmd(flags *RootFlags) *cobra.Command {
 	}
 }
 
-func newPresetShowCmd(flags *RootFlags) *cobra.Command {
-	return &cobra.Command{
+func newPresetShowCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
+	var showMap bool
+
+	cmd := &cobra.Command{
 		Use:   "show <name>",
 		Short: "Show details of a preset",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+		Long: `Show the rules of a preset.
+With --map, draw where the preset would place the current windows on a map of the
+screens instead; this needs Accessibility permission.
+With --format svg, draw the frames of the rules as an SVG image. The screens stored
+with the preset are used when it has any, so no display or permission is needed.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
 			f := output.New(newOutputFormat(flags.Format), os.Stdout, os.Stderr)
 			name := args[0]
 
+			if showMap {
+				return showPresetMap(cmd, f, svc, flags, name)
+			}
 			for _, p := range flags.Presets {
 				if p.Name == name {
+					if newOutputFormat(flags.Format) == output.FormatSVG {
+						return showPresetSVG(cmd, f, svc, flags, p)
+					}
 					return f.PrintPresetShow(p)
 				}
 			}
@@ -240,6 +588,91 @@ func newPresetShowCmd(flags *RootFlags) *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&showMap, "map", false, "draw the windows at their preset frames on a map of the screens")
+
+	return cmd
+}
+
+// showPresetMap draws the windows matched by the named preset at their target frames.
+// Windows the preset does not move are drawn where they are.
+func showPresetMap(cmd *cobra.Command, f *output.Formatter, svc ax.WindowService, flags *RootFlags, name string) error {
+	if err := svc.CheckPermission(); err != nil {
+		msg := err.Error()
+		if permErr, ok := err.(*ax.PermissionError); ok {
+			msg = permErr.Error() + "\n\n" + permErr.Resolution()
+		}
+		_ = f.PrintError(2, msg, nil)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), flags.Timeout)
+	defer cancel()
+
+	outcome, err := preset.Plan(ctx, svc, flags.Presets, name, flags.IgnoreApps, flags.ScreenAliases)
+	if err != nil {
+		return handleApplyError(f, err, outcome)
+	}
+	windows, err := svc.ListWindows(ctx)
+	if err != nil {
+		return handleApplyError(f, err, nil)
+	}
+	screens, err := svc.ListScreens(ctx)
+	if err != nil {
+		return handleApplyError(f, err, nil)
+	}
+
+	targets := make(map[uint32]ax.Window)
+	for _, r := range outcome.Results {
+		for _, w := range r.Affected {
+			targets[w.ID] = w
+		}
+	}
+	for i, w := range windows {
+		if t, ok := targets[w.ID]; ok {
+			windows[i] = t
+		}
+	}
+	return f.PrintWindowMap(screens, windows)
+}
+
+// showPresetSVG draws the frames of the rules of p on its stored screens, or on the
+// connected screens when it has none. Rules whose frame depends on the window are
+// reported on stderr and left out.
+func showPresetSVG(cmd *cobra.Command, f *output.Formatter, svc ax.WindowService, flags *RootFlags, p preset.Preset) error {
+	screens := p.AxScreens()
+	if len(screens) == 0 {
+		ctx, cancel := context.WithTimeout(cmd.Context(), flags.Timeout)
+		defer cancel()
+
+		var err error
+		if screens, err = svc.ListScreens(ctx); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				_ = f.PrintError(6, "AX operation timed out", nil)
+				os.Exit(6)
+			}
+			return err
+		}
+	}
+
+	ruleFrames, err := preset.Layout(p, screens, flags.ScreenAliases)
+	if err != nil {
+		_ = f.PrintError(1, err.Error(), nil)
+		os.Exit(1)
+	}
+
+	var frames []output.LayoutFrame
+	for _, r := range ruleFrames {
+		switch r.Reason {
+		case "depends_on_window":
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: preset rule[%d] (app: %q) not drawn: its frame depends on the window\n", r.RuleIndex, r.AppFilter)
+		case "no_screen":
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: preset rule[%d] (app: %q) not drawn: its screen was not found\n", r.RuleIndex, r.AppFilter)
+		default:
+			frames = append(frames, output.LayoutFrame{Label: r.AppFilter, Detail: r.Title, Rect: r.Frame})
+		}
+	}
+	return f.PrintLayoutSVG("mado preset "+p.Name, screens, frames)
 }
 
 func newPresetValidateCmd(flags *RootFlags) *cobra.Command {
@@ -253,3 +686,68 @@ func newPresetValidateCmd(flags *RootFlags) *cobra.Command {
 		},
 	}
 }
+
+func buildPlanResponse(name string, outcome *preset.ApplyOutcome) output.PresetPlanResponse {
+	resp := output.PresetPlanResponse{
+		SchemaVersion: 1,
+		Success:       true,
+		Preset:        name,
+		DryRun:        true,
+		Rules:         make([]output.PresetPlanRule, 0, len(outcome.Results)),
+	}
+
+	for _, r := range outcome.Results {
+		rule := output.PresetPlanRule{
+			RuleIndex: r.RuleIndex,
+			AppFilter: r.AppFilter,
+			Windows:   make([]output.PresetPlanWindow, 0, len(r.Affected)),
+			Reason:    r.Reason,
+		}
+		for i, w := range r.Affected {
+			rule.Windows = append(rule.Windows, output.PresetPlanWindow{
+				Window: r.Before[i],
+				Target: w.Frame(),
+			})
+		}
+		if r.Err != nil {
+			rule.Error = r.Err.Error()
+		}
+		resp.Rules = append(resp.Rules, rule)
+	}
+
+	return resp
+}
+
+func buildDiffResponse(outcome *preset.DiffOutcome, tolerance *int) output.PresetDiffResponse {
+	resp := output.PresetDiffResponse{
+		SchemaVersion: 1,
+		Success:       true,
+		Preset:        outcome.PresetName,
+		MaxDrift:      outcome.MaxDrift(),
+		Tolerance:     tolerance,
+		Rules:         make([]output.PresetDiffRule, 0, len(outcome.Results)),
+	}
+
+	for _, r := range outcome.Results {
+		rule := output.PresetDiffRule{
+			RuleIndex: r.RuleIndex,
+			AppFilter: r.AppFilter,
+			Windows:   make([]output.PresetDiffWindow, 0, len(r.Windows)),
+			Reason:    r.Reason,
+		}
+		for _, d := range r.Windows {
+			rule.Windows = append(rule.Windows, output.PresetDiffWindow{
+				Window: d.Window,
+				Target: d.Target,
+				Delta:  d.Delta(),
+				Drift:  d.Max(),
+			})
+		}
+		if r.Err != nil {
+			rule.Error = r.Err.Error()
+		}
+		resp.Rules = append(resp.Rules, rule)
+	}
+
+	return resp
+}
@@ -104,8 +104,8 @@ func TestPresetRec_MissingArgs(t *testing.T) {
 func TestPresetRec_Stdout(t *testing.T) {
 	svc := &ax.MockWindowService{
 		Windows: []ax.Window{
-			{AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
-			{AppName: "Terminal", Title: "zsh", PID: 2, X: 960, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
+			{ID: 1, AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
+			{ID: 2, AppName: "Terminal", Title: "zsh", PID: 2, X: 960, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
 		},
 	}
 
@@ -135,7 +135,7 @@ func TestPresetRec_Stdout(t *testing.T) {
 func TestPresetRec_ToFile(t *testing.T) {
 	svc := &ax.MockWindowService{
 		Windows: []ax.Window{
-			{AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
+			{ID: 3, AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
 		},
 	}
 
@@ -166,3 +166,153 @@ func TestPresetRec_ToFile(t *testing.T) {
 		t.Errorf("file missing Code app, got:\n%s", content)
 	}
 }
+
+func TestPresetRec_Save(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: []ax.Window{
+			{ID: 4, AppName: "Safari", Title: "GitHub", PID: 1, X: 0, Y: 0, Width: 1280, Height: 1080, State: ax.StateNormal},
+		},
+	}
+	config := "# my presets\n" + validPresetConfig
+
+	err := executePresetCmd(t, svc, config, "preset", "rec", "browsing", "--save")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(os.Getenv("MADO_CONFIG"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{"# my presets", "name: coding", "name: browsing", "app: Safari"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("config missing %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestPresetCheck_WithinTolerance(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: []ax.Window{
+		{ID: 1, AppName: "Code", Title: "main.go", X: 2, Y: 0, Width: 960, Height: 1080},
+		{ID: 2, AppName: "Terminal", Title: "zsh", X: 960, Y: 3, Width: 960, Height: 1080},
+	}}
+	err := executePresetCmd(t, svc, validPresetConfig, "preset", "check", "coding", "--tolerance", "4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPresetDiff(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: []ax.Window{
+		{ID: 1, AppName: "Code", Title: "main.go", X: 100, Y: 100, Width: 800, Height: 600},
+	}}
+	out, err := executeListCmdCapture(t, svc, validPresetConfig, "preset", "diff", "coding")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Δ(-100, -100) +160x+480") {
+		t.Errorf("diff should show the Code delta, got:\n%s", out)
+	}
+	if !strings.Contains(out, "rule[1] Terminal: skipped (no_match)") {
+		t.Errorf("diff should report the unmatched rule, got:\n%s", out)
+	}
+}
+
+func TestPresetMvAndRm(t *testing.T) {
+	svc := &ax.MockWindowService{}
+	if err := executePresetCmd(t, svc, validPresetConfig, "preset", "mv", "coding", "editing"); err != nil {
+		t.Fatalf("mv: unexpected error: %v", err)
+	}
+	cfgFile := os.Getenv("MADO_CONFIG")
+	data, _ := os.ReadFile(cfgFile)
+	if !strings.Contains(string(data), "name: editing") {
+		t.Fatalf("preset was not renamed:\n%s", data)
+	}
+
+	cmd := cli.NewRootCmd(svc)
+	cmd.SetArgs([]string{"preset", "rm", "editing"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("rm: unexpected error: %v", err)
+	}
+	data, _ = os.ReadFile(cfgFile)
+	if strings.Contains(string(data), "name: editing") {
+		t.Errorf("preset was not removed:\n%s", data)
+	}
+}
+
+func TestPresetEdit(t *testing.T) {
+	// the editor script widens the Code window
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	script := "#!/bin/sh\nsed 's/size: \\[960, 1080\\]/size: [1280, 1080]/' \"$1\" > \"$1.new\" && mv \"$1.new\" \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", editor)
+
+	out, err := executeListCmdCapture(t, &ax.MockWindowService{}, validPresetConfig, "preset", "edit", "coding")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, `Preset "coding" updated`) {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	data, _ := os.ReadFile(os.Getenv("MADO_CONFIG"))
+	if !strings.Contains(string(data), "size: [1280, 1080]") {
+		t.Errorf("edited preset was not saved:\n%s", data)
+	}
+}
+
+func TestPresetAutoCmd_DryRun(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: []ax.Window{
+			{ID: 1, AppName: "Code", Title: "main.go", PID: 100, State: ax.StateNormal, Width: 800, Height: 600, ScreenID: 1},
+		},
+		Screens: []ax.Screen{
+			{ID: 1, Name: "Built-in", Width: 1440, Height: 900, IsPrimary: true},
+			{ID: 2, Name: "DELL U2720Q", X: 1440, Width: 2560, Height: 1440},
+		},
+	}
+	config := `presets:
+  - name: solo
+    rules:
+      - app: Code
+        snap: maximize
+  - name: docked
+    rules:
+      - app: Code
+        screen: DELL U2720Q
+        snap: left-half
+profiles:
+  - name: laptop
+    preset: solo
+    screens:
+      - name: Built-in
+  - name: desk
+    preset: docked
+    screens:
+      - name: Built-in
+      - width: 2560
+        height: 1440
+`
+	out, err := executeListCmdCapture(t, svc, config, "preset", "auto", "--dry-run", "--format", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, `"preset": "docked"`) {
+		t.Errorf("expected the docked preset to be planned, got:\n%s", out)
+	}
+}
+
+func TestProfileDetectCmd(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Screens: []ax.Screen{{ID: 1, Name: "Built-in", Width: 1440, Height: 900, IsPrimary: true}},
+	}
+	out, err := executeListCmdCapture(t, svc, "format: text\n", "profile", "detect")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "- name: Built-in\n      width: 1440\n      height: 900\n") {
+		t.Errorf("expected the fingerprint of Built-in, got:\n%s", out)
+	}
+}
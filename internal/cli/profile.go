@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/output"
+	"github.com/peacock0803sz/mado/internal/profile"
+)
+
+// newProfileCmd creates the profile command group.
+func newProfileCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Inspect display profiles",
+		Long: `Display profiles map a set of connected screens to a preset, which
+"mado preset auto" applies.`,
+	}
+
+	cmd.AddCommand(newProfileDetectCmd(svc, flags))
+
+	return cmd
+}
+
+func newProfileDetectCmd(svc ax.WindowService, flags *RootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "detect",
+		Short: "Print the fingerprint of the connected screens",
+		Long: `Print the fingerprint (count, names and sizes) of the connected screens as a
+profile entry to paste under profiles: in the config file, and the profile it
+matches, if any.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			f := output.New(newOutputFormat(flags.Format), os.Stdout, os.Stderr)
+
+			if err := svc.CheckPermission(); err != nil {
+				msg := err.Error()
+				if permErr, ok := err.(*ax.PermissionError); ok {
+					msg = permErr.Error() + "\n\n" + permErr.Resolution()
+				}
+				_ = f.PrintError(2, msg, nil)
+				os.Exit(2)
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), flags.Timeout)
+			defer cancel()
+
+			screens, err := svc.ListScreens(ctx)
+			if err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					_ = f.PrintError(6, "AX operation timed out", nil)
+					os.Exit(6)
+				}
+				return err
+			}
+
+			resp := output.ProfileDetectResponse{
+				SchemaVersion: 1,
+				Success:       true,
+				Screens:       profile.Fingerprint(screens),
+			}
+			if p, err := profile.Match(flags.Profiles, screens); err == nil {
+				resp.Profile, resp.Preset = p.Name, p.Preset
+			}
+			return f.PrintProfileDetect(resp)
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/layout"
+	"github.com/peacock0803sz/mado/internal/output"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// newRescueCmd creates the rescue subcommand.
+func newRescueCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
+	var (
+		appFilter  string
+		target     string
+		minVisible float64
+		dryRun     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rescue",
+		Short: "Move off-screen windows back onto a screen",
+		Long: `Find windows that are entirely or mostly off-screen, for example left at the
+coordinates of a disconnected display, and move them onto the nearest or the primary
+screen. Windows larger than the screen are shrunk to fit.
+
+A window is rescued when less than --min-visible of its area is on a screen.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			f := output.New(newOutputFormat(root.Format), os.Stdout, os.Stderr)
+
+			opts := layout.RescueOptions{
+				Filter:     window.ListOptions{AppFilter: appFilter},
+				Target:     layout.RescueTarget(target),
+				MinVisible: minVisible,
+				DryRun:     dryRun,
+			}
+			if err := opts.Validate(); err != nil {
+				_ = f.PrintError(3, err.Error(), nil)
+				os.Exit(3)
+			}
+			// As with list, an explicit --app bypasses the ignore list.
+			if appFilter == "" {
+				opts.Filter.IgnoreApps = root.IgnoreApps
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), root.Timeout)
+			defer cancel()
+
+			if err := svc.CheckPermission(); err != nil {
+				msg := err.Error()
+				if permErr, ok := err.(*ax.PermissionError); ok {
+					msg = permErr.Error() + "\n\n" + permErr.Resolution()
+				}
+				_ = f.PrintError(2, msg, nil)
+				os.Exit(2)
+			}
+
+			plan, err := layout.Rescue(ctx, svc, opts)
+			if err != nil {
+				// windows changed before the error can still be undone
+				var partialErr *ax.PartialSuccessError
+				partial := errors.As(err, &partialErr)
+				if partial {
+					plan = appliedRescues(plan, partialErr.Affected)
+					recordHistory(cmd, nil, rescuedWindows(plan), partialErr.Affected)
+				}
+				if errors.Is(err, context.DeadlineExceeded) {
+					_ = f.PrintError(6, "AX operation timed out", nil)
+					os.Exit(6)
+				}
+				if partial {
+					resp := buildRescueResponse(dryRun, plan)
+					resp.Success = false
+					resp.Error = &output.ErrorDetail{Code: 7, Message: partialErr.Error()}
+					_ = f.PrintRescueResult(resp)
+					os.Exit(7)
+				}
+				return err
+			}
+
+			if !dryRun {
+				after := make([]ax.Window, len(plan))
+				for i, r := range plan {
+					w := r.Window
+					w.X, w.Y, w.Width, w.Height = r.Frame.X, r.Frame.Y, r.Frame.W, r.Frame.H
+					after[i] = w
+				}
+				recordHistory(cmd, nil, rescuedWindows(plan), after)
+			}
+			return f.PrintRescueResult(buildRescueResponse(dryRun, plan))
+		},
+	}
+
+	cmd.Flags().StringVar(&appFilter, "app", "", "only rescue windows of this app (case-insensitive, exact match or glob)")
+	cmd.Flags().StringVar(&target, "to", string(layout.RescueNearest), "screen to move windows onto: nearest|primary")
+	cmd.Flags().Float64Var(&minVisible, "min-visible", layout.DefaultMinVisible, "share of a window (0-1) that must be on screen to leave it alone")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the plan without moving any window")
+
+	_ = cmd.RegisterFlagCompletionFunc("to", cobra.FixedCompletions(
+		[]string{string(layout.RescueNearest), string(layout.RescuePrimary)}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+func buildRescueResponse(dryRun bool, plan []layout.Rescued) output.RescueResponse {
+	resp := output.RescueResponse{
+		SchemaVersion: 1,
+		Success:       true,
+		DryRun:        dryRun,
+		Rescued:       make([]output.RescuePlacement, 0, len(plan)),
+	}
+	for _, r := range plan {
+		resp.Rescued = append(resp.Rescued, output.RescuePlacement{
+			Window:  r.Window,
+			Target:  r.Frame,
+			Screen:  r.Screen.Name,
+			Visible: r.Visible,
+		})
+	}
+	return resp
+}
+
+// appliedRescues returns the entries of plan whose window is in affected, that is
+// those carried out before a partial failure.
+func appliedRescues(plan []layout.Rescued, affected []ax.Window) []layout.Rescued {
+	moved := make(map[uint32]bool, len(affected))
+	for _, w := range affected {
+		moved[w.ID] = true
+	}
+	var applied []layout.Rescued
+	for _, r := range plan {
+		if moved[r.Window.ID] {
+			applied = append(applied, r)
+		}
+	}
+	return applied
+}
+
+// rescuedWindows returns the windows of plan with their frames before the rescue.
+func rescuedWindows(plan []layout.Rescued) []ax.Window {
+	windows := make([]ax.Window, len(plan))
+	for i, r := range plan {
+		windows[i] = r.Window
+	}
+	return windows
+}
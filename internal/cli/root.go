@@ -11,14 +11,18 @@ import (
 	"github.com/peacock0803sz/mado/internal/config"
 	"github.com/peacock0803sz/mado/internal/output"
 	"github.com/peacock0803sz/mado/internal/preset"
+	"github.com/peacock0803sz/mado/internal/profile"
+	"github.com/peacock0803sz/mado/internal/window"
 )
 
 // RootFlags holds the global flags for the root command.
 type RootFlags struct {
-	Format     string
-	Timeout    time.Duration
-	Presets    []preset.Preset
-	IgnoreApps []string
+	Format        string
+	Timeout       time.Duration
+	Presets       []preset.Preset
+	Profiles      []profile.Profile
+	IgnoreApps    []string
+	ScreenAliases []window.ScreenAlias
 }
 
 // NewRootCmd creates the root command.
@@ -37,8 +41,8 @@ func NewRootCmd(svc ax.WindowService) *cobra.Command {
 		Short: "macOS window management CLI",
 		Long: `mado — a CLI tool for managing macOS windows.
 
-Commands that require Accessibility permission: list, move, preset apply, preset rec
-Commands that do not require permission: help, version, completion, preset list, preset show, preset validate`,
+Commands that require Accessibility permission: list, move, tile, rescue, screens, undo, redo, preset apply, preset auto, preset diff, preset check, preset rec, preset show --map, profile detect
+Commands that do not require permission: help, version, completion, history, preset list, preset show, preset validate, preset rm, preset mv, preset cp, preset edit`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
@@ -54,22 +58,35 @@ Commands that do not require permission: help, version, completion, preset list,
 			if !cmd.Root().PersistentFlags().Changed("format") {
 				flags.Format = cfg.Format
 			}
+			if _, err := output.ParseFormat(flags.Format); err != nil {
+				_ = output.New(output.FormatText, os.Stdout, os.Stderr).PrintError(3, err.Error(), nil)
+				os.Exit(3)
+			}
 			if !cmd.Root().PersistentFlags().Changed("timeout") {
 				flags.Timeout = cfg.Timeout
 			}
 			flags.Presets = cfg.Presets
+			flags.Profiles = cfg.Profiles
 			flags.IgnoreApps = cfg.IgnoreApps
+			flags.ScreenAliases = cfg.ScreenAliases
 			return nil
 		},
 	}
 
 	// global flags (CLI flags override config file values)
-	root.PersistentFlags().StringVar(&flags.Format, "format", def.Format, "output format (text|json)")
+	root.PersistentFlags().StringVar(&flags.Format, "format", def.Format, "output format (text|json|ndjson|csv|tsv|yaml|map|svg|template=<go template>)")
 	root.PersistentFlags().DurationVar(&flags.Timeout, "timeout", def.Timeout, "AX operation timeout")
 
 	root.AddCommand(newListCmd(svc, flags))
 	root.AddCommand(newMoveCmd(svc, flags))
+	root.AddCommand(newTileCmd(svc, flags))
+	root.AddCommand(newRescueCmd(svc, flags))
+	root.AddCommand(newScreensCmd(svc, flags))
+	root.AddCommand(newUndoCmd(svc, flags))
+	root.AddCommand(newRedoCmd(svc, flags))
+	root.AddCommand(newHistoryCmd(flags))
 	root.AddCommand(newPresetCmd(svc, flags))
+	root.AddCommand(newProfileCmd(svc, flags))
 	root.AddCommand(newVersionCmd())
 	root.AddCommand(newCompletionCmd(root))
 
@@ -91,9 +108,11 @@ func skipConfigLoad(cmd *cobra.Command) bool {
 }
 
 // newOutputFormat converts a flag string to an output.Format value.
+// Invalid values fall back to text; they are rejected when the config is loaded.
 func newOutputFormat(s string) output.Format {
-	if s == "json" {
-		return output.FormatJSON
+	f, err := output.ParseFormat(s)
+	if err != nil {
+		return output.FormatText
 	}
-	return output.FormatText
+	return f
 }
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/output"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// newScreensCmd creates the screens subcommand.
+func newScreensCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "screens",
+		Short: "List connected screens",
+		Long: `List connected screens with their bounds, arrangement and window counts.
+
+Screens are ordered left-to-right, then top-to-bottom. The index can be used
+as a screen reference of the form "#N", e.g. "screen: '#2'" in a preset rule.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), root.Timeout)
+			defer cancel()
+
+			f := output.New(newOutputFormat(root.Format), os.Stdout, os.Stderr)
+
+			if err := svc.CheckPermission(); err != nil {
+				msg := err.Error()
+				if permErr, ok := err.(*ax.PermissionError); ok {
+					msg = permErr.Error() + "\n\n" + permErr.Resolution()
+				}
+				_ = f.PrintError(2, msg, nil)
+				os.Exit(2)
+			}
+
+			screens, err := window.Screens(ctx, svc, window.ScreensOptions{IgnoreApps: root.IgnoreApps})
+			if err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					_ = f.PrintError(6, "AX operation timed out", nil)
+					os.Exit(6)
+				}
+				return err
+			}
+
+			return f.PrintScreens(screens)
+		},
+	}
+
+	return cmd
+}
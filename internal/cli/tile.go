@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/layout"
+	"github.com/peacock0803sz/mado/internal/output"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// newTileCmd creates the tile subcommand.
+func newTileCmd(svc ax.WindowService, root *RootFlags) *cobra.Command {
+	var (
+		appFilter     string
+		screenFilter  string
+		desktopFilter int
+		layoutName    string
+		masterRatio   float64
+		gap           int
+		margin        int
+		orientation   string
+		dryRun        bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tile",
+		Short: "Arrange matching windows into an automatic layout",
+		Long: `Arrange all matching windows into a computed layout, tiling each screen independently.
+
+Layouts: columns, rows, grid, master-stack, bsp. The frontmost window is the master.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			f := output.New(newOutputFormat(root.Format), os.Stdout, os.Stderr)
+
+			params := layout.Params{
+				Kind:        layout.Kind(layoutName),
+				MasterRatio: masterRatio,
+				Gap:         gap,
+				Margin:      margin,
+				Orientation: layout.Orientation(orientation),
+			}
+			if err := params.Validate(); err != nil {
+				_ = f.PrintError(3, err.Error(), nil)
+				os.Exit(3)
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), root.Timeout)
+			defer cancel()
+
+			if err := svc.CheckPermission(); err != nil {
+				msg := err.Error()
+				if permErr, ok := err.(*ax.PermissionError); ok {
+					msg = permErr.Error() + "\n\n" + permErr.Resolution()
+				}
+				_ = f.PrintError(2, msg, nil)
+				os.Exit(2)
+			}
+
+			filter := window.ListOptions{
+				AppFilter:     appFilter,
+				ScreenFilter:  screenFilter,
+				ScreenAliases: root.ScreenAliases,
+			}
+			// As with list, an explicit --app bypasses the ignore list.
+			if appFilter == "" {
+				filter.IgnoreApps = root.IgnoreApps
+			}
+			if cmd.Flags().Changed("desktop") {
+				if desktopFilter < 1 {
+					_ = f.PrintError(3, "invalid --desktop value: must be a positive integer", nil)
+					os.Exit(3)
+				}
+				filter.DesktopFilter = desktopFilter
+			}
+
+			plan, err := layout.Tile(ctx, svc, layout.TileOptions{
+				Filter: filter,
+				Params: params,
+				DryRun: dryRun,
+			})
+			if err != nil {
+				// windows changed before the error can still be undone
+				var partialErr *ax.PartialSuccessError
+				partial := errors.As(err, &partialErr)
+				if partial {
+					plan = appliedPlacements(plan, partialErr.Affected)
+					recordHistory(cmd, nil, planWindows(plan), partialErr.Affected)
+				}
+				if errors.Is(err, context.DeadlineExceeded) {
+					_ = f.PrintError(6, "AX operation timed out", nil)
+					os.Exit(6)
+				}
+				var notFound *ax.NotFoundError
+				if errors.As(err, &notFound) {
+					_ = f.PrintError(4, notFound.Error(), nil)
+					os.Exit(4)
+				}
+				if partial {
+					resp := buildTileResponse(layoutName, dryRun, plan)
+					resp.Success = false
+					resp.Error = &output.ErrorDetail{Code: 7, Message: partialErr.Error()}
+					_ = f.PrintTileResult(resp)
+					os.Exit(7)
+				}
+				return err
+			}
+
+			if !dryRun {
+				after := make([]ax.Window, len(plan))
+				for i, p := range plan {
+					w := p.Window
+					w.X, w.Y, w.Width, w.Height = p.Frame.X, p.Frame.Y, p.Frame.W, p.Frame.H
+					after[i] = w
+				}
+				recordHistory(cmd, nil, planWindows(plan), after)
+			}
+			return f.PrintTileResult(buildTileResponse(layoutName, dryRun, plan))
+		},
+	}
+
+	cmd.Flags().StringVar(&layoutName, "layout", string(layout.KindColumns), "layout: columns|rows|grid|master-stack|bsp")
+	cmd.Flags().StringVar(&appFilter, "app", "", "filter by app name (case-insensitive, exact match or glob like \"Google Chrome*\")")
+	cmd.Flags().StringVar(&screenFilter, "screen", "", "filter by screen ID, name, alias, #N or selector such as primary or not-primary (exact match)")
+	cmd.Flags().IntVar(&desktopFilter, "desktop", 0, "filter by desktop number (1-based, Mission Control order)")
+	cmd.Flags().Float64Var(&masterRatio, "master-ratio", 0.5, "share of the screen given to the master window (master-stack)")
+	cmd.Flags().IntVar(&gap, "gap", 0, "pixels between adjacent windows")
+	cmd.Flags().IntVar(&margin, "margin", 0, "pixels between the screen edge and windows")
+	cmd.Flags().StringVar(&orientation, "orientation", string(layout.Horizontal), "direction of the first split: horizontal|vertical")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the plan without moving any window")
+
+	_ = cmd.RegisterFlagCompletionFunc("layout", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		names := make([]string, len(layout.Kinds))
+		for i, k := range layout.Kinds {
+			names[i] = string(k)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("orientation", cobra.FixedCompletions(
+		[]string{string(layout.Horizontal), string(layout.Vertical)}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+func buildTileResponse(layoutName string, dryRun bool, plan []layout.Placement) output.TileResponse {
+	resp := output.TileResponse{
+		SchemaVersion: 1,
+		Success:       true,
+		Layout:        layoutName,
+		DryRun:        dryRun,
+		Placements:    make([]output.TilePlacement, 0, len(plan)),
+	}
+	for _, p := range plan {
+		resp.Placements = append(resp.Placements, output.TilePlacement{Window: p.Window, Target: p.Frame})
+	}
+	return resp
+}
+
+// appliedPlacements returns the placements of plan whose window is in affected,
+// that is those carried out before a partial failure.
+func appliedPlacements(plan []layout.Placement, affected []ax.Window) []layout.Placement {
+	moved := make(map[uint32]bool, len(affected))
+	for _, w := range affected {
+		moved[w.ID] = true
+	}
+	var applied []layout.Placement
+	for _, p := range plan {
+		if moved[p.Window.ID] {
+			applied = append(applied, p)
+		}
+	}
+	return applied
+}
+
+// planWindows returns the windows of plan with their frames before tiling.
+func planWindows(plan []layout.Placement) []ax.Window {
+	windows := make([]ax.Window, len(plan))
+	for i, p := range plan {
+		windows[i] = p.Window
+	}
+	return windows
+}
@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"go.yaml.in/yaml/v4"
 
 	"github.com/peacock0803sz/mado/internal/preset"
+	"github.com/peacock0803sz/mado/internal/profile"
+	"github.com/peacock0803sz/mado/internal/window"
 )
 
 // Config is the structure of the mado configuration file.
@@ -20,18 +23,26 @@ type Config struct {
 	Timeout    time.Duration
 	Format     string
 	Presets    []preset.Preset
+	Profiles   []profile.Profile
 	IgnoreApps []string
+	// ScreenAliases name screens for use as screen references (the screens section).
+	ScreenAliases []window.ScreenAlias
 }
 
 // rawConfig is an intermediate structure for YAML parsing.
 // time.Duration cannot be decoded directly from YAML, so it is received as a string.
 type rawConfig struct {
-	Timeout    string          `yaml:"timeout"`
-	Format     string          `yaml:"format"`
-	Presets    []preset.Preset `yaml:"presets"`
-	IgnoreApps []string        `yaml:"ignore_apps"`
+	Timeout    string               `yaml:"timeout"`
+	Format     string               `yaml:"format"`
+	Presets    []preset.Preset      `yaml:"presets"`
+	Profiles   []profile.Profile    `yaml:"profiles"`
+	IgnoreApps []string             `yaml:"ignore_apps"`
+	Screens    []window.ScreenAlias `yaml:"screens"`
 }
 
+// formats are the accepted values of format besides "template=<go template>".
+var formats = []string{"text", "json", "ndjson", "csv", "tsv", "yaml", "map", "svg"}
+
 // Default returns the default configuration.
 func Default() Config {
 	return Config{
@@ -62,6 +73,13 @@ func Load() (Config, error) {
 		return cfg, fmt.Errorf("config file read error: %w", err)
 	}
 
+	return parse(data, path)
+}
+
+// parse decodes and validates the contents of the config file at path.
+func parse(data []byte, path string) (Config, error) {
+	cfg := Default()
+
 	var raw rawConfig
 	if err := yaml.Unmarshal(data, &raw); err != nil {
 		return cfg, fmt.Errorf("config file parse error (%s): %w", path, err)
@@ -76,16 +94,22 @@ func Load() (Config, error) {
 		cfg.Timeout = d
 	}
 
-	// apply format if specified: "text" | "json"
+	// apply format if specified: "text" | "json" | "ndjson" | "csv" | "tsv" | "yaml" | "map" | "svg" | "template=..."
 	if raw.Format != "" {
-		switch raw.Format {
-		case "text", "json":
+		switch {
+		case slices.Contains(formats, raw.Format), strings.HasPrefix(raw.Format, "template="):
 			cfg.Format = raw.Format
 		default:
-			return cfg, fmt.Errorf("config: invalid format %q (must be \"text\" or \"json\")", raw.Format)
+			return cfg, fmt.Errorf("config: invalid format %q (must be one of %s or template=<go template>)", raw.Format, strings.Join(formats, ", "))
 		}
 	}
 
+	// validate screen aliases
+	if err := window.ValidateScreenAliases(raw.Screens); err != nil {
+		return cfg, fmt.Errorf("config (%s): %w", path, err)
+	}
+	cfg.ScreenAliases = raw.Screens
+
 	// Validate presets
 	if len(raw.Presets) > 0 {
 		if verrs := preset.ValidatePresets(raw.Presets); verrs != nil {
@@ -98,6 +122,18 @@ func Load() (Config, error) {
 		cfg.Presets = raw.Presets
 	}
 
+	// Validate profiles; each must refer to one of the presets above
+	if len(raw.Profiles) > 0 {
+		if verrs := profile.ValidateProfiles(raw.Profiles, raw.Presets); verrs != nil {
+			var errMsgs []string
+			for _, vErr := range verrs {
+				errMsgs = append(errMsgs, vErr.Error())
+			}
+			return cfg, fmt.Errorf("config (%s): profile validation failed: %s", path, strings.Join(errMsgs, "; "))
+		}
+		cfg.Profiles = raw.Profiles
+	}
+
 	// validate ignore_apps entries
 	for i, app := range raw.IgnoreApps {
 		trimmed := strings.TrimSpace(app)
@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -74,6 +75,27 @@ func TestLoad_InvalidFormat(t *testing.T) {
 	}
 }
 
+func TestLoad_StructuredFormats(t *testing.T) {
+	for _, format := range []string{"ndjson", "csv", "tsv", "yaml", `"template={{.app_name}}"`} {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+			cfgFile := filepath.Join(dir, "config.yaml")
+			if err := os.WriteFile(cfgFile, []byte("format: "+format+"\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			t.Setenv("MADO_CONFIG", cfgFile)
+			cfg, err := config.Load()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want := strings.Trim(format, `"`); cfg.Format != want {
+				t.Errorf("Format = %q, want %q", cfg.Format, want)
+			}
+		})
+	}
+}
+
 func TestLoad_PresetsValid(t *testing.T) {
 	dir := t.TempDir()
 	cfgFile := filepath.Join(dir, "config.yaml")
@@ -128,6 +150,124 @@ func TestLoad_PresetsInvalid(t *testing.T) {
 	}
 }
 
+func TestLoad_Profiles(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "config.yaml")
+	content := `presets:
+  - name: coding
+    rules:
+      - app: Code
+        snap: maximize
+profiles:
+  - name: docked
+    preset: coding
+    screens:
+      - name: Built-in Retina Display
+      - name: DELL U2720Q
+        width: 2560
+        height: 1440
+`
+	if err := os.WriteFile(cfgFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MADO_CONFIG", cfgFile)
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Profiles) != 1 || cfg.Profiles[0].Preset != "coding" || len(cfg.Profiles[0].Screens) != 2 {
+		t.Errorf("unexpected profiles: %+v", cfg.Profiles)
+	}
+}
+
+func TestLoad_ProfileUnknownPreset(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "config.yaml")
+	content := `profiles:
+  - name: docked
+    preset: coding
+    screens:
+      - width: 2560
+        height: 1440
+`
+	if err := os.WriteFile(cfgFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MADO_CONFIG", cfgFile)
+	if _, err := config.Load(); err == nil {
+		t.Fatal("expected validation error for profile with unknown preset, got nil")
+	}
+}
+
+func TestLoad_ScreenAliases(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "config.yaml")
+	content := `screens:
+  - alias: main
+    primary: true
+  - alias: external
+    resolution: 2560x1440
+presets:
+  - name: coding
+    rules:
+      - app: Code
+        screen: external
+        snap: maximize
+`
+	if err := os.WriteFile(cfgFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MADO_CONFIG", cfgFile)
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.ScreenAliases) != 2 || cfg.ScreenAliases[1].Resolution != "2560x1440" {
+		t.Errorf("unexpected screen aliases: %+v", cfg.ScreenAliases)
+	}
+	// presets carry the aliases their rules may refer to
+}
+
+func TestLoad_ScreenAliasInvalid(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "config.yaml")
+	content := `screens:
+  - alias: middle
+    position: center
+`
+	if err := os.WriteFile(cfgFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MADO_CONFIG", cfgFile)
+	if _, err := config.Load(); err == nil {
+		t.Fatal("expected validation error for invalid screen alias, got nil")
+	}
+}
+
+func TestLoad_ScreenAliasWithoutCriteria(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "config.yaml")
+	content := `screens:
+  - alias: laptop
+`
+	if err := os.WriteFile(cfgFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MADO_CONFIG", cfgFile)
+	_, err := config.Load()
+	if err == nil {
+		t.Fatal("expected validation error for an alias without criteria, got nil")
+	}
+	if !strings.Contains(err.Error(), `alias "laptop" needs at least one of`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 func TestLoad_PresetsEmpty(t *testing.T) {
 	dir := t.TempDir()
 	cfgFile := filepath.Join(dir, "config.yaml")
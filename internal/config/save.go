@@ -0,0 +1,345 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/peacock0803sz/mado/internal/preset"
+)
+
+// schemaHeader is written at the top of config files created by SavePreset.
+const schemaHeader = "# yaml-language-server: $schema=https://github.com/peacock0803sz/mado/raw/main/schemas/config.v1.schema.json"
+
+// PresetExistsError is returned when a preset is written under a name that is
+// already defined in the config file.
+type PresetExistsError struct {
+	Name string
+	Path string
+}
+
+func (e *PresetExistsError) Error() string {
+	return fmt.Sprintf("preset %q already exists in %s", e.Name, e.Path)
+}
+
+// PresetInUseError is returned when a preset that a profile refers to is deleted.
+type PresetInUseError struct {
+	Name    string
+	Profile string
+}
+
+func (e *PresetInUseError) Error() string {
+	return fmt.Sprintf("preset %q is used by profile %q", e.Name, e.Profile)
+}
+
+// SavePreset inserts p into the presets of the active config file, or replaces the
+// preset with the same name when replace is true. A missing file is created.
+// Returns the path of the file.
+func SavePreset(p preset.Preset, replace bool) (string, error) {
+	var item yaml.Node
+	if err := item.Encode(p); err != nil {
+		return "", err
+	}
+	return modifyPresets(func(_, seq *yaml.Node, path string) error {
+		i := presetIndex(seq, p.Name)
+		if i < 0 {
+			seq.Content = append(seq.Content, &item)
+			return nil
+		}
+		if !replace {
+			return &PresetExistsError{Name: p.Name, Path: path}
+		}
+		item.HeadComment = seq.Content[i].HeadComment
+		seq.Content[i] = &item
+		return nil
+	})
+}
+
+// DeletePreset removes the named preset from the active config file. A preset that a
+// profile refers to is not deleted.
+func DeletePreset(name string) (string, error) {
+	return modifyPresets(func(root, seq *yaml.Node, _ string) error {
+		i := presetIndex(seq, name)
+		if i < 0 {
+			return &preset.NotFoundError{Name: name}
+		}
+		if refs := profileRefs(root, name); len(refs) > 0 {
+			return &PresetInUseError{Name: name, Profile: profileName(refs[0])}
+		}
+		seq.Content = append(seq.Content[:i], seq.Content[i+1:]...)
+		return nil
+	})
+}
+
+// RenamePreset changes the name of a preset in the active config file, along with
+// the profiles that refer to it.
+func RenamePreset(name, newName string) (string, error) {
+	return modifyPresets(func(root, seq *yaml.Node, path string) error {
+		i := presetIndex(seq, name)
+		if i < 0 {
+			return &preset.NotFoundError{Name: name}
+		}
+		if presetIndex(seq, newName) >= 0 {
+			return &PresetExistsError{Name: newName, Path: path}
+		}
+		mappingValue(seq.Content[i], "name").Value = newName
+		for _, p := range profileRefs(root, name) {
+			mappingValue(p, "preset").Value = newName
+		}
+		return nil
+	})
+}
+
+// CopyPreset appends a copy of a preset under a new name to the active config file.
+func CopyPreset(name, newName string) (string, error) {
+	return modifyPresets(func(_, seq *yaml.Node, path string) error {
+		i := presetIndex(seq, name)
+		if i < 0 {
+			return &preset.NotFoundError{Name: name}
+		}
+		if presetIndex(seq, newName) >= 0 {
+			return &PresetExistsError{Name: newName, Path: path}
+		}
+		item := cloneNode(seq.Content[i])
+		mappingValue(item, "name").Value = newName
+		seq.Content = append(seq.Content, item)
+		return nil
+	})
+}
+
+// PresetSource returns the YAML of the named preset as written in the active config
+// file, including its comments.
+func PresetSource(name string) ([]byte, error) {
+	path, _, doc, err := readDocument()
+	if err != nil {
+		return nil, err
+	}
+	seq, err := presetsNode(doc, path, false)
+	if err != nil {
+		return nil, err
+	}
+	i := presetIndex(seq, name)
+	if i < 0 {
+		return nil, &preset.NotFoundError{Name: name}
+	}
+	return yaml.Dump(seq.Content[i], yaml.WithIndent(2), yaml.WithCompactSeqIndent(false))
+}
+
+// UpdatePreset replaces the named preset in the active config file with the preset
+// defined by src, a single YAML mapping as returned by PresetSource.
+func UpdatePreset(name string, src []byte) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return "", fmt.Errorf("preset parse error: %w", err)
+	}
+	if len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return "", errors.New("preset must be a single YAML mapping")
+	}
+	item := doc.Content[0]
+	// a comment above the preset is parsed as the comment of its first key;
+	// move it back in front of the list item
+	if item.HeadComment == "" {
+		item.HeadComment, doc.HeadComment = doc.HeadComment, ""
+	}
+	if item.HeadComment == "" && len(item.Content) > 0 {
+		item.HeadComment, item.Content[0].HeadComment = item.Content[0].HeadComment, ""
+	}
+	return modifyPresets(func(_, seq *yaml.Node, _ string) error {
+		i := presetIndex(seq, name)
+		if i < 0 {
+			return &preset.NotFoundError{Name: name}
+		}
+		seq.Content[i] = item
+		return nil
+	})
+}
+
+// modifyPresets applies fn to the top-level mapping and the presets sequence of the
+// active config file and writes the result back. Only the list items changed by fn
+// are re-encoded; the rest of the file is kept byte for byte, including comments,
+// blank lines and indentation. The updated file is validated like Load before it is
+// written atomically, so an invalid change leaves the file untouched. Returns the
+// path of the file.
+func modifyPresets(fn func(root, seq *yaml.Node, path string) error) (string, error) {
+	path, src, doc, err := readDocument()
+	if err != nil {
+		return path, err
+	}
+	root := doc.Content[0]
+	snaps := make([]listSnapshot, len(splicedKeys))
+	for i, key := range splicedKeys {
+		snaps[i] = snapshotList(root, key)
+	}
+	seq, err := presetsNode(doc, path, true)
+	if err != nil {
+		return path, err
+	}
+	if err := fn(root, seq, path); err != nil {
+		return path, err
+	}
+
+	out, err := yaml.Dump(doc, yaml.WithIndent(2), yaml.WithCompactSeqIndent(false))
+	if err != nil {
+		return path, err
+	}
+	if spliced, ok := splice(src, doc, snaps, out); ok {
+		out = spliced
+	}
+	if _, err := parse(out, path); err != nil {
+		return path, err
+	}
+	return path, writeFileAtomic(path, out)
+}
+
+// readDocument reads the active config file and parses it into a YAML document whose
+// content is a single mapping. A missing file yields no data and an empty document with
+// the schema header.
+func readDocument() (string, []byte, *yaml.Node, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is resolved from trusted config locations (XDG or $MADO_CONFIG)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return path, nil, nil, fmt.Errorf("config file read error: %w", err)
+	}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return path, nil, nil, fmt.Errorf("config file parse error (%s): %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = &yaml.Node{Kind: yaml.DocumentNode, HeadComment: schemaHeader}
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return path, nil, nil, fmt.Errorf("config (%s): top level must be a mapping", path)
+	}
+	return path, data, doc, nil
+}
+
+// presetsNode returns the presets sequence of doc. When create is true a missing or
+// empty presets key is turned into an empty sequence; otherwise an empty sequence is
+// returned without touching doc.
+func presetsNode(doc *yaml.Node, path string, create bool) (*yaml.Node, error) {
+	root := doc.Content[0]
+	seq := mappingValue(root, "presets")
+	switch {
+	case seq == nil:
+		seq = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if create {
+			root.Content = append(root.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "presets"}, seq)
+		}
+	case seq.Kind == yaml.ScalarNode && seq.Tag == "!!null":
+		// "presets:" with no value
+		if !create {
+			return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}, nil
+		}
+		*seq = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", LineComment: seq.LineComment}
+	case seq.Kind != yaml.SequenceNode:
+		return nil, fmt.Errorf("config (%s): presets must be a list", path)
+	}
+	return seq, nil
+}
+
+// presetIndex returns the index of the preset called name in seq, or -1.
+func presetIndex(seq *yaml.Node, name string) int {
+	for i, item := range seq.Content {
+		if n := mappingValue(item, "name"); n != nil && n.Value == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// profileRefs returns the profiles in the top-level mapping root that refer to the
+// preset called name.
+func profileRefs(root *yaml.Node, name string) []*yaml.Node {
+	seq := mappingValue(root, "profiles")
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var refs []*yaml.Node
+	for _, item := range seq.Content {
+		if p := mappingValue(item, "preset"); p != nil && p.Value == name {
+			refs = append(refs, item)
+		}
+	}
+	return refs
+}
+
+// profileName returns the name of the profile mapping p.
+func profileName(p *yaml.Node) string {
+	if n := mappingValue(p, "name"); n != nil {
+		return n.Value
+	}
+	return ""
+}
+
+// cloneNode returns a deep copy of n.
+func cloneNode(n *yaml.Node) *yaml.Node {
+	c := *n
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i, child := range n.Content {
+		c.Content[i] = cloneNode(child)
+	}
+	return &c
+}
+
+// mappingValue returns the value node of key in the mapping node m, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it over path,
+// keeping the permissions of an existing file. Symlinks are followed.
+func writeFileAtomic(path string, data []byte) error {
+	// write through symlinks instead of replacing them
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	mode := os.FileMode(0o600)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("config file write error: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("config file write error: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // no-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("config file write error: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("config file write error: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("config file write error: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("config file write error: %w", err)
+	}
+	return nil
+}
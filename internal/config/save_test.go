@@ -0,0 +1,327 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/config"
+	"github.com/peacock0803sz/mado/internal/preset"
+)
+
+const savedConfig = `# yaml-language-server: $schema=https://github.com/peacock0803sz/mado/raw/main/schemas/config.v1.schema.json
+format: text # keep this comment
+presets:
+  # editor on the left
+  - name: coding
+    rules:
+      - app: Code
+        position: [0, 0]
+timeout: 3s
+`
+
+var recorded = preset.Preset{
+	Name: "coding",
+	Rules: []preset.Rule{
+		{App: "Code", Position: []preset.Expr{"0", "0"}, Size: []preset.Expr{"960", "1080"}},
+	},
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	cfgFile := filepath.Join(t.TempDir(), "config.yaml")
+	if content != "" {
+		if err := os.WriteFile(cfgFile, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("MADO_CONFIG", cfgFile)
+	return cfgFile
+}
+
+func TestSavePreset_Insert(t *testing.T) {
+	cfgFile := writeConfig(t, savedConfig)
+
+	p := recorded
+	p.Name = "writing"
+	path, err := config.SavePreset(p, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != cfgFile {
+		t.Errorf("path = %q, want %q", path, cfgFile)
+	}
+
+	data, err := os.ReadFile(cfgFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"# yaml-language-server: $schema=",
+		"format: text # keep this comment",
+		"  # editor on the left\n  - name: coding",
+		"  - name: writing",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("saved config missing %q:\n%s", want, got)
+		}
+	}
+	// key order is preserved
+	if strings.Index(got, "presets:") > strings.Index(got, "timeout: 3s") {
+		t.Errorf("key order changed:\n%s", got)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("saved config does not load: %v", err)
+	}
+	if len(cfg.Presets) != 2 || cfg.Presets[1].Name != "writing" {
+		t.Errorf("expected presets [coding writing], got %+v", cfg.Presets)
+	}
+}
+
+func TestSavePreset_Exists(t *testing.T) {
+	cfgFile := writeConfig(t, savedConfig)
+
+	_, err := config.SavePreset(recorded, false)
+	var exists *config.PresetExistsError
+	if !errors.As(err, &exists) {
+		t.Fatalf("expected *config.PresetExistsError, got %T: %v", err, err)
+	}
+	data, _ := os.ReadFile(cfgFile)
+	if string(data) != savedConfig {
+		t.Errorf("config must be left untouched, got:\n%s", data)
+	}
+}
+
+func TestSavePreset_Replace(t *testing.T) {
+	writeConfig(t, savedConfig)
+
+	if _, err := config.SavePreset(recorded, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Presets) != 1 {
+		t.Fatalf("expected 1 preset, got %d", len(cfg.Presets))
+	}
+	if size := cfg.Presets[0].Rules[0].Size; len(size) != 2 || size[0] != "960" {
+		t.Errorf("preset was not replaced, size = %v", size)
+	}
+}
+
+func TestSavePreset_NewFile(t *testing.T) {
+	cfgFile := writeConfig(t, "")
+
+	if _, err := config.SavePreset(recorded, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(cfgFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# yaml-language-server: $schema=") {
+		t.Errorf("new config should start with the schema header:\n%s", data)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Presets) != 1 {
+		t.Errorf("expected 1 preset, got %d", len(cfg.Presets))
+	}
+}
+
+func TestSavePreset_Invalid(t *testing.T) {
+	cfgFile := writeConfig(t, savedConfig)
+
+	p := recorded
+	p.Name = "bad name!"
+	if _, err := config.SavePreset(p, false); err == nil {
+		t.Fatal("expected validation error, got nil")
+	}
+	data, _ := os.ReadFile(cfgFile)
+	if string(data) != savedConfig {
+		t.Errorf("config must be left untouched, got:\n%s", data)
+	}
+}
+
+func TestDeletePreset(t *testing.T) {
+	cfgFile := writeConfig(t, savedConfig)
+
+	if _, err := config.DeletePreset("coding"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(cfgFile)
+	if strings.Contains(string(data), "name: coding") {
+		t.Errorf("preset was not removed:\n%s", data)
+	}
+	if !strings.Contains(string(data), "format: text # keep this comment") {
+		t.Errorf("comments were not preserved:\n%s", data)
+	}
+
+	var notFound *preset.NotFoundError
+	if _, err := config.DeletePreset("coding"); !errors.As(err, &notFound) {
+		t.Errorf("expected *preset.NotFoundError, got %T: %v", err, err)
+	}
+}
+
+func TestRenamePreset(t *testing.T) {
+	cfgFile := writeConfig(t, savedConfig)
+
+	if _, err := config.RenamePreset("coding", "editing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(cfgFile)
+	if !strings.Contains(string(data), "  # editor on the left\n  - name: editing") {
+		t.Errorf("preset was not renamed in place:\n%s", data)
+	}
+
+	if _, err := config.RenamePreset("editing", "bad name!"); err == nil {
+		t.Error("expected validation error for an invalid name, got nil")
+	}
+}
+
+const profiledConfig = savedConfig + `profiles:
+  - name: docked
+    preset: coding
+    screens:
+      - name: DELL U2720Q
+`
+
+func TestDeletePreset_UsedByProfile(t *testing.T) {
+	cfgFile := writeConfig(t, profiledConfig)
+
+	_, err := config.DeletePreset("coding")
+	var inUse *config.PresetInUseError
+	if !errors.As(err, &inUse) {
+		t.Fatalf("expected *config.PresetInUseError, got %T: %v", err, err)
+	}
+	if inUse.Profile != "docked" || !strings.Contains(err.Error(), `profile "docked"`) {
+		t.Errorf("error should name the profile, got %v", err)
+	}
+	if data, _ := os.ReadFile(cfgFile); string(data) != profiledConfig {
+		t.Errorf("config must be left untouched, got:\n%s", data)
+	}
+}
+
+func TestRenamePreset_UpdatesProfiles(t *testing.T) {
+	writeConfig(t, profiledConfig)
+
+	if _, err := config.RenamePreset("coding", "editing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Profiles) != 1 || cfg.Profiles[0].Preset != "editing" {
+		t.Errorf("profile still refers to the old name: %+v", cfg.Profiles)
+	}
+}
+
+func TestCopyPreset(t *testing.T) {
+	writeConfig(t, savedConfig)
+
+	if _, err := config.CopyPreset("coding", "coding-wide"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Presets) != 2 || cfg.Presets[1].Name != "coding-wide" || cfg.Presets[1].Rules[0].App != "Code" {
+		t.Errorf("unexpected presets after copy: %+v", cfg.Presets)
+	}
+
+	var exists *config.PresetExistsError
+	if _, err := config.CopyPreset("coding", "coding-wide"); !errors.As(err, &exists) {
+		t.Errorf("expected *config.PresetExistsError, got %T: %v", err, err)
+	}
+}
+
+func TestPresetSourceAndUpdate(t *testing.T) {
+	cfgFile := writeConfig(t, savedConfig)
+
+	src, err := config.PresetSource("coding")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(string(src), "# editor on the left\nname: coding\n") {
+		t.Errorf("unexpected preset source:\n%s", src)
+	}
+
+	edited := strings.Replace(string(src), "position: [0, 0]", "position: [0, 0]\n    size: [960, 1080] # resized", 1)
+	if _, err := config.UpdatePreset("coding", []byte(edited)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(cfgFile)
+	for _, want := range []string{"  # editor on the left\n  - name: coding", "size: [960, 1080] # resized", "timeout: 3s"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("config missing %q:\n%s", want, data)
+		}
+	}
+
+	if _, err := config.UpdatePreset("coding", []byte("name: coding\nrules: []\n")); err == nil {
+		t.Error("expected validation error for a preset without rules, got nil")
+	}
+}
+
+const formattedConfig = `# yaml-language-server: $schema=https://github.com/peacock0803sz/mado/raw/main/schemas/config.v1.schema.json
+format: text
+
+presets:
+    # editor on the left
+    - name: coding
+      rules:
+          - app: Code
+            position: [0, 0]
+
+    - name: meeting
+      rules:
+          - {app: Zoom, position: [0, 0]}
+
+timeout:   3s   # spacing kept
+`
+
+func TestModifyPresets_KeepsFormatting(t *testing.T) {
+	head := formattedConfig[:strings.Index(formattedConfig, "    # editor")]
+	coding := formattedConfig[len(head) : strings.Index(formattedConfig, "\n\n    - name: meeting")+1]
+	meeting := "    - name: meeting\n      rules:\n          - {app: Zoom, position: [0, 0]}\n"
+	tail := "\ntimeout:   3s   # spacing kept\n"
+
+	tests := []struct {
+		name   string
+		modify func() error
+		want   string
+	}{
+		{"rm", func() error { _, err := config.DeletePreset("coding"); return err },
+			head + meeting + tail},
+		{"mv", func() error { _, err := config.RenamePreset("meeting", "call"); return err },
+			head + coding + "\n" + "    - name: call\n      rules:\n        - {app: Zoom, position: [0, 0]}\n" + tail},
+		{"rec --save", func() error {
+			p := recorded
+			p.Name = "writing"
+			_, err := config.SavePreset(p, false)
+			return err
+		}, head + coding + "\n" + meeting + "\n" +
+			"    - name: writing\n      rules:\n        - app: Code\n          position: [0, 0]\n          size: [960, 1080]\n" + tail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgFile := writeConfig(t, formattedConfig)
+			if err := tt.modify(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			data, _ := os.ReadFile(cfgFile)
+			if string(data) != tt.want {
+				t.Errorf("config =\n%s\nwant\n%s", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// splicedKeys are the top-level lists whose items modifyPresets rewrites one by one.
+var splicedKeys = []string{"presets", "profiles"}
+
+// listSnapshot is a top-level list of the config file as it was read.
+type listSnapshot struct {
+	key   string
+	value *yaml.Node   // value of key, nil when the key is missing
+	dump  []byte       // encoding of value
+	items []*yaml.Node // items of a block list with at least one item
+	dumps [][]byte     // encoding of each item
+}
+
+// snapshotList records the value of key in the top-level mapping root.
+func snapshotList(root *yaml.Node, key string) listSnapshot {
+	s := listSnapshot{key: key, value: mappingValue(root, key)}
+	if s.value == nil {
+		return s
+	}
+	s.dump = dumpNode(s.value)
+	if s.value.Kind == yaml.SequenceNode && s.value.Style&yaml.FlowStyle == 0 && len(s.value.Content) > 0 {
+		s.items = append([]*yaml.Node(nil), s.value.Content...)
+		for _, item := range s.items {
+			s.dumps = append(s.dumps, dumpNode(item))
+		}
+	}
+	return s
+}
+
+// lineEdit replaces the lines [start, end) of a file with text.
+type lineEdit struct {
+	start, end int
+	text       string
+}
+
+// splice rewrites src, the config file as read, to match doc after modifyPresets
+// changed it. Changed items of the lists in snaps are re-encoded in place and new items
+// are added after the last one; a list that cannot be edited item by item is
+// re-encoded as a whole. Everything else is copied from src. want is the encoding of
+// the whole document; ok is false when the spliced file would not parse to the same
+// document, in which case want should be written instead.
+func splice(src []byte, doc *yaml.Node, snaps []listSnapshot, want []byte) (out []byte, ok bool) {
+	root := doc.Content[0]
+	if len(src) == 0 || root.Line == 0 || root.Style&yaml.FlowStyle != 0 {
+		return nil, false
+	}
+	text := string(src)
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	lines := strings.SplitAfter(text, "\n")
+	lines = lines[:len(lines)-1]
+
+	var edits []lineEdit
+	for _, s := range snaps {
+		k := keyIndex(root, s.key)
+		if k < 0 {
+			continue
+		}
+		key, value := root.Content[k], root.Content[k+1]
+		if s.value == value && bytes.Equal(dumpNode(value), s.dump) {
+			continue
+		}
+		// the end of the pair, before the next top-level key
+		limit := len(lines)
+		if k+2 < len(root.Content) {
+			limit = startLine(root.Content[k+2])
+		}
+		switch {
+		case key.Line == 0:
+			edits = append(edits, lineEdit{start: len(lines), end: len(lines), text: string(dumpPair(key, value))})
+		case s.value == value && len(s.items) > 0 && len(value.Content) > 0:
+			edits = append(edits, spliceItems(lines, s, value, limit))
+		default:
+			edits = append(edits, lineEdit{
+				start: startLine(key),
+				end:   trimBlank(lines, startLine(key), limit),
+				text:  string(dumpPair(key, value)),
+			})
+		}
+	}
+
+	var b strings.Builder
+	pos := 0
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	for _, e := range edits {
+		if e.start < pos {
+			return nil, false
+		}
+		b.WriteString(strings.Join(lines[pos:e.start], ""))
+		b.WriteString(e.text)
+		pos = e.end
+	}
+	b.WriteString(strings.Join(lines[pos:], ""))
+	out = []byte(b.String())
+
+	// make sure the edits mean the same as the document
+	var check yaml.Node
+	if err := yaml.Unmarshal(out, &check); err != nil {
+		return nil, false
+	}
+	got, err := yaml.Dump(&check, yaml.WithIndent(2), yaml.WithCompactSeqIndent(false))
+	if err != nil || !bytes.Equal(got, want) {
+		return nil, false
+	}
+	return out, true
+}
+
+// spliceItems returns the edit that turns the items of s, a block list, into the items
+// of value. Items that did not change keep their original lines. limit is the line
+// after the last one the list may take.
+func spliceItems(lines []string, s listSnapshot, value *yaml.Node, limit int) lineEdit {
+	n := len(s.items)
+	indent := leadingSpace(lines[s.items[0].Line-1])
+	starts := make([]int, n+1)
+	for i, item := range s.items {
+		starts[i] = startLine(item)
+	}
+	starts[n] = itemEnd(lines, s.items[n-1], len(indent), limit)
+
+	orig := make(map[*yaml.Node]int, n)
+	for i, item := range s.items {
+		orig[item] = i
+	}
+	// the blank lines between the first two items separate every item
+	sep := ""
+	if n > 1 {
+		sep = strings.Repeat("\n", starts[1]-trimBlank(lines, starts[0], starts[1]))
+	}
+
+	texts := make([]string, len(value.Content))
+	for j, item := range value.Content {
+		if i, ok := orig[item]; ok && bytes.Equal(dumpNode(item), s.dumps[i]) {
+			texts[j] = strings.Join(lines[starts[i]:trimBlank(lines, starts[i], starts[i+1])], "")
+			continue
+		}
+		texts[j] = indentLines(dumpNode(&yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{item}}), indent)
+	}
+	return lineEdit{start: starts[0], end: starts[n], text: strings.Join(texts, sep)}
+}
+
+// itemEnd returns the line after the last item of a block list, whose dashes are
+// indented by indent columns. Lines indented deeper than the dash belong to the item;
+// blank lines at its end do not.
+func itemEnd(lines []string, item *yaml.Node, indent, limit int) int {
+	end := lastLine(item)
+	for i := end; i < limit; i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		if len(leadingSpace(lines[i])) <= indent {
+			break
+		}
+		end = i + 1
+	}
+	return end
+}
+
+// startLine returns the index of the first line of n, including its head comment.
+func startLine(n *yaml.Node) int {
+	start := n.Line - 1
+	if n.HeadComment != "" {
+		start -= strings.Count(n.HeadComment, "\n") + 1
+	}
+	return start
+}
+
+// lastLine returns the number of the last line holding n or one of its children.
+func lastLine(n *yaml.Node) int {
+	last := n.Line
+	for _, c := range n.Content {
+		last = max(last, lastLine(c))
+	}
+	return last
+}
+
+// trimBlank returns end moved back over the blank lines before it, but not before start.
+func trimBlank(lines []string, start, end int) int {
+	for end > start && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return end
+}
+
+// keyIndex returns the index of key in the content of the mapping node m, or -1.
+func keyIndex(m *yaml.Node, key string) int {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// leadingSpace returns the spaces at the start of line.
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " "))]
+}
+
+// indentLines prefixes every non-empty line of data with indent.
+func indentLines(data []byte, indent string) string {
+	lines := strings.SplitAfter(string(data), "\n")
+	for i, l := range lines {
+		if strings.TrimSpace(l) != "" {
+			lines[i] = indent + l
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// dumpPair encodes a single top-level key and its value.
+func dumpPair(key, value *yaml.Node) []byte {
+	return dumpNode(&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{key, value}})
+}
+
+// dumpNode encodes n the way modifyPresets writes the config file.
+func dumpNode(n *yaml.Node) []byte {
+	data, err := yaml.Dump(n, yaml.WithIndent(2), yaml.WithCompactSeqIndent(false))
+	if err != nil {
+		return nil
+	}
+	return data
+}
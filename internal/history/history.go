@@ -0,0 +1,205 @@
+// Package history records window geometry touched by mutating commands so that
+// they can be undone and redone.
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+)
+
+// DefaultLimit is the maximum number of entries kept on the undo stack.
+const DefaultLimit = 50
+
+// ErrNothingToUndo is returned by Undo when the undo stack is empty.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// ErrNothingToRedo is returned by Redo when the redo stack is empty.
+var ErrNothingToRedo = errors.New("nothing to redo")
+
+// Entry is a single recorded operation.
+// Before and After hold the same windows (matched by Window.ID) with their
+// frames before and after the operation.
+type Entry struct {
+	Command string      `json:"command"`
+	Time    time.Time   `json:"time"`
+	Before  []ax.Window `json:"before"`
+	After   []ax.Window `json:"after"`
+}
+
+// Store is a bounded undo/redo history persisted as a JSON file.
+type Store struct {
+	path  string
+	limit int
+
+	// UndoStack holds undoable entries, oldest first.
+	UndoStack []Entry `json:"undo"`
+	// RedoStack holds entries undone since the last recorded operation, oldest first.
+	RedoStack []Entry `json:"redo"`
+}
+
+// RestoreResult holds the outcome of Undo or Redo.
+type RestoreResult struct {
+	Entry    Entry
+	Restored []ax.Window // windows moved back, with their restored frames
+	Missing  []ax.Window // windows that no longer exist, as recorded
+}
+
+// DefaultPath returns the history file path: $XDG_STATE_HOME/mado/history.json,
+// defaulting to ~/.local/state/mado/history.json.
+func DefaultPath() (string, error) {
+	baseDir := os.Getenv("XDG_STATE_HOME")
+	if baseDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot determine home dir: %w", err)
+		}
+		baseDir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(baseDir, "mado", "history.json"), nil
+}
+
+// Open loads the history stored at path. A missing file yields an empty history.
+// limit bounds the undo stack; values <= 0 mean DefaultLimit.
+func Open(path string, limit int) (*Store, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	s := &Store{path: path, limit: limit}
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the history file under the user's state dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("history read error: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("history parse error (%s): %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes the history back to its file, creating the directory if needed.
+func (s *Store) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("history write error: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first so an interrupted write never truncates the history
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("history write error: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("history write error: %w", err)
+	}
+	return nil
+}
+
+// Record pushes e onto the undo stack and clears the redo stack.
+// The oldest entries are dropped once the stack exceeds the limit.
+// Entries with no windows are ignored.
+func (s *Store) Record(e Entry) {
+	if len(e.Before) == 0 {
+		return
+	}
+	s.UndoStack = append(s.UndoStack, e)
+	if over := len(s.UndoStack) - s.limit; over > 0 {
+		s.UndoStack = s.UndoStack[over:]
+	}
+	s.RedoStack = nil
+}
+
+// Undo restores the frames recorded before the most recent operation and moves
+// the entry onto the redo stack.
+func (s *Store) Undo(ctx context.Context, svc ax.WindowService) (*RestoreResult, error) {
+	if len(s.UndoStack) == 0 {
+		return nil, ErrNothingToUndo
+	}
+	e := s.UndoStack[len(s.UndoStack)-1]
+	res, err := restore(ctx, svc, e, e.Before)
+	if err != nil {
+		return res, err
+	}
+	s.UndoStack = s.UndoStack[:len(s.UndoStack)-1]
+	s.RedoStack = append(s.RedoStack, e)
+	return res, nil
+}
+
+// Redo re-applies the frames of the most recently undone operation and moves
+// the entry back onto the undo stack.
+func (s *Store) Redo(ctx context.Context, svc ax.WindowService) (*RestoreResult, error) {
+	if len(s.RedoStack) == 0 {
+		return nil, ErrNothingToRedo
+	}
+	e := s.RedoStack[len(s.RedoStack)-1]
+	res, err := restore(ctx, svc, e, e.After)
+	if err != nil {
+		return res, err
+	}
+	s.RedoStack = s.RedoStack[:len(s.RedoStack)-1]
+	s.UndoStack = append(s.UndoStack, e)
+	return res, nil
+}
+
+// restore moves and resizes the windows in frames back to their recorded geometry.
+// Windows that no longer exist are reported in RestoreResult.Missing.
+func restore(ctx context.Context, svc ax.WindowService, e Entry, frames []ax.Window) (*RestoreResult, error) {
+	windows, err := svc.ListWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[uint32]ax.Window, len(windows))
+	for _, w := range windows {
+		current[w.ID] = w
+	}
+
+	res := &RestoreResult{Entry: e}
+	for _, f := range frames {
+		w, ok := current[f.ID]
+		if !ok {
+			res.Missing = append(res.Missing, f)
+			continue
+		}
+		if err := svc.MoveWindow(ctx, w.ID, f.X, f.Y); err != nil {
+			return res, err
+		}
+		if err := svc.ResizeWindow(ctx, w.ID, f.Width, f.Height); err != nil {
+			return res, err
+		}
+		w.X, w.Y, w.Width, w.Height = f.X, f.Y, f.Width, f.Height
+		res.Restored = append(res.Restored, w)
+	}
+	return res, nil
+}
+
+// NewEntry builds an Entry for command from the windows it changed.
+// before is a snapshot taken prior to the command (it may contain unrelated windows);
+// after holds the changed windows with their new frames. Windows missing from before
+// are left out.
+func NewEntry(command string, before, after []ax.Window) Entry {
+	prev := make(map[uint32]ax.Window, len(before))
+	for _, w := range before {
+		prev[w.ID] = w
+	}
+	e := Entry{Command: command, Time: time.Now()}
+	for _, w := range after {
+		b, ok := prev[w.ID]
+		if !ok {
+			continue
+		}
+		e.Before = append(e.Before, b)
+		e.After = append(e.After, w)
+	}
+	return e
+}
@@ -0,0 +1,177 @@
+package history_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/history"
+)
+
+func openTemp(t *testing.T, limit int) (*history.Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mado", "history.json")
+	s, err := history.Open(path, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, path
+}
+
+func TestNewEntry(t *testing.T) {
+	before := []ax.Window{
+		{ID: 1, AppName: "Code", X: 100, Y: 100, Width: 800, Height: 600},
+		{ID: 2, AppName: "Terminal", X: 0, Y: 0, Width: 640, Height: 480},
+	}
+	after := []ax.Window{
+		{ID: 1, AppName: "Code", X: 0, Y: 0, Width: 960, Height: 1080},
+		{ID: 9, AppName: "Unknown"},
+	}
+	e := history.NewEntry("move", before, after)
+	if len(e.Before) != 1 || e.Before[0].X != 100 {
+		t.Errorf("Before = %+v, want only Code at its old frame", e.Before)
+	}
+	if len(e.After) != 1 || e.After[0].Width != 960 {
+		t.Errorf("After = %+v, want only Code at its new frame", e.After)
+	}
+}
+
+func TestStore_SaveAndOpen(t *testing.T) {
+	s, path := openTemp(t, 0)
+	s.Record(history.NewEntry("preset apply coding",
+		[]ax.Window{{ID: 1, X: 10}}, []ax.Window{{ID: 1, X: 20}}))
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := history.Open(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.UndoStack) != 1 || loaded.UndoStack[0].Command != "preset apply coding" {
+		t.Errorf("loaded undo stack = %+v", loaded.UndoStack)
+	}
+}
+
+func TestStore_RecordBounded(t *testing.T) {
+	s, _ := openTemp(t, 3)
+	for i := range 5 {
+		s.Record(history.NewEntry(fmt.Sprintf("move %d", i),
+			[]ax.Window{{ID: 1, X: i}}, []ax.Window{{ID: 1, X: i + 1}}))
+	}
+	if len(s.UndoStack) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(s.UndoStack))
+	}
+	if s.UndoStack[0].Command != "move 2" {
+		t.Errorf("oldest entry = %q, want %q", s.UndoStack[0].Command, "move 2")
+	}
+}
+
+func TestStore_RecordEmptyIgnored(t *testing.T) {
+	s, _ := openTemp(t, 0)
+	s.Record(history.NewEntry("move", nil, nil))
+	if len(s.UndoStack) != 0 {
+		t.Errorf("expected empty entry to be ignored, got %+v", s.UndoStack)
+	}
+}
+
+func TestStore_UndoRedo(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: []ax.Window{
+		{ID: 1, AppName: "Code", X: 0, Y: 0, Width: 960, Height: 1080},
+	}}
+	s, _ := openTemp(t, 0)
+	s.Record(history.NewEntry("move",
+		[]ax.Window{{ID: 1, AppName: "Code", X: 100, Y: 100, Width: 800, Height: 600}},
+		[]ax.Window{{ID: 1, AppName: "Code", X: 0, Y: 0, Width: 960, Height: 1080}}))
+
+	res, err := s.Undo(context.Background(), svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Restored) != 1 || res.Restored[0].X != 100 || res.Restored[0].Width != 800 {
+		t.Errorf("undo restored = %+v", res.Restored)
+	}
+	if len(s.UndoStack) != 0 || len(s.RedoStack) != 1 {
+		t.Errorf("after undo: undo=%d redo=%d, want 0/1", len(s.UndoStack), len(s.RedoStack))
+	}
+
+	res, err = s.Redo(context.Background(), svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Restored) != 1 || res.Restored[0].X != 0 || res.Restored[0].Width != 960 {
+		t.Errorf("redo restored = %+v", res.Restored)
+	}
+	if len(s.UndoStack) != 1 || len(s.RedoStack) != 0 {
+		t.Errorf("after redo: undo=%d redo=%d, want 1/0", len(s.UndoStack), len(s.RedoStack))
+	}
+}
+
+func TestStore_RecordClearsRedo(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: []ax.Window{{ID: 1}}}
+	s, _ := openTemp(t, 0)
+	s.Record(history.NewEntry("move", []ax.Window{{ID: 1}}, []ax.Window{{ID: 1, X: 5}}))
+	if _, err := s.Undo(context.Background(), svc); err != nil {
+		t.Fatal(err)
+	}
+	s.Record(history.NewEntry("tile", []ax.Window{{ID: 1}}, []ax.Window{{ID: 1, X: 9}}))
+	if len(s.RedoStack) != 0 {
+		t.Errorf("expected redo stack to be cleared, got %d entries", len(s.RedoStack))
+	}
+}
+
+func TestStore_UndoMissingWindow(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: []ax.Window{{ID: 1, AppName: "Code"}}}
+	s, _ := openTemp(t, 0)
+	s.Record(history.NewEntry("tile",
+		[]ax.Window{{ID: 1, AppName: "Code"}, {ID: 2, AppName: "Terminal", Title: "zsh"}},
+		[]ax.Window{{ID: 1, AppName: "Code", X: 5}, {ID: 2, AppName: "Terminal", Title: "zsh", X: 5}}))
+
+	res, err := s.Undo(context.Background(), svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Restored) != 1 || len(res.Missing) != 1 || res.Missing[0].Title != "zsh" {
+		t.Errorf("restored=%+v missing=%+v", res.Restored, res.Missing)
+	}
+}
+
+func TestStore_NothingToUndo(t *testing.T) {
+	s, _ := openTemp(t, 0)
+	svc := &ax.MockWindowService{}
+	if _, err := s.Undo(context.Background(), svc); !errors.Is(err, history.ErrNothingToUndo) {
+		t.Errorf("expected ErrNothingToUndo, got %v", err)
+	}
+	if _, err := s.Redo(context.Background(), svc); !errors.Is(err, history.ErrNothingToRedo) {
+		t.Errorf("expected ErrNothingToRedo, got %v", err)
+	}
+}
+
+func TestStore_UndoKeepsEntryOnError(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: []ax.Window{{ID: 1}},
+		MoveErr: errors.New("AX error"),
+	}
+	s, _ := openTemp(t, 0)
+	s.Record(history.NewEntry("move", []ax.Window{{ID: 1}}, []ax.Window{{ID: 1, X: 5}}))
+	if _, err := s.Undo(context.Background(), svc); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if len(s.UndoStack) != 1 {
+		t.Errorf("failed undo must keep the entry, got %d entries", len(s.UndoStack))
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state")
+	path, err := history.DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/tmp/state/mado/history.json" {
+		t.Errorf("DefaultPath() = %q", path)
+	}
+}
@@ -0,0 +1,202 @@
+// Package layout computes automatic tiling layouts for a set of windows on a screen.
+package layout
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// Kind is the name of a tiling layout.
+type Kind string
+
+// Layout kinds supported by Compute.
+const (
+	KindColumns     Kind = "columns"
+	KindRows        Kind = "rows"
+	KindGrid        Kind = "grid"
+	KindMasterStack Kind = "master-stack"
+	KindBSP         Kind = "bsp"
+)
+
+// Kinds lists all layout kinds in help/completion order.
+var Kinds = []Kind{KindColumns, KindRows, KindGrid, KindMasterStack, KindBSP}
+
+// Orientation controls the direction of the first split.
+type Orientation string
+
+// Orientation constants for Params.Orientation.
+const (
+	// Horizontal places the master (or first bsp half) on the left.
+	Horizontal Orientation = "horizontal"
+	// Vertical places the master (or first bsp half) on top.
+	Vertical Orientation = "vertical"
+)
+
+// Params holds the layout parameters.
+type Params struct {
+	Kind        Kind
+	MasterRatio float64 // share of the area given to the master window (master-stack only)
+	Gap         int     // pixels between adjacent windows
+	Margin      int     // pixels between the screen edge and windows
+	Orientation Orientation
+}
+
+// DefaultParams returns Params for kind with the default ratio and orientation.
+func DefaultParams(kind Kind) Params {
+	return Params{Kind: kind, MasterRatio: 0.5, Orientation: Horizontal}
+}
+
+// Placement pairs a window with its computed target frame.
+type Placement struct {
+	Window ax.Window
+	Frame  window.Rect
+}
+
+// Validate returns an error when p cannot be used to compute a layout.
+func (p Params) Validate() error {
+	known := false
+	for _, k := range Kinds {
+		if p.Kind == k {
+			known = true
+			break
+		}
+	}
+	if !known {
+		names := make([]string, len(Kinds))
+		for i, k := range Kinds {
+			names[i] = string(k)
+		}
+		return fmt.Errorf("unknown layout %q (valid: %s)", p.Kind, strings.Join(names, ", "))
+	}
+	if p.MasterRatio <= 0 || p.MasterRatio >= 1 {
+		return fmt.Errorf("master ratio must be between 0 and 1 (exclusive), got %g", p.MasterRatio)
+	}
+	if p.Gap < 0 {
+		return fmt.Errorf("gap must be >= 0")
+	}
+	if p.Margin < 0 {
+		return fmt.Errorf("margin must be >= 0")
+	}
+	if p.Orientation != Horizontal && p.Orientation != Vertical {
+		return fmt.Errorf("unknown orientation %q (valid: horizontal, vertical)", p.Orientation)
+	}
+	return nil
+}
+
+// Compute arranges windows inside screen according to p.
+// Windows are placed in the given order; the first window is the master.
+func Compute(screen window.Rect, windows []ax.Window, p Params) ([]Placement, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return nil, nil
+	}
+
+	area := window.Rect{
+		X: screen.X + p.Margin,
+		Y: screen.Y + p.Margin,
+		W: screen.W - 2*p.Margin,
+		H: screen.H - 2*p.Margin,
+	}
+	if area.W <= 0 || area.H <= 0 {
+		return nil, fmt.Errorf("margin %d leaves no usable area", p.Margin)
+	}
+
+	var frames []window.Rect
+	n := len(windows)
+	switch p.Kind {
+	case KindColumns:
+		frames = splitH(area, n, p.Gap)
+	case KindRows:
+		frames = splitV(area, n, p.Gap)
+	case KindGrid:
+		frames = grid(area, n, p.Gap)
+	case KindMasterStack:
+		frames = masterStack(area, n, p)
+	case KindBSP:
+		frames = bsp(area, n, p.Gap, p.Orientation == Horizontal)
+	}
+
+	placements := make([]Placement, n)
+	for i, w := range windows {
+		f := frames[i]
+		if f.W <= 0 || f.H <= 0 {
+			return nil, fmt.Errorf("gap %d leaves no room for %d windows", p.Gap, n)
+		}
+		placements[i] = Placement{Window: w, Frame: f}
+	}
+	return placements, nil
+}
+
+// splitH divides r into n side-by-side columns separated by gap.
+func splitH(r window.Rect, n, gap int) []window.Rect {
+	out := make([]window.Rect, n)
+	for i := range n {
+		x0, x1 := window.GridEdge(i, n, r.W, gap), window.GridEdge(i+1, n, r.W, gap)
+		out[i] = window.Rect{X: r.X + x0, Y: r.Y, W: x1 - x0 - gap, H: r.H}
+	}
+	return out
+}
+
+// splitV divides r into n stacked rows separated by gap.
+func splitV(r window.Rect, n, gap int) []window.Rect {
+	out := make([]window.Rect, n)
+	for i := range n {
+		y0, y1 := window.GridEdge(i, n, r.H, gap), window.GridEdge(i+1, n, r.H, gap)
+		out[i] = window.Rect{X: r.X, Y: r.Y + y0, W: r.W, H: y1 - y0 - gap}
+	}
+	return out
+}
+
+// grid arranges n windows in a near-square grid filled row by row.
+// A partially filled last row is stretched across the full width.
+func grid(r window.Rect, n, gap int) []window.Rect {
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+	rows := (n + cols - 1) / cols
+
+	out := make([]window.Rect, 0, n)
+	for i, row := range splitV(r, rows, gap) {
+		count := min(cols, n-i*cols)
+		out = append(out, splitH(row, count, gap)...)
+	}
+	return out
+}
+
+// masterStack gives the first window p.MasterRatio of the area and stacks the rest.
+func masterStack(r window.Rect, n int, p Params) []window.Rect {
+	if n == 1 {
+		return []window.Rect{r}
+	}
+	master, stack := splitRatio(r, p.MasterRatio, p.Gap, p.Orientation == Horizontal)
+	out := []window.Rect{master}
+	if p.Orientation == Horizontal {
+		return append(out, splitV(stack, n-1, p.Gap)...)
+	}
+	return append(out, splitH(stack, n-1, p.Gap)...)
+}
+
+// bsp recursively halves the area, alternating the split direction.
+func bsp(r window.Rect, n, gap int, horizontal bool) []window.Rect {
+	if n == 1 {
+		return []window.Rect{r}
+	}
+	first, rest := splitRatio(r, 0.5, gap, horizontal)
+	return append([]window.Rect{first}, bsp(rest, n-1, gap, !horizontal)...)
+}
+
+// splitRatio cuts r in two with ratio going to the first part, side by side when horizontal.
+func splitRatio(r window.Rect, ratio float64, gap int, horizontal bool) (window.Rect, window.Rect) {
+	if horizontal {
+		w := int(math.Round(float64(r.W-gap) * ratio))
+		return window.Rect{X: r.X, Y: r.Y, W: w, H: r.H},
+			window.Rect{X: r.X + w + gap, Y: r.Y, W: r.W - w - gap, H: r.H}
+	}
+	h := int(math.Round(float64(r.H-gap) * ratio))
+	return window.Rect{X: r.X, Y: r.Y, W: r.W, H: h},
+		window.Rect{X: r.X, Y: r.Y + h + gap, W: r.W, H: r.H - h - gap}
+}
@@ -0,0 +1,149 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/layout"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+var screenRect = window.Rect{X: 0, Y: 0, W: 1920, H: 1080}
+
+func nWindows(n int) []ax.Window {
+	ws := make([]ax.Window, n)
+	for i := range ws {
+		ws[i] = ax.Window{AppName: "App", PID: uint32(i + 1)}
+	}
+	return ws
+}
+
+func frames(t *testing.T, n int, p layout.Params) []window.Rect {
+	t.Helper()
+	placements, err := layout.Compute(screenRect, nWindows(n), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]window.Rect, len(placements))
+	for i, pl := range placements {
+		out[i] = pl.Frame
+	}
+	return out
+}
+
+func assertFrames(t *testing.T, got, want []window.Rect) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d frames, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("frame[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCompute_Columns(t *testing.T) {
+	p := layout.DefaultParams(layout.KindColumns)
+	assertFrames(t, frames(t, 3, p), []window.Rect{
+		{X: 0, Y: 0, W: 640, H: 1080},
+		{X: 640, Y: 0, W: 640, H: 1080},
+		{X: 1280, Y: 0, W: 640, H: 1080},
+	})
+}
+
+func TestCompute_RowsWithGapAndMargin(t *testing.T) {
+	p := layout.DefaultParams(layout.KindRows)
+	p.Gap = 10
+	p.Margin = 20
+	assertFrames(t, frames(t, 2, p), []window.Rect{
+		{X: 20, Y: 20, W: 1880, H: 515},
+		{X: 20, Y: 545, W: 1880, H: 515},
+	})
+}
+
+func TestCompute_Grid(t *testing.T) {
+	p := layout.DefaultParams(layout.KindGrid)
+	// 3 windows → 2 columns, 2 rows; the last row is stretched.
+	assertFrames(t, frames(t, 3, p), []window.Rect{
+		{X: 0, Y: 0, W: 960, H: 540},
+		{X: 960, Y: 0, W: 960, H: 540},
+		{X: 0, Y: 540, W: 1920, H: 540},
+	})
+}
+
+func TestCompute_MasterStack(t *testing.T) {
+	p := layout.DefaultParams(layout.KindMasterStack)
+	p.MasterRatio = 0.6
+	assertFrames(t, frames(t, 3, p), []window.Rect{
+		{X: 0, Y: 0, W: 1152, H: 1080},
+		{X: 1152, Y: 0, W: 768, H: 540},
+		{X: 1152, Y: 540, W: 768, H: 540},
+	})
+}
+
+func TestCompute_MasterStackVertical(t *testing.T) {
+	p := layout.DefaultParams(layout.KindMasterStack)
+	p.Orientation = layout.Vertical
+	assertFrames(t, frames(t, 3, p), []window.Rect{
+		{X: 0, Y: 0, W: 1920, H: 540},
+		{X: 0, Y: 540, W: 960, H: 540},
+		{X: 960, Y: 540, W: 960, H: 540},
+	})
+}
+
+func TestCompute_MasterStackSingle(t *testing.T) {
+	p := layout.DefaultParams(layout.KindMasterStack)
+	assertFrames(t, frames(t, 1, p), []window.Rect{screenRect})
+}
+
+func TestCompute_BSP(t *testing.T) {
+	p := layout.DefaultParams(layout.KindBSP)
+	assertFrames(t, frames(t, 4, p), []window.Rect{
+		{X: 0, Y: 0, W: 960, H: 1080},
+		{X: 960, Y: 0, W: 960, H: 540},
+		{X: 960, Y: 540, W: 480, H: 540},
+		{X: 1440, Y: 540, W: 480, H: 540},
+	})
+}
+
+func TestCompute_Empty(t *testing.T) {
+	placements, err := layout.Compute(screenRect, nil, layout.DefaultParams(layout.KindGrid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(placements) != 0 {
+		t.Errorf("expected no placements, got %d", len(placements))
+	}
+}
+
+func TestParams_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*layout.Params)
+	}{
+		{"unknown kind", func(p *layout.Params) { p.Kind = "spiral" }},
+		{"ratio zero", func(p *layout.Params) { p.MasterRatio = 0 }},
+		{"ratio one", func(p *layout.Params) { p.MasterRatio = 1 }},
+		{"negative gap", func(p *layout.Params) { p.Gap = -1 }},
+		{"negative margin", func(p *layout.Params) { p.Margin = -1 }},
+		{"unknown orientation", func(p *layout.Params) { p.Orientation = "diagonal" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := layout.DefaultParams(layout.KindColumns)
+			tt.modify(&p)
+			if err := p.Validate(); err == nil {
+				t.Error("expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestCompute_GapTooLarge(t *testing.T) {
+	p := layout.DefaultParams(layout.KindColumns)
+	p.Gap = 1000
+	if _, err := layout.Compute(screenRect, nWindows(3), p); err == nil {
+		t.Error("expected error when gaps leave no room")
+	}
+}
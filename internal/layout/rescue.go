@@ -0,0 +1,150 @@
+package layout
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// RescueTarget selects the screen stranded windows are moved to.
+type RescueTarget string
+
+// Rescue targets supported by Rescue.
+const (
+	RescueNearest RescueTarget = "nearest" // the screen closest to the window
+	RescuePrimary RescueTarget = "primary" // the primary screen
+)
+
+// DefaultMinVisible is the share of a window that must be on screen for Rescue to
+// leave it alone.
+const DefaultMinVisible = 0.5
+
+// RescueOptions holds the options for the rescue command.
+type RescueOptions struct {
+	Filter window.ListOptions
+	Target RescueTarget
+	// MinVisible is the share of its area (0-1) a window needs on screen to be left
+	// where it is; windows entirely off-screen are always rescued.
+	MinVisible float64
+	DryRun     bool
+}
+
+// Rescued is a window found off-screen and the frame it is moved to.
+type Rescued struct {
+	Window  ax.Window
+	Frame   window.Rect
+	Screen  ax.Screen // the screen the window is moved onto
+	Visible float64   // share of the window that was on screen before the rescue
+}
+
+// Validate returns an error when opts cannot be used to rescue windows.
+func (opts RescueOptions) Validate() error {
+	switch opts.Target {
+	case RescueNearest, RescuePrimary:
+	default:
+		return fmt.Errorf("invalid rescue target %q (want %s or %s)", opts.Target, RescueNearest, RescuePrimary)
+	}
+	if opts.MinVisible < 0 || opts.MinVisible > 1 {
+		return fmt.Errorf("min visible share must be between 0 and 1, got %g", opts.MinVisible)
+	}
+	return nil
+}
+
+// Rescue finds normal windows that are entirely or mostly off-screen, typically left
+// at the coordinates of a disconnected display, and moves them onto the target
+// screen, shrinking them to fit. When opts.DryRun is set, the plan is returned
+// without moving any window. No match is not an error; the plan is then empty.
+func Rescue(ctx context.Context, svc ax.WindowService, opts RescueOptions) ([]Rescued, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	windows, err := window.List(ctx, svc, opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+	screens, err := svc.ListScreens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(screens) == 0 {
+		return nil, fmt.Errorf("no screens found")
+	}
+
+	var plan []Rescued
+	for _, w := range windows {
+		if w.State != ax.StateNormal || w.Width <= 0 || w.Height <= 0 {
+			continue
+		}
+		visible := visibleShare(screens, w)
+		if visible > 0 && visible >= opts.MinVisible {
+			continue
+		}
+		s := rescueScreen(screens, w, opts.Target)
+		frame := window.ClampFrame(w.Frame(), s)
+		plan = append(plan, Rescued{Window: w, Frame: frame, Screen: s, Visible: visible})
+	}
+
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	var affected []ax.Window
+	for _, r := range plan {
+		w := r.Window
+		if err := svc.MoveWindow(ctx, w.ID, r.Frame.X, r.Frame.Y); err != nil {
+			return plan, applyError(affected, err)
+		}
+		if r.Frame.W != w.Width || r.Frame.H != w.Height {
+			if err := svc.ResizeWindow(ctx, w.ID, r.Frame.W, r.Frame.H); err != nil {
+				// the window has already moved
+				w.X, w.Y = r.Frame.X, r.Frame.Y
+				return plan, applyError(append(affected, w), err)
+			}
+		}
+		w.X, w.Y, w.Width, w.Height = r.Frame.X, r.Frame.Y, r.Frame.W, r.Frame.H
+		affected = append(affected, w)
+	}
+	return plan, nil
+}
+
+// visibleShare returns the share of the area of w that lies on any screen.
+// Screens never overlap, so the overlaps with each screen add up.
+func visibleShare(screens []ax.Screen, w ax.Window) float64 {
+	area := 0
+	for _, s := range screens {
+		ix := min(w.X+w.Width, s.X+s.Width) - max(w.X, s.X)
+		iy := min(w.Y+w.Height, s.Y+s.Height) - max(w.Y, s.Y)
+		if ix > 0 && iy > 0 {
+			area += ix * iy
+		}
+	}
+	return float64(area) / float64(w.Width*w.Height)
+}
+
+// rescueScreen returns the screen w is moved onto.
+func rescueScreen(screens []ax.Screen, w ax.Window, target RescueTarget) ax.Screen {
+	if target == RescuePrimary {
+		for _, s := range screens {
+			if s.IsPrimary {
+				return s
+			}
+		}
+		return window.OrderScreens(screens)[0]
+	}
+
+	// nearest: the screen with the smallest distance from the window's center
+	cx, cy := float64(w.X)+float64(w.Width)/2, float64(w.Y)+float64(w.Height)/2
+	best, bestDist := screens[0], math.Inf(1)
+	for _, s := range window.OrderScreens(screens) {
+		dx := math.Max(math.Max(float64(s.X)-cx, 0), cx-float64(s.X+s.Width))
+		dy := math.Max(math.Max(float64(s.Y)-cy, 0), cy-float64(s.Y+s.Height))
+		if d := math.Hypot(dx, dy); d < bestDist {
+			best, bestDist = s, d
+		}
+	}
+	return best
+}
@@ -0,0 +1,95 @@
+package layout_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/layout"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+var rescueScreens = []ax.Screen{
+	{ID: 2, Name: "External", X: 1920, Y: 0, Width: 2560, Height: 1440},
+	{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1920, Height: 1080, IsPrimary: true},
+}
+
+var rescueWindows = []ax.Window{
+	// on screen
+	{ID: 1, AppName: "Code", Title: "main.go", X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal, ScreenID: 1},
+	// left of every screen, on a disconnected display
+	{ID: 2, AppName: "Terminal", Title: "zsh", X: -2000, Y: 100, Width: 800, Height: 600, State: ax.StateNormal},
+	// a quarter on the external screen, larger than it
+	{ID: 3, AppName: "Safari", Title: "GitHub", X: 3840, Y: 0, Width: 2560, Height: 1600, State: ax.StateNormal, ScreenID: 2},
+	// off-screen but minimized
+	{ID: 4, AppName: "Notes", Title: "Todo", X: -2000, Y: 0, Width: 400, Height: 400, State: ax.StateMinimized},
+}
+
+func TestRescue_Nearest(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: rescueWindows, Screens: rescueScreens}
+	plan, err := layout.Rescue(context.Background(), svc, layout.RescueOptions{
+		Target:     layout.RescueNearest,
+		MinVisible: layout.DefaultMinVisible,
+		DryRun:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 2 {
+		t.Fatalf("expected 2 rescued windows, got %d", len(plan))
+	}
+	if plan[0].Window.ID != 2 || plan[0].Screen.ID != 1 || plan[0].Frame != (window.Rect{X: 0, Y: 100, W: 800, H: 600}) || plan[0].Visible != 0 {
+		t.Errorf("Terminal: %+v", plan[0])
+	}
+	// shrunk to the screen and moved the shortest distance onto it
+	if plan[1].Window.ID != 3 || plan[1].Screen.ID != 2 || plan[1].Frame != (window.Rect{X: 1920, Y: 0, W: 2560, H: 1440}) {
+		t.Errorf("Safari: %+v", plan[1])
+	}
+}
+
+func TestRescue_PrimaryAndMinVisible(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: rescueWindows, Screens: rescueScreens}
+	// with a threshold of 0, only windows entirely off-screen are rescued
+	plan, err := layout.Rescue(context.Background(), svc, layout.RescueOptions{
+		Target: layout.RescuePrimary,
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 1 || plan[0].Window.ID != 2 || plan[0].Screen.ID != 1 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+}
+
+func TestRescue_Apply(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: rescueWindows, Screens: rescueScreens}
+	plan, err := layout.Rescue(context.Background(), svc, layout.RescueOptions{
+		Target:     layout.RescueNearest,
+		MinVisible: layout.DefaultMinVisible,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 2 {
+		t.Fatalf("expected 2 rescued windows, got %d", len(plan))
+	}
+}
+
+func TestRescueOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts layout.RescueOptions
+	}{
+		{"unknown target", layout.RescueOptions{Target: "left"}},
+		{"negative share", layout.RescueOptions{Target: layout.RescueNearest, MinVisible: -0.1}},
+		{"share above 1", layout.RescueOptions{Target: layout.RescueNearest, MinVisible: 1.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package layout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// TileOptions holds the options for the tile command.
+type TileOptions struct {
+	Filter window.ListOptions
+	Params Params
+	DryRun bool
+}
+
+// Tile arranges all matching windows into the layout described by opts.Params.
+// Windows are grouped by screen and each screen is tiled independently.
+// Only normal (on-screen, non-fullscreen) windows take part.
+// When opts.DryRun is set, the plan is returned without moving any window.
+func Tile(ctx context.Context, svc ax.WindowService, opts TileOptions) ([]Placement, error) {
+	if err := opts.Params.Validate(); err != nil {
+		return nil, err
+	}
+
+	windows, err := window.List(ctx, svc, opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+	screens, err := svc.ListScreens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group windows by screen, keeping both screen order and window order stable.
+	byScreen := make(map[uint32][]ax.Window)
+	for _, w := range windows {
+		if w.State != ax.StateNormal {
+			continue
+		}
+		if _, ok := window.ScreenOf(screens, w); !ok {
+			continue
+		}
+		byScreen[w.ScreenID] = append(byScreen[w.ScreenID], w)
+	}
+	if len(byScreen) == 0 {
+		return nil, &ax.NotFoundError{Query: buildQuery(opts.Filter)}
+	}
+
+	var plan []Placement
+	for _, s := range screens {
+		group := byScreen[s.ID]
+		if len(group) == 0 {
+			continue
+		}
+		placements, err := Compute(s.Bounds(), group, opts.Params)
+		if err != nil {
+			return nil, fmt.Errorf("screen %q: %w", s.Name, err)
+		}
+		plan = append(plan, placements...)
+	}
+
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	var affected []ax.Window
+	for _, p := range plan {
+		w := p.Window
+		if err := svc.MoveWindow(ctx, w.ID, p.Frame.X, p.Frame.Y); err != nil {
+			return plan, applyError(affected, err)
+		}
+		if err := svc.ResizeWindow(ctx, w.ID, p.Frame.W, p.Frame.H); err != nil {
+			// the window has already moved
+			w.X, w.Y = p.Frame.X, p.Frame.Y
+			return plan, applyError(append(affected, w), err)
+		}
+		w.X, w.Y, w.Width, w.Height = p.Frame.X, p.Frame.Y, p.Frame.W, p.Frame.H
+		affected = append(affected, w)
+	}
+	return plan, nil
+}
+
+// applyError wraps err in a PartialSuccessError when some windows were already tiled.
+func applyError(affected []ax.Window, err error) error {
+	if len(affected) > 0 {
+		return &ax.PartialSuccessError{Affected: affected, Cause: err}
+	}
+	return err
+}
+
+func buildQuery(opts window.ListOptions) string {
+	parts := make([]string, 0)
+	if opts.AppFilter != "" {
+		parts = append(parts, `--app "`+opts.AppFilter+`"`)
+	}
+	if opts.ScreenFilter != "" {
+		parts = append(parts, `--screen "`+opts.ScreenFilter+`"`)
+	}
+	if opts.DesktopFilter != 0 {
+		parts = append(parts, fmt.Sprintf("--desktop %d", opts.DesktopFilter))
+	}
+	if len(parts) == 0 {
+		return "(no filter)"
+	}
+	return strings.Join(parts, " ")
+}
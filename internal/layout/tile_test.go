@@ -0,0 +1,131 @@
+package layout_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/layout"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+var tileScreens = []ax.Screen{
+	{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1920, Height: 1080, IsPrimary: true},
+	{ID: 2, Name: "External", X: 1920, Y: 0, Width: 2560, Height: 1440},
+}
+
+var tileWindows = []ax.Window{
+	{ID: 1, AppName: "Code", Title: "main.go", PID: 100, State: ax.StateNormal, ScreenID: 1},
+	{ID: 2, AppName: "Terminal", Title: "zsh", PID: 200, State: ax.StateNormal, ScreenID: 1},
+	{ID: 3, AppName: "Safari", Title: "GitHub", PID: 300, State: ax.StateNormal, ScreenID: 2},
+	{ID: 4, AppName: "Safari", Title: "Apple", PID: 300, State: ax.StateMinimized},
+	{ID: 5, AppName: "Code", Title: "README.md", PID: 100, State: ax.StateFullscreen, ScreenID: 2},
+}
+
+func TestTile_PerScreen(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: tileWindows, Screens: tileScreens}
+	plan, err := layout.Tile(context.Background(), svc, layout.TileOptions{
+		Params: layout.DefaultParams(layout.KindColumns),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Minimized and fullscreen windows are left alone.
+	if len(plan) != 3 {
+		t.Fatalf("expected 3 placements, got %d", len(plan))
+	}
+	if plan[0].Frame != (window.Rect{X: 0, Y: 0, W: 960, H: 1080}) {
+		t.Errorf("Code frame = %+v", plan[0].Frame)
+	}
+	if plan[1].Frame != (window.Rect{X: 960, Y: 0, W: 960, H: 1080}) {
+		t.Errorf("Terminal frame = %+v", plan[1].Frame)
+	}
+	if plan[2].Frame != (window.Rect{X: 1920, Y: 0, W: 2560, H: 1440}) {
+		t.Errorf("Safari frame = %+v", plan[2].Frame)
+	}
+}
+
+func TestTile_AppFilter(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: tileWindows, Screens: tileScreens}
+	plan, err := layout.Tile(context.Background(), svc, layout.TileOptions{
+		Filter: window.ListOptions{AppFilter: "terminal"},
+		Params: layout.DefaultParams(layout.KindGrid),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 1 || plan[0].Window.AppName != "Terminal" {
+		t.Fatalf("expected only Terminal, got %+v", plan)
+	}
+}
+
+func TestTile_DryRunDoesNotMove(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: tileWindows,
+		Screens: tileScreens,
+		MoveErr: errors.New("must not be called"),
+	}
+	plan, err := layout.Tile(context.Background(), svc, layout.TileOptions{
+		Params: layout.DefaultParams(layout.KindRows),
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("dry run should not call MoveWindow: %v", err)
+	}
+	if len(plan) != 3 {
+		t.Errorf("expected 3 placements, got %d", len(plan))
+	}
+}
+
+func TestTile_NotFound(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: tileWindows, Screens: tileScreens}
+	_, err := layout.Tile(context.Background(), svc, layout.TileOptions{
+		Filter: window.ListOptions{AppFilter: "Slack"},
+		Params: layout.DefaultParams(layout.KindColumns),
+	})
+	var notFound *ax.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected *ax.NotFoundError, got %T: %v", err, err)
+	}
+}
+
+func TestTile_MoveError(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: tileWindows,
+		Screens: tileScreens,
+		MoveErr: errors.New("AX error"),
+	}
+	_, err := layout.Tile(context.Background(), svc, layout.TileOptions{
+		Params: layout.DefaultParams(layout.KindColumns),
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	var partial *ax.PartialSuccessError
+	if errors.As(err, &partial) {
+		t.Error("first window failed; expected a plain error, not partial success")
+	}
+}
+
+func TestTile_ResizeError(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows:   tileWindows,
+		Screens:   tileScreens,
+		ResizeErr: errors.New("AX error"),
+	}
+	plan, err := layout.Tile(context.Background(), svc, layout.TileOptions{
+		Params: layout.DefaultParams(layout.KindColumns),
+	})
+	// the first window moved before its resize failed
+	var partial *ax.PartialSuccessError
+	if !errors.As(err, &partial) {
+		t.Fatalf("expected *ax.PartialSuccessError, got %T: %v", err, err)
+	}
+	if len(partial.Affected) != 1 {
+		t.Fatalf("expected 1 affected window, got %d", len(partial.Affected))
+	}
+	if w, f := partial.Affected[0], plan[0].Frame; w.X != f.X || w.Y != f.Y {
+		t.Errorf("affected window at (%d,%d), want its new position (%d,%d)", w.X, w.Y, f.X, f.Y)
+	}
+}
@@ -0,0 +1,246 @@
+package output
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+)
+
+// Column is a window field that can be shown in window tables (list output and
+// ambiguous-target candidates) and used as a sort key.
+type Column struct {
+	// Name is the JSON field name of ax.Window, used by --columns, --sort and as the
+	// header of CSV and TSV output.
+	Name   string
+	Header string // header in text tables
+	// Width truncates values in text tables to this many runes (0 = no limit).
+	Width int
+
+	value   func(ax.Window) string // value shown in text tables
+	raw     func(ax.Window) string // value written to CSV and TSV (the JSON value)
+	compare func(a, b ax.Window) int
+}
+
+func strColumn(name, header string, width int, get func(ax.Window) string) Column {
+	return Column{
+		Name: name, Header: header, Width: width,
+		value: get,
+		raw:   get,
+		compare: func(a, b ax.Window) int {
+			return cmp.Compare(strings.ToLower(get(a)), strings.ToLower(get(b)))
+		},
+	}
+}
+
+func intColumn(name, header string, get func(ax.Window) int) Column {
+	value := func(w ax.Window) string { return strconv.Itoa(get(w)) }
+	return Column{
+		Name: name, Header: header,
+		value:   value,
+		raw:     value,
+		compare: func(a, b ax.Window) int { return cmp.Compare(get(a), get(b)) },
+	}
+}
+
+// windowColumns lists every column in the field order of ax.Window.
+var windowColumns = []Column{
+	intColumn("id", "ID", func(w ax.Window) int { return int(w.ID) }),
+	strColumn("app_name", "APP_NAME", 0, func(w ax.Window) string { return w.AppName }),
+	strColumn("bundle_id", "BUNDLE_ID", 0, func(w ax.Window) string { return w.BundleID }),
+	strColumn("title", "TITLE", 32, func(w ax.Window) string { return w.Title }),
+	intColumn("pid", "PID", func(w ax.Window) int { return int(w.PID) }),
+	intColumn("x", "X", func(w ax.Window) int { return w.X }),
+	intColumn("y", "Y", func(w ax.Window) int { return w.Y }),
+	intColumn("width", "WIDTH", func(w ax.Window) int { return w.Width }),
+	intColumn("height", "HEIGHT", func(w ax.Window) int { return w.Height }),
+	strColumn("state", "STATE", 0, func(w ax.Window) string { return string(w.State) }),
+	intColumn("screen_id", "SCREEN_ID", func(w ax.Window) int { return int(w.ScreenID) }),
+	{
+		Name: "screen_name", Header: "SCREEN", Width: 20,
+		value: func(w ax.Window) string {
+			// minimized and hidden windows are not on any screen
+			if w.State == ax.StateMinimized || w.State == ax.StateHidden {
+				return ""
+			}
+			return w.ScreenName
+		},
+		raw: func(w ax.Window) string { return w.ScreenName },
+		compare: func(a, b ax.Window) int {
+			return cmp.Compare(strings.ToLower(a.ScreenName), strings.ToLower(b.ScreenName))
+		},
+	},
+	{
+		Name: "desktop", Header: "DESKTOP",
+		value:   func(w ax.Window) string { return formatDesktop(w.Desktop) },
+		raw:     func(w ax.Window) string { return strconv.Itoa(w.Desktop) },
+		compare: func(a, b ax.Window) int { return cmp.Compare(a.Desktop, b.Desktop) },
+	},
+}
+
+// columnAliases maps shorthand column names to JSON field names.
+var columnAliases = map[string]string{
+	"app":    "app_name",
+	"screen": "screen_name",
+}
+
+// DefaultColumns are the columns of mado list text output when --columns is not given.
+// CSV and TSV output default to every column.
+var DefaultColumns = []string{"id", "app_name", "bundle_id", "title", "x", "y", "width", "height", "state", "desktop", "screen_name"}
+
+// candidateColumns are the columns of the candidates printed with an ambiguous-target error.
+var candidateColumns = []string{"id", "app_name", "title", "pid"}
+
+// ColumnNames returns the names accepted by ParseColumns and ParseSort.
+func ColumnNames() []string {
+	names := make([]string, len(windowColumns))
+	for i, c := range windowColumns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func lookupColumn(name string) (Column, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := columnAliases[name]; ok {
+		name = alias
+	}
+	for _, c := range windowColumns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+func unknownColumnError(name string) error {
+	return fmt.Errorf("unknown column %q (want one of %s)", name, strings.Join(ColumnNames(), ", "))
+}
+
+// SortKey orders windows by a column, descending when Desc is set.
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// TableOptions selects the columns, row order and header of window tables.
+// The zero value prints DefaultColumns in the order the windows were given.
+type TableOptions struct {
+	Columns  []string // column names; nil = the default columns of the table
+	Sort     []SortKey
+	NoHeader bool
+}
+
+// ParseColumns parses a comma-separated column list such as "app,title,x,y".
+func ParseColumns(spec string) ([]string, error) {
+	var cols []string
+	for _, name := range strings.Split(spec, ",") {
+		c, ok := lookupColumn(name)
+		if !ok {
+			return nil, unknownColumnError(strings.TrimSpace(name))
+		}
+		cols = append(cols, c.Name)
+	}
+	return cols, nil
+}
+
+// ParseSort parses a comma-separated list of sort keys such as "app,title,-width".
+// A leading "-" sorts that key in descending order and "+" in ascending order.
+func ParseSort(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		key := SortKey{}
+		switch {
+		case strings.HasPrefix(field, "-"):
+			key.Desc = true
+			field = field[1:]
+		case strings.HasPrefix(field, "+"):
+			field = field[1:]
+		}
+		c, ok := lookupColumn(field)
+		if !ok {
+			return nil, unknownColumnError(field)
+		}
+		key.Column = c.Name
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// SetTableOptions configures the columns, sort order and header of window tables.
+// Sorting also applies to the windows of structured output.
+func (f *Formatter) SetTableOptions(opts TableOptions) {
+	f.table = opts
+}
+
+// sortWindows returns windows ordered by the configured sort keys.
+// The order of windows that compare equal is preserved.
+func (f *Formatter) sortWindows(windows []ax.Window) []ax.Window {
+	if len(f.table.Sort) == 0 {
+		return windows
+	}
+	sorted := slices.Clone(windows)
+	slices.SortStableFunc(sorted, func(a, b ax.Window) int {
+		for _, k := range f.table.Sort {
+			c, _ := lookupColumn(k.Column)
+			if r := c.compare(a, b); r != 0 {
+				if k.Desc {
+					return -r
+				}
+				return r
+			}
+		}
+		return 0
+	})
+	return sorted
+}
+
+// columns resolves the configured columns, falling back to defaults.
+func (f *Formatter) columns(defaults []string) []Column {
+	names := f.table.Columns
+	if names == nil {
+		names = defaults
+	}
+	cols := make([]Column, 0, len(names))
+	for _, name := range names {
+		if c, ok := lookupColumn(name); ok {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+// writeTable renders windows as an aligned text table, each line prefixed with indent.
+// Empty values are shown as "-".
+func (f *Formatter) writeTable(out io.Writer, indent string, cols []Column, windows []ax.Window) error {
+	// align columns with tabwriter (min width 8, tab width 1, padding 2)
+	tw := tabwriter.NewWriter(out, 8, 1, 2, ' ', 0)
+	if !f.table.NoHeader {
+		headers := make([]string, len(cols))
+		for i, c := range cols {
+			headers[i] = c.Header
+		}
+		fmt.Fprintln(tw, indent+strings.Join(headers, "\t")) //nolint:errcheck // tabwriter defers errors to Flush()
+	}
+	for _, w := range windows {
+		cells := make([]string, len(cols))
+		for i, c := range cols {
+			v := c.value(w)
+			if c.Width > 0 {
+				v = truncate(v, c.Width)
+			}
+			if v == "" {
+				v = "-"
+			}
+			cells[i] = v
+		}
+		fmt.Fprintln(tw, indent+strings.Join(cells, "\t")) //nolint:errcheck // tabwriter defers errors to Flush()
+	}
+	return tw.Flush()
+}
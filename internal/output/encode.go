@@ -0,0 +1,440 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/history"
+	"github.com/peacock0803sz/mado/internal/preset"
+)
+
+// templatePrefix starts a template format, e.g. "template={{.app_name}} {{.title}}".
+const templatePrefix = string(FormatTemplate) + "="
+
+// FormatNames lists the accepted --format values, with the template form last.
+func FormatNames() []string {
+	return []string{
+		string(FormatText), string(FormatJSON), string(FormatNDJSON), string(FormatCSV),
+		string(FormatTSV), string(FormatYAML), string(FormatMap), string(FormatSVG), templatePrefix + "<go template>",
+	}
+}
+
+// ParseFormat validates a --format value and returns it as a Format.
+// Template formats are checked for syntax errors.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatText, FormatJSON, FormatNDJSON, FormatCSV, FormatTSV, FormatYAML, FormatMap, FormatSVG:
+		return f, nil
+	}
+	if text, ok := strings.CutPrefix(s, templatePrefix); ok {
+		if _, err := parseTemplate(text); err != nil {
+			return "", err
+		}
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("invalid format %q (must be one of %s)", s, strings.Join(FormatNames(), ", "))
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("format").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// structured reports whether f writes machine-readable output instead of text.
+func (f *Formatter) structured() bool {
+	switch f.format {
+	case FormatJSON, FormatNDJSON, FormatCSV, FormatTSV, FormatYAML, FormatTemplate:
+		return true
+	}
+	return false
+}
+
+// errorsAsText reports whether errors are printed as text on stderr even though the
+// output is structured: CSV, TSV and template output have no place for them.
+func (f *Formatter) errorsAsText() bool {
+	return f.format == FormatCSV || f.format == FormatTSV || f.format == FormatTemplate
+}
+
+// records converts a slice into the records of ndjson, csv, tsv and template output.
+func records[T any](s []T) []any {
+	out := make([]any, len(s))
+	for i, v := range s {
+		out[i] = v
+	}
+	return out
+}
+
+// printStructured writes resp in a machine-readable format. JSON and YAML encode resp
+// as a whole; NDJSON, CSV, TSV and templates write one line per record, using the
+// JSON field names of the records.
+func (f *Formatter) printStructured(resp any, recs []any) error {
+	switch f.format {
+	case FormatJSON:
+		return f.printJSON(resp)
+	case FormatYAML:
+		return f.printYAML(resp)
+	case FormatNDJSON:
+		for _, r := range recs {
+			data, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(f.out, "%s\n", data); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatTemplate:
+		return f.printTemplate(recs)
+	default:
+		return f.printDelimited(recs)
+	}
+}
+
+// printWindowsStructured is printStructured for window lists: CSV and TSV use the
+// configured columns (every column by default).
+func (f *Formatter) printWindowsStructured(resp any, windows []ax.Window) error {
+	if f.format != FormatCSV && f.format != FormatTSV {
+		return f.printStructured(resp, records(windows))
+	}
+	cols := f.columns(ColumnNames())
+	var rows [][]string
+	if !f.table.NoHeader {
+		header := make([]string, len(cols))
+		for i, c := range cols {
+			header[i] = c.Name
+		}
+		rows = append(rows, header)
+	}
+	for _, w := range windows {
+		row := make([]string, len(cols))
+		for i, c := range cols {
+			row[i] = c.raw(w)
+		}
+		rows = append(rows, row)
+	}
+	return f.writeDelimited(rows)
+}
+
+func (f *Formatter) printYAML(v any) error {
+	ov, err := toOrdered(v)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Dump(yamlNode(ov), yaml.WithIndent(2), yaml.WithCompactSeqIndent(false))
+	if err != nil {
+		return err
+	}
+	_, err = f.out.Write(data)
+	return err
+}
+
+// printTemplate executes the template once per record, each followed by a newline.
+// Records are passed as maps keyed by their JSON field names.
+func (f *Formatter) printTemplate(recs []any) error {
+	if f.tmplErr != nil {
+		return f.tmplErr
+	}
+	for _, r := range recs {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		if err := f.tmpl.Execute(f.out, v); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f.out, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printDelimited writes records as CSV or TSV. Nested objects are flattened into
+// dotted column names (e.g. "target.x") and arrays are written as JSON. The header
+// is the union of the columns of all records in first-seen order.
+func (f *Formatter) printDelimited(recs []any) error {
+	var header []string
+	seen := make(map[string]bool)
+	flat := make([]map[string]string, len(recs))
+	for i, r := range recs {
+		ov, err := toOrdered(r)
+		if err != nil {
+			return err
+		}
+		var fields orderedObject
+		flatten("", ov, &fields)
+		flat[i] = make(map[string]string, len(fields))
+		for _, fl := range fields {
+			if !seen[fl.key] {
+				seen[fl.key] = true
+				header = append(header, fl.key)
+			}
+			flat[i][fl.key] = fl.value.(string)
+		}
+	}
+	if len(recs) == 0 {
+		return nil
+	}
+	var rows [][]string
+	if !f.table.NoHeader {
+		rows = append(rows, header)
+	}
+	for _, m := range flat {
+		row := make([]string, len(header))
+		for i, k := range header {
+			row[i] = m[k]
+		}
+		rows = append(rows, row)
+	}
+	return f.writeDelimited(rows)
+}
+
+// tsvEscaper escapes the characters that would break TSV rows.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func (f *Formatter) writeDelimited(rows [][]string) error {
+	if f.format == FormatTSV {
+		for _, row := range rows {
+			cells := make([]string, len(row))
+			for i, c := range row {
+				cells[i] = tsvEscaper.Replace(c)
+			}
+			if _, err := fmt.Fprintln(f.out, strings.Join(cells, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	w := csv.NewWriter(f.out)
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+// --- order-preserving JSON values ---
+
+// orderedField is a key of a JSON object with its value.
+type orderedField struct {
+	key   string
+	value any
+}
+
+// orderedObject is a JSON object that keeps the field order of the encoded struct.
+type orderedObject []orderedField
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, fl := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := json.Marshal(fl.key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(fl.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// toOrdered encodes v as JSON and decodes it into orderedObject, []any, string,
+// json.Number, bool and nil values.
+func toOrdered(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeOrdered(dec)
+}
+
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+	switch delim {
+	case '{':
+		obj := orderedObject{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			val, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, orderedField{key: key.(string), value: val})
+		}
+		_, err = dec.Token() // '}'
+		return obj, err
+	default: // '['
+		arr := []any{}
+		for dec.More() {
+			val, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, val)
+		}
+		_, err = dec.Token() // ']'
+		return arr, err
+	}
+}
+
+// flatten appends the scalar cells of v to out, naming nested fields "parent.child".
+func flatten(prefix string, v any, out *orderedObject) {
+	if obj, ok := v.(orderedObject); ok {
+		for _, fl := range obj {
+			key := fl.key
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			flatten(key, fl.value, out)
+		}
+		return
+	}
+	var cell string
+	switch t := v.(type) {
+	case nil:
+	case string:
+		cell = t
+	case json.Number:
+		cell = t.String()
+	case bool:
+		cell = fmt.Sprint(t)
+	default: // arrays
+		data, _ := json.Marshal(t)
+		cell = string(data)
+	}
+	*out = append(*out, orderedField{key: prefix, value: cell})
+}
+
+// yamlNode converts an order-preserving JSON value into a YAML node.
+func yamlNode(v any) *yaml.Node {
+	switch t := v.(type) {
+	case orderedObject:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, fl := range t {
+			n.Content = append(n.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fl.key},
+				yamlNode(fl.value))
+		}
+		return n
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, e := range t {
+			n.Content = append(n.Content, yamlNode(e))
+		}
+		return n
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(t)}
+	default: // nil
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+}
+
+// --- records of ndjson, csv, tsv and template output ---
+
+// ruleRecord is a preset rule that produced no window records: a skipped rule
+// (Reason) or a rule that failed (Error).
+type ruleRecord struct {
+	RuleIndex int    `json:"rule_index"`
+	AppFilter string `json:"app_filter"`
+	Reason    string `json:"reason,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ruleWindowRecord is a window changed by a preset rule.
+type ruleWindowRecord struct {
+	RuleIndex int    `json:"rule_index"`
+	AppFilter string `json:"app_filter"`
+	ax.Window
+}
+
+// planRecord is a window matched by a preset rule with its target frame.
+type planRecord struct {
+	RuleIndex int    `json:"rule_index"`
+	AppFilter string `json:"app_filter"`
+	PresetPlanWindow
+}
+
+// diffRecord is a window matched by a preset rule with its drift.
+type diffRecord struct {
+	RuleIndex int    `json:"rule_index"`
+	AppFilter string `json:"app_filter"`
+	PresetDiffWindow
+}
+
+// presetRuleRecord is a rule of preset show output.
+type presetRuleRecord struct {
+	Preset    string `json:"preset"`
+	RuleIndex int    `json:"rule_index"`
+	preset.Rule
+}
+
+// restoreRecord is a window of undo or redo output; Status is "restored" or "missing".
+type restoreRecord struct {
+	Status string `json:"status"`
+	ax.Window
+}
+
+// historyRecord is an entry of history output; Stack is "undo" or "redo".
+type historyRecord struct {
+	Stack string `json:"stack"`
+	history.Entry
+}
+
+func diffRecords(resp PresetDiffResponse) []any {
+	var recs []any
+	for _, r := range resp.Rules {
+		for _, d := range r.Windows {
+			recs = append(recs, diffRecord{RuleIndex: r.RuleIndex, AppFilter: r.AppFilter, PresetDiffWindow: d})
+		}
+		if len(r.Windows) == 0 || r.Error != "" {
+			recs = append(recs, ruleRecord{RuleIndex: r.RuleIndex, AppFilter: r.AppFilter, Reason: r.Reason, Error: r.Error})
+		}
+	}
+	return recs
+}
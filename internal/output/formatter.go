@@ -1,4 +1,5 @@
-// Package output formats mado command results as text or JSON.
+// Package output formats mado command results as text, JSON, NDJSON, CSV, TSV, YAML,
+// a Go template, a screen map or an SVG drawing.
 package output
 
 import (
@@ -7,10 +8,16 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"strings"
 	"text/tabwriter"
+	"text/template"
+	"time"
 
 	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/history"
 	"github.com/peacock0803sz/mado/internal/preset"
+	"github.com/peacock0803sz/mado/internal/profile"
+	"github.com/peacock0803sz/mado/internal/window"
 )
 
 // Format represents the type of output format.
@@ -18,8 +25,21 @@ type Format string
 
 // Output format constants.
 const (
-	FormatText Format = "text"
-	FormatJSON Format = "json"
+	FormatText   Format = "text"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson" // one JSON object per line
+	FormatCSV    Format = "csv"
+	FormatTSV    Format = "tsv"
+	FormatYAML   Format = "yaml"
+	// FormatMap draws windows on a map of the screens (see PrintWindowMap). Commands
+	// without a map print text.
+	FormatMap Format = "map"
+	// FormatSVG draws screens and windows as a self-contained SVG image (see
+	// PrintLayoutSVG). Commands without a drawing print text.
+	FormatSVG Format = "svg"
+	// FormatTemplate executes a Go template per record. Pass it to New as
+	// "template=<go template>", e.g. "template={{.app_name}}: {{.title}}".
+	FormatTemplate Format = "template"
 )
 
 // ListResponse is the JSON output schema for the list command.
@@ -50,16 +70,24 @@ type ErrorDetail struct {
 	Candidates []ax.Window `json:"candidates,omitempty"`
 }
 
-// Formatter writes output in either text or JSON format.
+// Formatter writes output in one of the Format formats.
 type Formatter struct {
-	format Format
-	out    io.Writer
-	errOut io.Writer
+	format  Format
+	out     io.Writer
+	errOut  io.Writer
+	table   TableOptions
+	tmpl    *template.Template
+	tmplErr error
 }
 
-// New creates a new Formatter.
+// New creates a new Formatter. Unknown formats fall back to text.
 func New(format Format, out, errOut io.Writer) *Formatter {
-	return &Formatter{format: format, out: out, errOut: errOut}
+	f := &Formatter{format: format, out: out, errOut: errOut}
+	if text, ok := strings.CutPrefix(string(format), templatePrefix); ok {
+		f.format = FormatTemplate
+		f.tmpl, f.tmplErr = parseTemplate(text)
+	}
+	return f
 }
 
 // IsTerminal reports whether stdout is connected to a TTY.
@@ -73,24 +101,25 @@ func IsTerminal() bool {
 
 // PrintWindows outputs the list of windows.
 func (f *Formatter) PrintWindows(windows []ax.Window) error {
-	if f.format == FormatJSON {
-		return f.printJSON(ListResponse{
+	windows = f.sortWindows(windows)
+	if f.structured() {
+		return f.printWindowsStructured(ListResponse{
 			SchemaVersion: 1,
 			Success:       true,
 			Windows:       windows,
-		})
+		}, windows)
 	}
 	return f.printWindowsText(windows)
 }
 
 // PrintMoveResult outputs the result of a move operation.
 func (f *Formatter) PrintMoveResult(affected []ax.Window) error {
-	if f.format == FormatJSON {
-		return f.printJSON(MoveResponse{
+	if f.structured() {
+		return f.printWindowsStructured(MoveResponse{
 			SchemaVersion: 1,
 			Success:       true,
 			Affected:      affected,
-		})
+		}, affected)
 	}
 	for _, w := range affected {
 		if _, err := fmt.Fprintf(f.out, "Moved: %s %q → (%d, %d)\n", w.AppName, w.Title, w.X, w.Y); err != nil {
@@ -101,19 +130,21 @@ func (f *Formatter) PrintMoveResult(affected []ax.Window) error {
 }
 
 // PrintError formats and outputs an error message.
+// CSV, TSV and template output print errors as text on stderr.
 func (f *Formatter) PrintError(code int, message string, candidates []ax.Window) error {
-	if f.format == FormatJSON {
-		return f.printJSON(ErrorResponse{
+	if f.structured() && !f.errorsAsText() {
+		resp := ErrorResponse{
 			SchemaVersion: 1,
 			Success:       false,
 			Error: &ErrorDetail{
 				Code:       code,
 				Message:    message,
-				Candidates: candidates,
+				Candidates: f.sortWindows(candidates),
 			},
-		})
+		}
+		return f.printStructured(resp, []any{resp})
 	}
-	return f.printErrorText(code, message, candidates)
+	return f.printErrorText(code, message, f.sortWindows(candidates))
 }
 
 // --- Preset response types ---
@@ -142,6 +173,84 @@ type PresetApplyResponse struct {
 	Error         *ErrorDetail          `json:"error,omitempty"`
 }
 
+// PresetPlanWindow is a window matched by a preset rule with its target frame.
+// Window holds the current frame.
+type PresetPlanWindow struct {
+	Window ax.Window   `json:"window"`
+	Target window.Rect `json:"target"`
+}
+
+// PresetPlanRule is the plan for a single preset rule.
+// Reason is set when the rule is skipped (ignored, no_match, fullscreen).
+type PresetPlanRule struct {
+	RuleIndex int                `json:"rule_index"`
+	AppFilter string             `json:"app_filter"`
+	Windows   []PresetPlanWindow `json:"windows"`
+	Reason    string             `json:"reason,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// PresetPlanResponse is the JSON output for preset apply --dry-run.
+type PresetPlanResponse struct {
+	SchemaVersion int              `json:"schema_version"`
+	Success       bool             `json:"success"`
+	Preset        string           `json:"preset"`
+	DryRun        bool             `json:"dry_run"`
+	Rules         []PresetPlanRule `json:"rules"`
+}
+
+// PresetDiffWindow is a window matched by a preset rule with its drift from the target frame.
+// Window holds the current frame; Delta is Target minus the current frame and Drift its
+// largest absolute component.
+type PresetDiffWindow struct {
+	Window ax.Window   `json:"window"`
+	Target window.Rect `json:"target"`
+	Delta  window.Rect `json:"delta"`
+	Drift  int         `json:"drift"`
+}
+
+// PresetDiffRule is the drift of the windows matched by a single preset rule.
+// Reason is set when the rule is skipped (ignored, no_match, fullscreen).
+type PresetDiffRule struct {
+	RuleIndex int                `json:"rule_index"`
+	AppFilter string             `json:"app_filter"`
+	Windows   []PresetDiffWindow `json:"windows"`
+	Reason    string             `json:"reason,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// PresetDiffResponse is the JSON output for preset diff and preset check.
+// Tolerance is only set by preset check.
+type PresetDiffResponse struct {
+	SchemaVersion int              `json:"schema_version"`
+	Success       bool             `json:"success"`
+	Preset        string           `json:"preset"`
+	MaxDrift      int              `json:"max_drift"`
+	Tolerance     *int             `json:"tolerance,omitempty"`
+	Rules         []PresetDiffRule `json:"rules"`
+	Error         *ErrorDetail     `json:"error,omitempty"`
+}
+
+// PresetSaveResponse is the JSON output for preset rec --save.
+type PresetSaveResponse struct {
+	SchemaVersion int    `json:"schema_version"`
+	Success       bool   `json:"success"`
+	Preset        string `json:"preset"`
+	Path          string `json:"path"`
+	Rules         int    `json:"rules"`
+}
+
+// PresetChangeResponse is the JSON output for preset rm, mv, cp and edit.
+// NewName is set by mv and cp.
+type PresetChangeResponse struct {
+	SchemaVersion int    `json:"schema_version"`
+	Success       bool   `json:"success"`
+	Action        string `json:"action"`
+	Preset        string `json:"preset"`
+	NewName       string `json:"new_name,omitempty"`
+	Path          string `json:"path"`
+}
+
 // PresetListItem represents a single preset in list output.
 type PresetListItem struct {
 	Name        string `json:"name"`
@@ -173,8 +282,17 @@ type PresetValidateResponse struct {
 
 // PrintPresetApplyResult outputs the result of a preset apply operation.
 func (f *Formatter) PrintPresetApplyResult(resp PresetApplyResponse) error {
-	if f.format == FormatJSON {
-		return f.printJSON(resp)
+	if f.structured() {
+		var recs []any
+		for _, a := range resp.Applied {
+			for _, w := range a.Affected {
+				recs = append(recs, ruleWindowRecord{RuleIndex: a.RuleIndex, AppFilter: a.AppFilter, Window: w})
+			}
+		}
+		for _, sk := range resp.Skipped {
+			recs = append(recs, ruleRecord{RuleIndex: sk.RuleIndex, AppFilter: sk.AppFilter, Reason: sk.Reason})
+		}
+		return f.printStructured(resp, recs)
 	}
 	return f.printPresetApplyText(resp)
 }
@@ -192,9 +310,144 @@ func (f *Formatter) printPresetApplyText(resp PresetApplyResponse) error {
 	return nil
 }
 
+// PrintPresetPlan outputs the plan of a preset apply dry run.
+func (f *Formatter) PrintPresetPlan(resp PresetPlanResponse) error {
+	if f.structured() {
+		var recs []any
+		for _, r := range resp.Rules {
+			for _, p := range r.Windows {
+				recs = append(recs, planRecord{RuleIndex: r.RuleIndex, AppFilter: r.AppFilter, PresetPlanWindow: p})
+			}
+			if len(r.Windows) == 0 || r.Error != "" {
+				recs = append(recs, ruleRecord{RuleIndex: r.RuleIndex, AppFilter: r.AppFilter, Reason: r.Reason, Error: r.Error})
+			}
+		}
+		return f.printStructured(resp, recs)
+	}
+	return f.printPresetPlanText(resp)
+}
+
+func (f *Formatter) printPresetPlanText(resp PresetPlanResponse) error {
+	fmt.Fprintf(f.out, "Preset %q plan (dry run):\n", resp.Preset) //nolint:errcheck
+	for _, r := range resp.Rules {
+		if r.Reason != "" {
+			fmt.Fprintf(f.out, "  rule[%d] %s: skipped (%s)\n", r.RuleIndex, r.AppFilter, r.Reason) //nolint:errcheck
+			continue
+		}
+		fmt.Fprintf(f.out, "  rule[%d] %s:\n", r.RuleIndex, r.AppFilter) //nolint:errcheck
+		for _, p := range r.Windows {
+			w, t := p.Window, p.Target
+			fmt.Fprintf(f.out, "    %s %q (%d, %d) %dx%d → (%d, %d) %dx%d\n", //nolint:errcheck
+				w.AppName, w.Title, w.X, w.Y, w.Width, w.Height, t.X, t.Y, t.W, t.H)
+		}
+		if r.Error != "" {
+			fmt.Fprintf(f.out, "    error: %s\n", r.Error) //nolint:errcheck
+		}
+	}
+	return nil
+}
+
+// PrintPresetDiff outputs the drift between the current layout and a preset.
+func (f *Formatter) PrintPresetDiff(resp PresetDiffResponse) error {
+	if f.structured() {
+		return f.printStructured(resp, diffRecords(resp))
+	}
+	return f.printPresetDiffText(resp)
+}
+
+func (f *Formatter) printPresetDiffText(resp PresetDiffResponse) error {
+	fmt.Fprintf(f.out, "Preset %q diff (max drift %dpx):\n", resp.Preset, resp.MaxDrift) //nolint:errcheck
+	for _, r := range resp.Rules {
+		if r.Reason != "" {
+			fmt.Fprintf(f.out, "  rule[%d] %s: skipped (%s)\n", r.RuleIndex, r.AppFilter, r.Reason) //nolint:errcheck
+			continue
+		}
+		fmt.Fprintf(f.out, "  rule[%d] %s:\n", r.RuleIndex, r.AppFilter) //nolint:errcheck
+		for _, d := range r.Windows {
+			f.printDiffWindow(d)
+		}
+		if r.Error != "" {
+			fmt.Fprintf(f.out, "    error: %s\n", r.Error) //nolint:errcheck
+		}
+	}
+	return nil
+}
+
+// PrintPresetCheck outputs the result of a preset drift check.
+// Text output lists only the windows whose drift exceeds the tolerance.
+func (f *Formatter) PrintPresetCheck(resp PresetDiffResponse) error {
+	if f.structured() {
+		return f.printStructured(resp, diffRecords(resp))
+	}
+	tolerance := 0
+	if resp.Tolerance != nil {
+		tolerance = *resp.Tolerance
+	}
+	if resp.Success {
+		fmt.Fprintf(f.out, "Preset %q matches (max drift %dpx, tolerance %dpx)\n", resp.Preset, resp.MaxDrift, tolerance) //nolint:errcheck
+		return nil
+	}
+	fmt.Fprintf(f.out, "Preset %q drifted (max drift %dpx, tolerance %dpx):\n", resp.Preset, resp.MaxDrift, tolerance) //nolint:errcheck
+	for _, r := range resp.Rules {
+		for _, d := range r.Windows {
+			if d.Drift > tolerance {
+				fmt.Fprintf(f.out, "  rule[%d] %s:\n", r.RuleIndex, r.AppFilter) //nolint:errcheck
+				break
+			}
+		}
+		for _, d := range r.Windows {
+			if d.Drift > tolerance {
+				f.printDiffWindow(d)
+			}
+		}
+	}
+	return nil
+}
+
+// printDiffWindow writes one window line of diff output, e.g.
+// `Code "main.go" (100, 100) 800x600 → (0, 0) 960x1080 Δ(-100, -100) +160x+480`.
+func (f *Formatter) printDiffWindow(d PresetDiffWindow) {
+	w, t, dl := d.Window, d.Target, d.Delta
+	if d.Drift == 0 {
+		fmt.Fprintf(f.out, "    %s %q (%d, %d) %dx%d ok\n", w.AppName, w.Title, w.X, w.Y, w.Width, w.Height) //nolint:errcheck
+		return
+	}
+	fmt.Fprintf(f.out, "    %s %q (%d, %d) %dx%d → (%d, %d) %dx%d Δ(%+d, %+d) %+dx%+d\n", //nolint:errcheck
+		w.AppName, w.Title, w.X, w.Y, w.Width, w.Height, t.X, t.Y, t.W, t.H, dl.X, dl.Y, dl.W, dl.H)
+}
+
+// PrintPresetSaved outputs the result of saving a recorded preset to the config file.
+func (f *Formatter) PrintPresetSaved(resp PresetSaveResponse) error {
+	if f.structured() {
+		return f.printStructured(resp, []any{resp})
+	}
+	fmt.Fprintf(f.out, "Preset %q saved to %s (%d rules)\n", resp.Preset, resp.Path, resp.Rules) //nolint:errcheck
+	return nil
+}
+
+// PrintPresetChange outputs the result of changing a preset in the config file.
+func (f *Formatter) PrintPresetChange(resp PresetChangeResponse) error {
+	if f.structured() {
+		return f.printStructured(resp, []any{resp})
+	}
+	var msg string
+	switch resp.Action {
+	case "rm":
+		msg = fmt.Sprintf("Preset %q removed from %s", resp.Preset, resp.Path)
+	case "mv":
+		msg = fmt.Sprintf("Preset %q renamed to %q in %s", resp.Preset, resp.NewName, resp.Path)
+	case "cp":
+		msg = fmt.Sprintf("Preset %q copied to %q in %s", resp.Preset, resp.NewName, resp.Path)
+	default:
+		msg = fmt.Sprintf("Preset %q updated in %s", resp.Preset, resp.Path)
+	}
+	fmt.Fprintln(f.out, msg) //nolint:errcheck
+	return nil
+}
+
 // PrintPresetList outputs the list of presets.
 func (f *Formatter) PrintPresetList(presets []preset.Preset) error {
-	if f.format == FormatJSON {
+	if f.structured() {
 		items := make([]PresetListItem, len(presets))
 		for i, p := range presets {
 			items[i] = PresetListItem{
@@ -203,11 +456,11 @@ func (f *Formatter) PrintPresetList(presets []preset.Preset) error {
 				RuleCount:   len(p.Rules),
 			}
 		}
-		return f.printJSON(PresetListResponse{
+		return f.printStructured(PresetListResponse{
 			SchemaVersion: 1,
 			Success:       true,
 			Presets:       items,
-		})
+		}, records(items))
 	}
 	return f.printPresetListText(presets)
 }
@@ -227,12 +480,16 @@ func (f *Formatter) printPresetListText(presets []preset.Preset) error {
 
 // PrintPresetShow outputs the details of a single preset.
 func (f *Formatter) PrintPresetShow(p preset.Preset) error {
-	if f.format == FormatJSON {
-		return f.printJSON(PresetShowResponse{
+	if f.structured() {
+		var recs []any
+		for i, r := range p.Rules {
+			recs = append(recs, presetRuleRecord{Preset: p.Name, RuleIndex: i, Rule: r})
+		}
+		return f.printStructured(PresetShowResponse{
 			SchemaVersion: 1,
 			Success:       true,
 			Preset:        p,
-		})
+		}, recs)
 	}
 	return f.printPresetShowText(p)
 }
@@ -244,18 +501,36 @@ func (f *Formatter) printPresetShowText(p preset.Preset) error {
 	}
 	fmt.Fprintln(f.out, "Rules:") //nolint:errcheck
 	for i, r := range p.Rules {
-		line := fmt.Sprintf("  [%d] app=%s", i, r.App)
+		line := fmt.Sprintf("  [%d]", i)
+		if r.App != "" {
+			line += " app=" + r.App
+		}
+		if r.BundleID != "" {
+			line += " bundle_id=" + r.BundleID
+		}
 		if r.Title != "" {
 			line += fmt.Sprintf(" title=%q", r.Title)
 		}
+		if r.TitleRegex != "" {
+			line += fmt.Sprintf(" title_regex=%q", r.TitleRegex)
+		}
+		if r.ExcludeApp != "" {
+			line += fmt.Sprintf(" exclude_app=%q", r.ExcludeApp)
+		}
+		if r.ExcludeTitle != "" {
+			line += fmt.Sprintf(" exclude_title=%q", r.ExcludeTitle)
+		}
 		if r.Screen != "" {
 			line += fmt.Sprintf(" screen=%s", r.Screen)
 		}
 		if len(r.Position) == 2 {
-			line += fmt.Sprintf(" position=(%d,%d)", r.Position[0], r.Position[1])
+			line += fmt.Sprintf(" position=(%s,%s)", r.Position[0], r.Position[1])
 		}
 		if len(r.Size) == 2 {
-			line += fmt.Sprintf(" size=%dx%d", r.Size[0], r.Size[1])
+			line += " size=" + formatSize(r.Size)
+		}
+		if r.Snap != "" {
+			line += " snap=" + r.Snap
 		}
 		fmt.Fprintln(f.out, line) //nolint:errcheck
 	}
@@ -264,16 +539,16 @@ func (f *Formatter) printPresetShowText(p preset.Preset) error {
 
 // PrintPresetValidateResult outputs the result of preset validation.
 func (f *Formatter) PrintPresetValidateResult(count int, errs []preset.ValidationError) error {
-	if f.format == FormatJSON {
+	if f.structured() {
 		if errs == nil {
 			errs = []preset.ValidationError{}
 		}
-		return f.printJSON(PresetValidateResponse{
+		return f.printStructured(PresetValidateResponse{
 			SchemaVersion:    1,
 			Success:          len(errs) == 0,
 			PresetsValidated: count,
 			Errors:           errs,
-		})
+		}, records(errs))
 	}
 	return f.printPresetValidateText(count, errs)
 }
@@ -290,29 +565,295 @@ func (f *Formatter) printPresetValidateText(count int, errs []preset.ValidationE
 	return nil
 }
 
-func (f *Formatter) printWindowsText(windows []ax.Window) error {
-	if len(windows) == 0 {
-		_, err := fmt.Fprintln(f.out, "(no windows)")
+// --- Tile response types ---
+
+// TilePlacement represents a window's current frame and its computed target frame.
+type TilePlacement struct {
+	Window ax.Window   `json:"window"`
+	Target window.Rect `json:"target"`
+}
+
+// TileResponse is the JSON output for the tile command.
+type TileResponse struct {
+	SchemaVersion int             `json:"schema_version"`
+	Success       bool            `json:"success"`
+	Layout        string          `json:"layout"`
+	DryRun        bool            `json:"dry_run"`
+	Placements    []TilePlacement `json:"placements"`
+	Error         *ErrorDetail    `json:"error,omitempty"`
+}
+
+// PrintTileResult outputs a tiling plan, or the result of applying it.
+func (f *Formatter) PrintTileResult(resp TileResponse) error {
+	if f.structured() {
+		return f.printStructured(resp, records(resp.Placements))
+	}
+	return f.printTileText(resp)
+}
+
+func (f *Formatter) printTileText(resp TileResponse) error {
+	if resp.DryRun {
+		fmt.Fprintf(f.out, "Layout %q plan (dry run):\n", resp.Layout) //nolint:errcheck
+	} else {
+		fmt.Fprintf(f.out, "Layout %q applied:\n", resp.Layout) //nolint:errcheck
+	}
+	for _, p := range resp.Placements {
+		w, t := p.Window, p.Target
+		fmt.Fprintf(f.out, "  %s %q (%d, %d) %dx%d → (%d, %d) %dx%d\n", //nolint:errcheck
+			w.AppName, w.Title, w.X, w.Y, w.Width, w.Height, t.X, t.Y, t.W, t.H)
+	}
+	return nil
+}
+
+// --- Rescue response types ---
+
+// RescuePlacement represents an off-screen window and the frame it is moved to.
+type RescuePlacement struct {
+	Window ax.Window   `json:"window"`
+	Target window.Rect `json:"target"`
+	Screen string      `json:"screen"`
+	// Visible is the share of the window that was on screen, from 0 to 1.
+	Visible float64 `json:"visible"`
+}
+
+// RescueResponse is the JSON output for the rescue command.
+type RescueResponse struct {
+	SchemaVersion int               `json:"schema_version"`
+	Success       bool              `json:"success"`
+	DryRun        bool              `json:"dry_run"`
+	Rescued       []RescuePlacement `json:"rescued"`
+	Error         *ErrorDetail      `json:"error,omitempty"`
+}
+
+// PrintRescueResult outputs a rescue plan, or the result of applying it.
+func (f *Formatter) PrintRescueResult(resp RescueResponse) error {
+	if f.structured() {
+		return f.printStructured(resp, records(resp.Rescued))
+	}
+	return f.printRescueText(resp)
+}
+
+func (f *Formatter) printRescueText(resp RescueResponse) error {
+	if len(resp.Rescued) == 0 {
+		_, err := fmt.Fprintln(f.out, "No off-screen windows found.")
 		return err
 	}
+	if resp.DryRun {
+		fmt.Fprintln(f.out, "Rescue plan (dry run):") //nolint:errcheck
+	} else {
+		fmt.Fprintln(f.out, "Rescued windows:") //nolint:errcheck
+	}
+	for _, p := range resp.Rescued {
+		w, t := p.Window, p.Target
+		fmt.Fprintf(f.out, "  %s %q (%d, %d) %dx%d, %d%% visible → (%d, %d) %dx%d on %s\n", //nolint:errcheck
+			w.AppName, w.Title, w.X, w.Y, w.Width, w.Height, int(p.Visible*100), t.X, t.Y, t.W, t.H, p.Screen)
+	}
+	return nil
+}
+
+// --- Profile response types ---
+
+// ProfileDetectResponse is the JSON output for profile detect. Profile and Preset are
+// set when a configured profile matches the screens.
+type ProfileDetectResponse struct {
+	SchemaVersion int              `json:"schema_version"`
+	Success       bool             `json:"success"`
+	Profile       string           `json:"profile,omitempty"`
+	Preset        string           `json:"preset,omitempty"`
+	Screens       []profile.Screen `json:"screens"`
+}
+
+// PrintProfileDetect outputs the fingerprint of the connected screens. The text form
+// is a profile entry ready to paste under profiles: in the config file.
+func (f *Formatter) PrintProfileDetect(resp ProfileDetectResponse) error {
+	if f.structured() {
+		return f.printStructured(resp, records(resp.Screens))
+	}
+	entry := profile.Profile{Name: resp.Profile, Preset: resp.Preset, Screens: resp.Screens}
+	if resp.Profile == "" {
+		fmt.Fprintln(f.out, "# No profile matches these screens. Add this under profiles: and set name and preset.") //nolint:errcheck
+		entry.Name, entry.Preset = "new-profile", "preset-name"
+	} else {
+		fmt.Fprintf(f.out, "# These screens match profile %q (preset %q).\n", resp.Profile, resp.Preset) //nolint:errcheck
+	}
+	return f.printYAML([]profile.Profile{entry})
+}
+
+// --- History response types ---
+
+// HistoryRestoreResponse is the JSON output for the undo and redo commands.
+type HistoryRestoreResponse struct {
+	SchemaVersion int          `json:"schema_version"`
+	Success       bool         `json:"success"`
+	Action        string       `json:"action"`
+	Command       string       `json:"command"`
+	Restored      []ax.Window  `json:"restored"`
+	Missing       []ax.Window  `json:"missing"`
+	Error         *ErrorDetail `json:"error,omitempty"`
+}
+
+// HistoryResponse is the JSON output for the history command.
+type HistoryResponse struct {
+	SchemaVersion int             `json:"schema_version"`
+	Success       bool            `json:"success"`
+	Undo          []history.Entry `json:"undo"`
+	Redo          []history.Entry `json:"redo"`
+}
+
+// PrintHistoryRestore outputs the result of an undo or redo.
+func (f *Formatter) PrintHistoryRestore(resp HistoryRestoreResponse) error {
+	if f.structured() {
+		var recs []any
+		for _, w := range resp.Restored {
+			recs = append(recs, restoreRecord{Status: "restored", Window: w})
+		}
+		for _, w := range resp.Missing {
+			recs = append(recs, restoreRecord{Status: "missing", Window: w})
+		}
+		return f.printStructured(resp, recs)
+	}
+	verb := "Undid"
+	if resp.Action == "redo" {
+		verb = "Redid"
+	}
+	fmt.Fprintf(f.out, "%s %q:\n", verb, resp.Command) //nolint:errcheck
+	for _, w := range resp.Restored {
+		fmt.Fprintf(f.out, "  %s %q → (%d, %d) %dx%d\n", w.AppName, w.Title, w.X, w.Y, w.Width, w.Height) //nolint:errcheck
+	}
+	for _, w := range resp.Missing {
+		fmt.Fprintf(f.out, "Missing: %s %q (id=%d)\n", w.AppName, w.Title, w.ID) //nolint:errcheck
+	}
+	return nil
+}
+
+// PrintHistory outputs the undo and redo stacks. Entries are listed newest first.
+func (f *Formatter) PrintHistory(undo, redo []history.Entry) error {
+	if f.structured() {
+		// newest first, like the text table
+		var recs []any
+		for _, e := range redo {
+			recs = append(recs, historyRecord{Stack: "redo", Entry: e})
+		}
+		for i := len(undo) - 1; i >= 0; i-- {
+			recs = append(recs, historyRecord{Stack: "undo", Entry: undo[i]})
+		}
+		return f.printStructured(HistoryResponse{
+			SchemaVersion: 1,
+			Success:       true,
+			Undo:          undo,
+			Redo:          redo,
+		}, recs)
+	}
+	if len(undo) == 0 && len(redo) == 0 {
+		_, err := fmt.Fprintln(f.out, "(no history)")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(f.out, 8, 1, 2, ' ', 0)
+	fmt.Fprintln(tw, "STACK\tTIME\tWINDOWS\tCOMMAND") //nolint:errcheck // tabwriter defers errors to Flush()
+	// the redo stack sits "above" the current state, so it is listed first
+	for _, e := range redo {
+		fmt.Fprintf(tw, "redo\t%s\t%d\t%s\n", e.Time.Format(time.DateTime), len(e.Before), e.Command) //nolint:errcheck // tabwriter defers errors to Flush()
+	}
+	for i := len(undo) - 1; i >= 0; i-- {
+		e := undo[i]
+		fmt.Fprintf(tw, "undo\t%s\t%d\t%s\n", e.Time.Format(time.DateTime), len(e.Before), e.Command) //nolint:errcheck // tabwriter defers errors to Flush()
+	}
+	return tw.Flush()
+}
+
+// --- Screens response types ---
+
+// ScreensResponse is the JSON output for the screens command.
+type ScreensResponse struct {
+	SchemaVersion int                 `json:"schema_version"`
+	Success       bool                `json:"success"`
+	Screens       []window.ScreenInfo `json:"screens"`
+}
+
+// PrintScreens outputs the connected screens.
+func (f *Formatter) PrintScreens(screens []window.ScreenInfo) error {
+	if f.structured() {
+		return f.printStructured(ScreensResponse{
+			SchemaVersion: 1,
+			Success:       true,
+			Screens:       screens,
+		}, records(screens))
+	}
+	return f.printScreensText(screens)
+}
+
+func (f *Formatter) printScreensText(screens []window.ScreenInfo) error {
+	if len(screens) == 0 {
+		_, err := fmt.Fprintln(f.out, "(no screens)")
+		return err
+	}
+
+	index := make(map[uint32]int, len(screens))
+	for _, s := range screens {
+		index[s.ID] = s.Index
+	}
 
-	// align columns with tabwriter (min width 8, tab width 1, padding 2)
 	tw := tabwriter.NewWriter(f.out, 8, 1, 2, ' ', 0)
-	fmt.Fprintln(tw, "APP_NAME\tTITLE\tX\tY\tWIDTH\tHEIGHT\tSTATE\tDESKTOP\tSCREEN") //nolint:errcheck // tabwriter defers errors to Flush()
+	fmt.Fprintln(tw, "INDEX\tID\tNAME\tX\tY\tWIDTH\tHEIGHT\tVISIBLE\tPRIMARY\tWINDOWS\tARRANGEMENT") //nolint:errcheck // tabwriter defers errors to Flush()
 
-	for _, w := range windows {
-		screenName := truncate(w.ScreenName, 20)
-		if w.State == ax.StateMinimized || w.State == ax.StateHidden {
-			screenName = "-"
+	for _, s := range screens {
+		primary := "no"
+		if s.IsPrimary {
+			primary = "yes"
 		}
-		title := truncate(w.Title, 32)
-		desktop := formatDesktop(w.Desktop)
-		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%s\t%s\t%s\n", //nolint:errcheck // tabwriter defers errors to Flush()
-			w.AppName, title, w.X, w.Y, w.Width, w.Height, w.State, desktop, screenName)
+		v := s.Visible()
+		fmt.Fprintf(tw, "#%d\t%d\t%s\t%d\t%d\t%d\t%d\t%d,%d %dx%d\t%s\t%d\t%s\n", //nolint:errcheck // tabwriter defers errors to Flush()
+			s.Index, s.ID, truncate(s.Name, 24), s.X, s.Y, s.Width, s.Height, v.X, v.Y, v.W, v.H, primary, s.WindowCount,
+			formatArrangement(s, index))
 	}
 	return tw.Flush()
 }
 
+// formatArrangement renders the left-of/above relationships of s using screen indexes,
+// e.g. "left of #2, above #3". Screens with no neighbours to the right or below get "-".
+func formatArrangement(s window.ScreenInfo, index map[uint32]int) string {
+	refs := func(ids []uint32) string {
+		parts := make([]string, len(ids))
+		for i, id := range ids {
+			parts[i] = "#" + strconv.Itoa(index[id])
+		}
+		return strings.Join(parts, " ")
+	}
+	var parts []string
+	if len(s.LeftOf) > 0 {
+		parts = append(parts, "left of "+refs(s.LeftOf))
+	}
+	if len(s.Above) > 0 {
+		parts = append(parts, "above "+refs(s.Above))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (f *Formatter) printWindowsText(windows []ax.Window) error {
+	if len(windows) == 0 {
+		if f.table.NoHeader {
+			return nil
+		}
+		_, err := fmt.Fprintln(f.out, "(no windows)")
+		return err
+	}
+	return f.writeTable(f.out, "", f.columns(DefaultColumns), windows)
+}
+
+// formatSize renders a rule size as "WxH", or "(W, H)" when a component is an expression.
+func formatSize(size []preset.Expr) string {
+	_, wAbs := size[0].Absolute()
+	_, hAbs := size[1].Absolute()
+	if wAbs && hAbs {
+		return fmt.Sprintf("%sx%s", size[0], size[1])
+	}
+	return fmt.Sprintf("(%s, %s)", size[0], size[1])
+}
+
 // formatDesktop converts a Desktop int to its display string.
 // 0 = "all" (assigned to all desktops), -1 = "?" (unknown), N = numeric string.
 func formatDesktop(d int) string {
@@ -331,10 +872,8 @@ func (f *Formatter) printErrorText(code int, message string, candidates []ax.Win
 
 	if len(candidates) > 0 {
 		fmt.Fprintln(f.errOut, "\nCandidates:") //nolint:errcheck
-		for i, c := range candidates {
-			fmt.Fprintf(f.errOut, "  %d. %s %q\tpid=%d\n", i+1, c.AppName, c.Title, c.PID) //nolint:errcheck
-		}
-		fmt.Fprintln(f.errOut, "\nHint: use --title to narrow down, or --all to move all") //nolint:errcheck
+		_ = f.writeTable(f.errOut, "  ", f.columns(candidateColumns), candidates)
+		fmt.Fprintln(f.errOut, "\nHint: use --title or --id to narrow down, or --all to move all") //nolint:errcheck
 	}
 
 	_ = code
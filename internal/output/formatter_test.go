@@ -2,17 +2,25 @@ package output_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/history"
 	"github.com/peacock0803sz/mado/internal/output"
 	"github.com/peacock0803sz/mado/internal/preset"
+	"github.com/peacock0803sz/mado/internal/profile"
+	"github.com/peacock0803sz/mado/internal/window"
 	"github.com/sebdah/goldie/v2"
 )
 
 var multiScreenWindows = []ax.Window{
 	{
+		ID:         1,
 		AppName:    "Terminal",
+		BundleID:   "com.apple.Terminal",
 		Title:      "peacock — zsh — 80×24",
 		PID:        1234,
 		X:          100,
@@ -25,7 +33,9 @@ var multiScreenWindows = []ax.Window{
 		Desktop:    1,
 	},
 	{
+		ID:         2,
 		AppName:    "Safari",
+		BundleID:   "com.apple.Safari",
 		Title:      "GitHub",
 		PID:        5678,
 		X:          -1920,
@@ -38,6 +48,7 @@ var multiScreenWindows = []ax.Window{
 		Desktop:    2,
 	},
 	{
+		ID:       3,
 		AppName:  "Finder",
 		Title:    "",
 		PID:      300,
@@ -51,7 +62,9 @@ var multiScreenWindows = []ax.Window{
 
 var sampleWindows = []ax.Window{
 	{
+		ID:         4,
 		AppName:    "Terminal",
+		BundleID:   "com.apple.Terminal",
 		Title:      "peacock — zsh — 80×24",
 		PID:        1234,
 		X:          100,
@@ -64,7 +77,9 @@ var sampleWindows = []ax.Window{
 		Desktop:    1,
 	},
 	{
+		ID:         5,
 		AppName:    "Safari",
+		BundleID:   "com.apple.Safari",
 		Title:      "GitHub",
 		PID:        5678,
 		X:          0,
@@ -77,6 +92,7 @@ var sampleWindows = []ax.Window{
 		Desktop:    1,
 	},
 	{
+		ID:       6,
 		AppName:  "Safari",
 		Title:    "Apple",
 		PID:      5678,
@@ -110,6 +126,147 @@ func TestPrintWindowsJSON(t *testing.T) {
 	g.AssertJson(t, "list_json", buf.Bytes())
 }
 
+func TestPrintWindowsColumnsAndSort(t *testing.T) {
+	cols, err := output.ParseColumns("app,title,WIDTH,screen_name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort, err := output.ParseSort("app,-width")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	f := output.New(output.FormatText, &buf, &buf)
+	f.SetTableOptions(output.TableOptions{Columns: cols, Sort: sort})
+	if err := f.PrintWindows(sampleWindows); err != nil {
+		t.Fatal(err)
+	}
+	g := goldie.New(t)
+	g.Assert(t, "list_columns_text", buf.Bytes())
+}
+
+func TestPrintWindowsNoHeader(t *testing.T) {
+	var buf bytes.Buffer
+	f := output.New(output.FormatText, &buf, &buf)
+	f.SetTableOptions(output.TableOptions{Columns: []string{"id", "app"}, NoHeader: true})
+	if err := f.PrintWindows(sampleWindows[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "4       Terminal\n5       Safari\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	if err := f.PrintWindows(nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output for an empty list without header, got %q", buf.String())
+	}
+}
+
+func TestPrintWindowsSortJSON(t *testing.T) {
+	var buf bytes.Buffer
+	f := output.New(output.FormatJSON, &buf, &buf)
+	f.SetTableOptions(output.TableOptions{Sort: []output.SortKey{{Column: "id", Desc: true}}})
+	if err := f.PrintWindows(sampleWindows); err != nil {
+		t.Fatal(err)
+	}
+	var resp output.ListResponse
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Windows) != 3 || resp.Windows[0].ID != 6 || resp.Windows[2].ID != 4 {
+		t.Errorf("expected windows sorted by descending id, got %+v", resp.Windows)
+	}
+}
+
+func TestParseColumnsAndSort_Unknown(t *testing.T) {
+	if _, err := output.ParseColumns("app,nope"); err == nil || !strings.Contains(err.Error(), `unknown column "nope"`) {
+		t.Errorf("ParseColumns: unexpected error %v", err)
+	}
+	if _, err := output.ParseSort("-widht"); err == nil || !strings.Contains(err.Error(), `unknown column "widht"`) {
+		t.Errorf("ParseSort: unexpected error %v", err)
+	}
+	if _, err := output.ParseColumns("app,"); err == nil {
+		t.Error("ParseColumns: expected error for an empty column name")
+	}
+}
+
+func TestPrintWindowsFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format output.Format
+		golden string
+	}{
+		{"ndjson", output.FormatNDJSON, "list_ndjson"},
+		{"csv", output.FormatCSV, "list_csv"},
+		{"tsv", output.FormatTSV, "list_tsv"},
+		{"yaml", output.FormatYAML, "list_yaml"},
+		{"template", "template={{.id}} {{.app_name}}: {{.title}} ({{.width}}x{{.height}})", "list_template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			f := output.New(tt.format, &buf, &buf)
+			if err := f.PrintWindows(sampleWindows); err != nil {
+				t.Fatal(err)
+			}
+			g := goldie.New(t)
+			g.Assert(t, tt.golden, buf.Bytes())
+		})
+	}
+}
+
+func TestPrintWindowsCSVColumns(t *testing.T) {
+	var buf bytes.Buffer
+	f := output.New(output.FormatCSV, &buf, &buf)
+	f.SetTableOptions(output.TableOptions{Columns: []string{"app_name", "title", "desktop"}, NoHeader: true})
+	if err := f.PrintWindows(sampleWindows[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "Safari,GitHub,1\nSafari,Apple,-1\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPrintErrorFormats(t *testing.T) {
+	tests := []struct {
+		format  output.Format
+		wantOut string
+		wantErr string
+	}{
+		{output.FormatNDJSON, `{"schema_version":1,"success":false,"error":{"code":4,"message":"no window matches"}}` + "\n", ""},
+		{output.FormatCSV, "", "Error: no window matches\n"},
+		{"template={{.title}}", "", "Error: no window matches\n"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			f := output.New(tt.format, &out, &errOut)
+			if err := f.PrintError(4, "no window matches", nil); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.wantOut || errOut.String() != tt.wantErr {
+				t.Errorf("stdout %q, stderr %q; want %q, %q", out.String(), errOut.String(), tt.wantOut, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"text", "json", "ndjson", "csv", "tsv", "yaml", "template={{.title}}"} {
+		if _, err := output.ParseFormat(s); err != nil {
+			t.Errorf("ParseFormat(%q): unexpected error %v", s, err)
+		}
+	}
+	for _, s := range []string{"xml", "template={{.title", ""} {
+		if _, err := output.ParseFormat(s); err == nil {
+			t.Errorf("ParseFormat(%q): expected error", s)
+		}
+	}
+}
+
 func TestPrintWindowsEmpty(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -193,16 +350,16 @@ var samplePresets = []preset.Preset{
 		Name:        "coding",
 		Description: "Editor left, terminal right",
 		Rules: []preset.Rule{
-			{App: "Code", Position: []int{0, 0}, Size: []int{960, 1080}},
-			{App: "Terminal", Position: []int{960, 0}, Size: []int{960, 1080}},
+			{App: "Code", Position: []preset.Expr{"0", "0"}, Size: []preset.Expr{"960", "1080"}},
+			{App: "Terminal", Position: []preset.Expr{"960", "0"}, Size: []preset.Expr{"960", "1080"}},
 		},
 	},
 	{
 		Name:        "meeting",
 		Description: "Browser center, notes right",
 		Rules: []preset.Rule{
-			{App: "Safari", Title: "Zoom", Position: []int{0, 0}, Size: []int{1280, 1080}},
-			{App: "Notes", Position: []int{1280, 0}, Size: []int{640, 1080}},
+			{App: "Safari", Title: "Zoom", Position: []preset.Expr{"0", "0"}, Size: []preset.Expr{"1280", "1080"}},
+			{App: "Notes", Position: []preset.Expr{"1280", "0"}, Size: []preset.Expr{"640", "1080"}},
 		},
 	},
 }
@@ -269,14 +426,14 @@ func TestPrintPresetApply(t *testing.T) {
 				RuleIndex: 0,
 				AppFilter: "Code",
 				Affected: []ax.Window{
-					{AppName: "Code", Title: "main.go", X: 0, Y: 0, Width: 960, Height: 1080},
+					{ID: 7, AppName: "Code", Title: "main.go", X: 0, Y: 0, Width: 960, Height: 1080},
 				},
 			},
 			{
 				RuleIndex: 1,
 				AppFilter: "Terminal",
 				Affected: []ax.Window{
-					{AppName: "Terminal", Title: "zsh", X: 960, Y: 0, Width: 960, Height: 1080},
+					{ID: 8, AppName: "Terminal", Title: "zsh", X: 960, Y: 0, Width: 960, Height: 1080},
 				},
 			},
 		},
@@ -361,3 +518,402 @@ func TestPrintAmbiguousError(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintTileResult(t *testing.T) {
+	resp := output.TileResponse{
+		SchemaVersion: 1,
+		Success:       true,
+		Layout:        "master-stack",
+		Placements: []output.TilePlacement{
+			{
+				Window: ax.Window{ID: 9, AppName: "Code", Title: "main.go", X: 100, Y: 100, Width: 800, Height: 600},
+				Target: window.Rect{X: 0, Y: 0, W: 960, H: 1080},
+			},
+			{
+				Window: ax.Window{ID: 10, AppName: "Terminal", Title: "zsh", X: 200, Y: 200, Width: 640, Height: 480},
+				Target: window.Rect{X: 960, Y: 0, W: 960, H: 1080},
+			},
+		},
+	}
+	tests := []struct {
+		name   string
+		format output.Format
+		dryRun bool
+		golden string
+	}{
+		{"text", output.FormatText, false, "tile_text"},
+		{"json", output.FormatJSON, false, "tile_json"},
+		{"dry-run text", output.FormatText, true, "tile_dry_run_text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resp
+			r.DryRun = tt.dryRun
+			var buf bytes.Buffer
+			f := output.New(tt.format, &buf, &buf)
+			if err := f.PrintTileResult(r); err != nil {
+				t.Fatal(err)
+			}
+			g := goldie.New(t)
+			if tt.format == output.FormatJSON {
+				g.AssertJson(t, tt.golden, buf.Bytes())
+			} else {
+				g.Assert(t, tt.golden, buf.Bytes())
+			}
+		})
+	}
+}
+
+func TestPrintRescueResult(t *testing.T) {
+	resp := output.RescueResponse{
+		SchemaVersion: 1,
+		Success:       true,
+		Rescued: []output.RescuePlacement{
+			{
+				Window: ax.Window{ID: 9, AppName: "Code", Title: "main.go", X: 3000, Y: 200, Width: 800, Height: 600},
+				Target: window.Rect{X: 1120, Y: 200, W: 800, H: 600},
+				Screen: "Built-in",
+			},
+			{
+				Window:  ax.Window{ID: 10, AppName: "Terminal", Title: "zsh", X: 1700, Y: 100, Width: 640, Height: 480},
+				Target:  window.Rect{X: 1280, Y: 100, W: 640, H: 480},
+				Screen:  "Built-in",
+				Visible: 0.34,
+			},
+		},
+	}
+	tests := []struct {
+		name   string
+		format output.Format
+		dryRun bool
+		golden string
+	}{
+		{"text", output.FormatText, false, "rescue_text"},
+		{"json", output.FormatJSON, false, "rescue_json"},
+		{"dry-run text", output.FormatText, true, "rescue_dry_run_text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resp
+			r.DryRun = tt.dryRun
+			var buf bytes.Buffer
+			f := output.New(tt.format, &buf, &buf)
+			if err := f.PrintRescueResult(r); err != nil {
+				t.Fatal(err)
+			}
+			g := goldie.New(t)
+			if tt.format == output.FormatJSON {
+				g.AssertJson(t, tt.golden, buf.Bytes())
+			} else {
+				g.Assert(t, tt.golden, buf.Bytes())
+			}
+		})
+	}
+}
+
+func TestPrintRescueResult_Nothing(t *testing.T) {
+	var buf bytes.Buffer
+	f := output.New(output.FormatText, &buf, &buf)
+	if err := f.PrintRescueResult(output.RescueResponse{SchemaVersion: 1, Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "No off-screen windows found.\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestPrintScreens(t *testing.T) {
+	screens := window.DescribeScreens([]ax.Screen{
+		{
+			ID: 69678592, Name: "Built-in Retina Display", X: 0, Y: 0, Width: 1440, Height: 900, IsPrimary: true,
+			VisibleFrame: ax.Rect{X: 0, Y: 25, W: 1440, H: 805},
+		},
+		{
+			ID: 12345678, Name: "DELL U2720Q", X: -1920, Y: 0, Width: 1920, Height: 1080,
+			VisibleFrame: ax.Rect{X: -1920, Y: 25, W: 1920, H: 1055},
+		},
+	}, multiScreenWindows)
+	tests := []struct {
+		name   string
+		format output.Format
+		golden string
+	}{
+		{"text", output.FormatText, "screens_text"},
+		{"json", output.FormatJSON, "screens_json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			f := output.New(tt.format, &buf, &buf)
+			if err := f.PrintScreens(screens); err != nil {
+				t.Fatal(err)
+			}
+			g := goldie.New(t)
+			if tt.format == output.FormatJSON {
+				g.AssertJson(t, tt.golden, buf.Bytes())
+			} else {
+				g.Assert(t, tt.golden, buf.Bytes())
+			}
+		})
+	}
+}
+
+func TestPrintPresetPlan(t *testing.T) {
+	resp := output.PresetPlanResponse{
+		SchemaVersion: 1,
+		Success:       true,
+		Preset:        "coding",
+		DryRun:        true,
+		Rules: []output.PresetPlanRule{
+			{
+				RuleIndex: 0,
+				AppFilter: "Code",
+				Windows: []output.PresetPlanWindow{{
+					Window: ax.Window{ID: 11, AppName: "Code", Title: "main.go", X: 100, Y: 100, Width: 800, Height: 600},
+					Target: window.Rect{X: 0, Y: 0, W: 960, H: 1080},
+				}},
+			},
+			{RuleIndex: 1, AppFilter: "Terminal", Windows: []output.PresetPlanWindow{}, Reason: "no_match"},
+			{RuleIndex: 2, AppFilter: "Slack", Windows: []output.PresetPlanWindow{}, Reason: "ignored"},
+		},
+	}
+	tests := []struct {
+		name   string
+		format output.Format
+		golden string
+	}{
+		{"text", output.FormatText, "preset_plan_text"},
+		{"json", output.FormatJSON, "preset_plan_json"},
+		{"csv", output.FormatCSV, "preset_plan_csv"},
+		{"yaml", output.FormatYAML, "preset_plan_yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			f := output.New(tt.format, &buf, &buf)
+			if err := f.PrintPresetPlan(resp); err != nil {
+				t.Fatal(err)
+			}
+			g := goldie.New(t)
+			if tt.format == output.FormatJSON {
+				g.AssertJson(t, tt.golden, buf.Bytes())
+			} else {
+				g.Assert(t, tt.golden, buf.Bytes())
+			}
+		})
+	}
+}
+
+func TestPrintPresetDiff(t *testing.T) {
+	tolerance := 4
+	resp := output.PresetDiffResponse{
+		SchemaVersion: 1,
+		Success:       true,
+		Preset:        "coding",
+		MaxDrift:      480,
+		Rules: []output.PresetDiffRule{
+			{
+				RuleIndex: 0,
+				AppFilter: "Code",
+				Windows: []output.PresetDiffWindow{{
+					Window: ax.Window{ID: 11, AppName: "Code", Title: "main.go", X: 100, Y: 100, Width: 800, Height: 600},
+					Target: window.Rect{X: 0, Y: 0, W: 960, H: 1080},
+					Delta:  window.Rect{X: -100, Y: -100, W: 160, H: 480},
+					Drift:  480,
+				}},
+			},
+			{
+				RuleIndex: 1,
+				AppFilter: "Terminal",
+				Windows: []output.PresetDiffWindow{
+					{
+						Window: ax.Window{ID: 12, AppName: "Terminal", Title: "zsh", X: 962, Y: 0, Width: 960, Height: 1080},
+						Target: window.Rect{X: 960, Y: 0, W: 960, H: 1080},
+						Delta:  window.Rect{X: -2},
+						Drift:  2,
+					},
+					{
+						Window: ax.Window{ID: 13, AppName: "Terminal", Title: "logs", X: 960, Y: 0, Width: 960, Height: 1080},
+						Target: window.Rect{X: 960, Y: 0, W: 960, H: 1080},
+					},
+				},
+			},
+			{RuleIndex: 2, AppFilter: "Slack", Windows: []output.PresetDiffWindow{}, Reason: "no_match"},
+		},
+	}
+	checked := resp
+	checked.Success = false
+	checked.Tolerance = &tolerance
+	checked.Error = &output.ErrorDetail{Code: 8, Message: "layout drift 480px exceeds tolerance 4px"}
+
+	tests := []struct {
+		name   string
+		format output.Format
+		check  bool
+		golden string
+	}{
+		{"diff_text", output.FormatText, false, "preset_diff_text"},
+		{"diff_json", output.FormatJSON, false, "preset_diff_json"},
+		{"check_text", output.FormatText, true, "preset_check_text"},
+		{"check_json", output.FormatJSON, true, "preset_check_json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			f := output.New(tt.format, &buf, &buf)
+			var err error
+			if tt.check {
+				err = f.PrintPresetCheck(checked)
+			} else {
+				err = f.PrintPresetDiff(resp)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			g := goldie.New(t)
+			if tt.format == output.FormatJSON {
+				g.AssertJson(t, tt.golden, buf.Bytes())
+			} else {
+				g.Assert(t, tt.golden, buf.Bytes())
+			}
+		})
+	}
+}
+
+func TestPrintHistoryRestore(t *testing.T) {
+	resp := output.HistoryRestoreResponse{
+		SchemaVersion: 1,
+		Success:       false,
+		Action:        "undo",
+		Command:       "preset apply coding",
+		Restored: []ax.Window{
+			{ID: 21, AppName: "Code", Title: "main.go", X: 100, Y: 100, Width: 800, Height: 600},
+		},
+		Missing: []ax.Window{
+			{ID: 22, AppName: "Terminal", Title: "zsh", X: 0, Y: 0, Width: 640, Height: 480},
+		},
+		Error: &output.ErrorDetail{Code: 7, Message: "1 window(s) no longer exist"},
+	}
+	tests := []struct {
+		name   string
+		format output.Format
+		golden string
+	}{
+		{"text", output.FormatText, "undo_text"},
+		{"json", output.FormatJSON, "undo_json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			f := output.New(tt.format, &buf, &buf)
+			if err := f.PrintHistoryRestore(resp); err != nil {
+				t.Fatal(err)
+			}
+			g := goldie.New(t)
+			if tt.format == output.FormatJSON {
+				g.AssertJson(t, tt.golden, buf.Bytes())
+			} else {
+				g.Assert(t, tt.golden, buf.Bytes())
+			}
+		})
+	}
+}
+
+func TestPrintHistory(t *testing.T) {
+	at := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+	undo := []history.Entry{
+		{Command: "move", Time: at, Before: []ax.Window{{ID: 1}}, After: []ax.Window{{ID: 1, X: 10}}},
+		{Command: "preset apply coding", Time: at.Add(time.Minute), Before: make([]ax.Window, 2), After: make([]ax.Window, 2)},
+	}
+	redo := []history.Entry{
+		{Command: "tile", Time: at.Add(2 * time.Minute), Before: make([]ax.Window, 3), After: make([]ax.Window, 3)},
+	}
+	var buf bytes.Buffer
+	f := output.New(output.FormatText, &buf, &buf)
+	if err := f.PrintHistory(undo, redo); err != nil {
+		t.Fatal(err)
+	}
+	g := goldie.New(t)
+	g.Assert(t, "history_text", buf.Bytes())
+}
+
+func TestPrintWindowMap(t *testing.T) {
+	screens := []ax.Screen{
+		{ID: 2, Name: "DELL U2720Q", X: 1440, Y: 0, Width: 1920, Height: 1080},
+		{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900, IsPrimary: true},
+	}
+	windows := []ax.Window{
+		{ID: 1, AppName: "Terminal", Title: "zsh", X: 100, Y: 100, Width: 800, Height: 600, State: ax.StateNormal},
+		{ID: 2, AppName: "Safari", Title: "GitHub", X: 600, Y: 400, Width: 700, Height: 450, State: ax.StateNormal},
+		{ID: 3, AppName: "Code", Title: "main.go", X: 1440, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
+		{ID: 4, AppName: "Notes", Title: "Todo", X: 3200, Y: 800, Width: 400, Height: 400, State: ax.StateNormal},
+		{ID: 5, AppName: "Finder", Title: "Home", X: 0, Y: 0, Width: 800, Height: 600, State: ax.StateMinimized},
+	}
+	var buf bytes.Buffer
+	f := output.New(output.FormatMap, &buf, &buf)
+	if err := f.PrintWindowMap(screens, windows); err != nil {
+		t.Fatal(err)
+	}
+	g := goldie.New(t)
+	g.Assert(t, "list_map", buf.Bytes())
+}
+
+func TestPrintWindowSVG(t *testing.T) {
+	screens := []ax.Screen{
+		{ID: 2, Name: "DELL U2720Q", X: 1440, Y: 0, Width: 1920, Height: 1080},
+		{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900, IsPrimary: true},
+	}
+	windows := []ax.Window{
+		{ID: 1, AppName: "Terminal", Title: "zsh", X: 0, Y: 0, Width: 720, Height: 900, State: ax.StateNormal},
+		{ID: 2, AppName: "Safari", Title: "<GitHub> & Issues", X: 720, Y: 0, Width: 720, Height: 900, State: ax.StateNormal},
+		{ID: 3, AppName: "Code", Title: "main.go", X: 1440, Y: 0, Width: 1920, Height: 1080, State: ax.StateNormal},
+		{ID: 4, AppName: "Finder", Title: "Home", X: 0, Y: 0, Width: 800, Height: 600, State: ax.StateHidden},
+	}
+	var buf bytes.Buffer
+	f := output.New(output.FormatSVG, &buf, &buf)
+	if err := f.PrintWindowSVG(screens, windows); err != nil {
+		t.Fatal(err)
+	}
+	g := goldie.New(t)
+	g.Assert(t, "list_svg", buf.Bytes())
+}
+
+func TestPrintProfileDetect(t *testing.T) {
+	resp := output.ProfileDetectResponse{
+		SchemaVersion: 1,
+		Success:       true,
+		Screens: []profile.Screen{
+			{Name: "Built-in Retina Display", Width: 1512, Height: 982},
+			{Name: "DELL U2720Q", Width: 2560, Height: 1440},
+		},
+	}
+	tests := []struct {
+		name    string
+		format  output.Format
+		profile string
+		golden  string
+	}{
+		{"text", output.FormatText, "", "profile_detect_text"},
+		{"matched text", output.FormatText, "docked", "profile_detect_matched_text"},
+		{"json", output.FormatJSON, "docked", "profile_detect_json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resp
+			if tt.profile != "" {
+				r.Profile, r.Preset = tt.profile, "coding"
+			}
+			var buf bytes.Buffer
+			f := output.New(tt.format, &buf, &buf)
+			if err := f.PrintProfileDetect(r); err != nil {
+				t.Fatal(err)
+			}
+			g := goldie.New(t)
+			if tt.format == output.FormatJSON {
+				g.AssertJson(t, tt.golden, buf.Bytes())
+			} else {
+				g.Assert(t, tt.golden, buf.Bytes())
+			}
+		})
+	}
+}
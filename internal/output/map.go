@@ -0,0 +1,243 @@
+package output
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// mapWidth is the width of the screen map in terminal columns.
+const mapWidth = 80
+
+// Map characters. Screens are drawn with + - |, windows with * ~ :, windows that are
+// partly or entirely off-screen with !, and cells covered by more than one window with x.
+const (
+	screenCorner = '+'
+	screenHoriz  = '-'
+	screenVert   = '|'
+	windowCorner = '*'
+	windowHoriz  = '~'
+	windowVert   = ':'
+	offScreen    = '!'
+	overlap      = 'x'
+)
+
+// mapWindow is a window placed on the map with the conditions worth highlighting.
+type mapWindow struct {
+	ax.Window
+	overlaps  []string // app names of the windows this one overlaps
+	offScreen string   // "", "partly off-screen" or "off-screen"
+}
+
+// PrintWindowMap renders screens as scaled boxes in their real arrangement with the
+// windows drawn on top, labeled by app, followed by a legend. Overlapping and
+// off-screen windows are highlighted. Minimized and hidden windows are not drawn.
+func (f *Formatter) PrintWindowMap(screens []ax.Screen, windows []ax.Window) error {
+	screens = window.OrderScreens(screens)
+	placed := placeWindows(screens, windows)
+	if len(screens) == 0 && len(placed) == 0 {
+		_, err := fmt.Fprintln(f.out, "(no screens)")
+		return err
+	}
+
+	for _, line := range renderMap(screens, placed, mapWidth) {
+		fmt.Fprintln(f.out, line) //nolint:errcheck
+	}
+
+	fmt.Fprintln(f.out, "\nScreens:") //nolint:errcheck
+	for i, s := range screens {
+		fmt.Fprintf(f.out, "  #%d %s (%d, %d) %dx%d\n", i+1, s.Name, s.X, s.Y, s.Width, s.Height) //nolint:errcheck
+	}
+	if len(placed) == 0 {
+		return nil
+	}
+	fmt.Fprintln(f.out, "Windows:") //nolint:errcheck
+	for _, w := range placed {
+		line := fmt.Sprintf("  %s %q (%d, %d) %dx%d", w.AppName, w.Title, w.X, w.Y, w.Width, w.Height)
+		if w.offScreen != "" {
+			line += " [" + w.offScreen + "]"
+		}
+		if len(w.overlaps) > 0 {
+			line += " [overlaps " + strings.Join(w.overlaps, ", ") + "]"
+		}
+		fmt.Fprintln(f.out, line) //nolint:errcheck
+	}
+	return nil
+}
+
+// placeWindows keeps the visible windows and works out which of them overlap each
+// other or lie outside every screen.
+func placeWindows(screens []ax.Screen, windows []ax.Window) []mapWindow {
+	var placed []mapWindow
+	for _, w := range windows {
+		if w.State == ax.StateMinimized || w.State == ax.StateHidden || w.Width <= 0 || w.Height <= 0 {
+			continue
+		}
+		placed = append(placed, mapWindow{Window: w, offScreen: offScreenState(screens, w)})
+	}
+	for i := range placed {
+		for j := range placed {
+			if i != j && intersects(placed[i].Window.Frame(), placed[j].Window.Frame()) {
+				placed[i].overlaps = append(placed[i].overlaps, placed[j].AppName)
+			}
+		}
+	}
+	return placed
+}
+
+func offScreenState(screens []ax.Screen, w ax.Window) string {
+	r := w.Frame()
+	onAny := false
+	for _, s := range screens {
+		sr := s.Bounds()
+		if r.X >= sr.X && r.Y >= sr.Y && r.X+r.W <= sr.X+sr.W && r.Y+r.H <= sr.Y+sr.H {
+			return ""
+		}
+		if intersects(r, sr) {
+			onAny = true
+		}
+	}
+	if onAny {
+		return "partly off-screen"
+	}
+	return "off-screen"
+}
+
+func intersects(a, b window.Rect) bool {
+	return a.X < b.X+b.W && b.X < a.X+a.W && a.Y < b.Y+b.H && b.Y < a.Y+a.H
+}
+
+// mapCanvas maps global coordinates onto a grid of terminal cells. A cell is about
+// twice as tall as it is wide, so rows cover twice the pixels of columns.
+type mapCanvas struct {
+	cells      [][]rune
+	minX, minY int
+	scale      float64 // pixels per column
+}
+
+func (c *mapCanvas) col(x int) int { return int(math.Round(float64(x-c.minX) / c.scale)) }
+func (c *mapCanvas) row(y int) int { return int(math.Round(float64(y-c.minY) / (c.scale * 2))) }
+
+// box returns the cell bounds of r; right and bottom are inclusive and at least one
+// cell away from left and top so that every box has visible borders.
+func (c *mapCanvas) box(r window.Rect) (left, top, right, bottom int) {
+	left, top = c.col(r.X), c.row(r.Y)
+	right, bottom = max(c.col(r.X+r.W)-1, left+1), max(c.row(r.Y+r.H)-1, top+1)
+	return left, top, right, bottom
+}
+
+func (c *mapCanvas) set(col, row int, ch rune) {
+	if row >= 0 && row < len(c.cells) && col >= 0 && col < len(c.cells[row]) {
+		c.cells[row][col] = ch
+	}
+}
+
+func (c *mapCanvas) drawBox(r window.Rect, corner, horiz, vert rune) {
+	left, top, right, bottom := c.box(r)
+	for x := left + 1; x < right; x++ {
+		c.set(x, top, horiz)
+		c.set(x, bottom, horiz)
+	}
+	for y := top + 1; y < bottom; y++ {
+		c.set(left, y, vert)
+		c.set(right, y, vert)
+	}
+	for _, p := range [][2]int{{left, top}, {right, top}, {left, bottom}, {right, bottom}} {
+		c.set(p[0], p[1], corner)
+	}
+}
+
+// label writes text into the top border (or the bottom border when bottom is set)
+// of the box of r just after its corner, cut to fit.
+func (c *mapCanvas) label(r window.Rect, text string, bottom bool) {
+	left, top, right, low := c.box(r)
+	if bottom {
+		top = low
+	}
+	runes := []rune(text)
+	if n := right - left - 1; len(runes) > n {
+		runes = runes[:max(n, 0)]
+	}
+	for i, ch := range runes {
+		c.set(left+1+i, top, ch)
+	}
+}
+
+// renderMap draws screens and windows scaled to fit width columns.
+func renderMap(screens []ax.Screen, windows []mapWindow, width int) []string {
+	var rects []window.Rect
+	for _, s := range screens {
+		rects = append(rects, s.Bounds())
+	}
+	for _, w := range windows {
+		rects = append(rects, w.Window.Frame())
+	}
+	minX, minY, maxX, maxY := rects[0].X, rects[0].Y, rects[0].X+rects[0].W, rects[0].Y+rects[0].H
+	for _, r := range rects[1:] {
+		minX, minY = min(minX, r.X), min(minY, r.Y)
+		maxX, maxY = max(maxX, r.X+r.W), max(maxY, r.Y+r.H)
+	}
+
+	c := &mapCanvas{minX: minX, minY: minY, scale: float64(maxX-minX) / float64(width)}
+	if c.scale <= 0 {
+		c.scale = 1
+	}
+	rows := c.row(maxY) + 1
+	c.cells = make([][]rune, rows)
+	for i := range c.cells {
+		c.cells[i] = []rune(strings.Repeat(" ", width+1))
+	}
+
+	for _, s := range screens {
+		c.drawBox(s.Bounds(), screenCorner, screenHoriz, screenVert)
+	}
+
+	// mark the cells covered by more than one window before drawing window borders
+	coverage := make([][]int, rows)
+	for i := range coverage {
+		coverage[i] = make([]int, width+1)
+	}
+	for _, w := range windows {
+		left, top, right, bottom := c.box(w.Window.Frame())
+		for y := max(top, 0); y <= bottom && y < rows; y++ {
+			for x := max(left, 0); x <= right && x <= width; x++ {
+				coverage[y][x]++
+			}
+		}
+	}
+	for y := range coverage {
+		for x, n := range coverage[y] {
+			if n > 1 {
+				c.set(x, y, overlap)
+			}
+		}
+	}
+
+	for _, w := range windows {
+		if w.offScreen != "" {
+			c.drawBox(w.Window.Frame(), offScreen, offScreen, offScreen)
+		} else {
+			c.drawBox(w.Window.Frame(), windowCorner, windowHoriz, windowVert)
+		}
+	}
+	// windows are labeled at the top and screens at the bottom so that a window in
+	// the top-left corner of a screen does not hide the screen's name
+	for _, w := range windows {
+		c.label(w.Window.Frame(), w.AppName, false)
+	}
+	for i, s := range screens {
+		c.label(s.Bounds(), fmt.Sprintf("#%d %s", i+1, s.Name), true)
+	}
+
+	lines := make([]string, 0, rows)
+	for _, row := range c.cells {
+		lines = append(lines, strings.TrimRight(string(row), " "))
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
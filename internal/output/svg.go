@@ -0,0 +1,134 @@
+package output
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// svgWidth is the width of SVG drawings in pixels; the height follows the aspect
+// ratio of the screens.
+const svgWidth = 960
+
+// svgPadding is the space around the screens in SVG pixels.
+const svgPadding = 16
+
+// svgCharWidth approximates the advance of a 12px sans-serif character, used to cut
+// labels to the width of their frame.
+const svgCharWidth = 7
+
+// svgPalette holds the fill colors of window frames; an app always gets the same color.
+var svgPalette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f"}
+
+// LayoutFrame is a labeled rectangle drawn by PrintLayoutSVG.
+type LayoutFrame struct {
+	Label  string // app name, drawn in bold and used to pick the color
+	Detail string // window title, drawn under the label
+	Rect   window.Rect
+}
+
+// PrintWindowSVG draws windows on their screens as an SVG image.
+// Minimized and hidden windows are not drawn.
+func (f *Formatter) PrintWindowSVG(screens []ax.Screen, windows []ax.Window) error {
+	frames := make([]LayoutFrame, 0, len(windows))
+	for _, w := range windows {
+		if w.State == ax.StateMinimized || w.State == ax.StateHidden || w.Width <= 0 || w.Height <= 0 {
+			continue
+		}
+		frames = append(frames, LayoutFrame{Label: w.AppName, Detail: w.Title, Rect: w.Frame()})
+	}
+	return f.PrintLayoutSVG("mado layout", screens, frames)
+}
+
+// PrintLayoutSVG writes a self-contained SVG image of screens in their real
+// arrangement with frames drawn on top, labeled and colored by app.
+func (f *Formatter) PrintLayoutSVG(title string, screens []ax.Screen, frames []LayoutFrame) error {
+	screens = window.OrderScreens(screens)
+	rects := make([]window.Rect, 0, len(screens)+len(frames))
+	for _, s := range screens {
+		rects = append(rects, s.Bounds())
+	}
+	for _, fr := range frames {
+		rects = append(rects, fr.Rect)
+	}
+	if len(rects) == 0 {
+		rects = append(rects, window.Rect{W: svgWidth, H: svgWidth / 2})
+	}
+
+	minX, minY, maxX, maxY := rects[0].X, rects[0].Y, rects[0].X+rects[0].W, rects[0].Y+rects[0].H
+	for _, r := range rects[1:] {
+		minX, minY = min(minX, r.X), min(minY, r.Y)
+		maxX, maxY = max(maxX, r.X+r.W), max(maxY, r.Y+r.H)
+	}
+	scale := float64(svgWidth-2*svgPadding) / float64(max(maxX-minX, 1))
+	height := int(float64(maxY-minY)*scale) + 2*svgPadding
+
+	// box converts r from global coordinates to SVG pixels
+	box := func(r window.Rect) (x, y, w, h float64) {
+		return float64(r.X-minX)*scale + svgPadding, float64(r.Y-minY)*scale + svgPadding, float64(r.W) * scale, float64(r.H) * scale
+	}
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="-apple-system, Helvetica, Arial, sans-serif" font-size="12">`+"\n",
+		svgWidth, height, svgWidth, height)
+	fmt.Fprintf(&b, "  <title>%s</title>\n", svgEscape(title))
+	fmt.Fprintf(&b, `  <rect width="%d" height="%d" fill="#ffffff"/>`+"\n", svgWidth, height)
+
+	for _, s := range screens {
+		x, y, w, h := box(s.Bounds())
+		fmt.Fprintf(&b, `  <rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#f2f2f2" stroke="#555555" stroke-width="2"/>`+"\n", x, y, w, h)
+	}
+
+	for _, fr := range frames {
+		x, y, w, h := box(fr.Rect)
+		b.WriteString("  <g>\n")
+		fmt.Fprintf(&b, `    <rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="4" fill="%s" fill-opacity="0.55" stroke="#333333"/>`+"\n",
+			x, y, w, h, svgColor(fr.Label))
+		fmt.Fprintf(&b, `    <text x="%.1f" y="%.1f" font-weight="bold">%s</text>`+"\n", x+6, y+18, svgEscape(svgFit(fr.Label, w-12)))
+		// the title needs a second line of text
+		if fr.Detail != "" && h > 40 {
+			fmt.Fprintf(&b, `    <text x="%.1f" y="%.1f">%s</text>`+"\n", x+6, y+34, svgEscape(svgFit(fr.Detail, w-12)))
+		}
+		b.WriteString("  </g>\n")
+	}
+	// screen labels go last so that windows do not cover them
+	for i, s := range screens {
+		x, y, w, h := box(s.Bounds())
+		label := fmt.Sprintf("#%d %s %dx%d", i+1, s.Name, s.Width, s.Height)
+		fmt.Fprintf(&b, `  <text x="%.1f" y="%.1f" fill="#333333">%s</text>`+"\n", x+6, y+h-6, svgEscape(svgFit(label, w-12)))
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(f.out, b.String())
+	return err
+}
+
+// svgColor picks the palette color of an app.
+func svgColor(app string) string {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(app))) //nolint:errcheck // hash.Hash never returns an error
+	return svgPalette[h.Sum32()%uint32(len(svgPalette))]
+}
+
+// svgFit cuts s to about width SVG pixels, marking the cut with an ellipsis.
+func svgFit(s string, width float64) string {
+	n := int(width / svgCharWidth)
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	if n <= 1 {
+		return ""
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
+
+// svgEscape escapes text for use in SVG character data.
+func svgEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
@@ -31,22 +31,97 @@ func (e *AllFullscreenError) Error() string {
 type ApplyResult struct {
 	RuleIndex int
 	AppFilter string
-	Affected  []ax.Window
-	Skipped   bool
-	Reason    string
-	Err       error
+	// Affected holds the matched windows with their target frames.
+	Affected []ax.Window
+	// Before holds the frames of the Affected windows prior to applying the rule (same order).
+	Before  []ax.Window
+	Skipped bool
+	Reason  string
+	Err     error
 }
 
 // ApplyOutcome holds the aggregate result of applying a preset.
 type ApplyOutcome struct {
 	PresetName string
 	Results    []ApplyResult
+
+	matched    int // windows matched by any rule, including fullscreen ones
+	fullscreen int // matched windows skipped because they are fullscreen
 }
 
 // Apply applies the named preset to matching windows.
 // ignoreApps contains app names to skip (case-insensitive). Rules targeting ignored apps
-// are skipped with reason "ignored".
-func Apply(ctx context.Context, svc ax.WindowService, presets []Preset, name string, ignoreApps []string) (*ApplyOutcome, error) {
+// are skipped with reason "ignored". aliases are the screen aliases rule screens may
+// refer to.
+func Apply(ctx context.Context, svc ax.WindowService, presets []Preset, name string, ignoreApps []string, aliases []window.ScreenAlias) (*ApplyOutcome, error) {
+	outcome, err := evaluate(ctx, svc, presets, name, ignoreApps, aliases, func(w ax.Window, r window.Rect, move, resize bool) error {
+		if move {
+			if err := svc.MoveWindow(ctx, w.ID, r.X, r.Y); err != nil {
+				return err
+			}
+		}
+		if resize {
+			if err := svc.ResizeWindow(ctx, w.ID, r.W, r.H); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 全マッチがフルスクリーンの場合
+	if outcome.matched > 0 && outcome.matched == outcome.fullscreen {
+		return outcome, &AllFullscreenError{Skipped: outcome.fullscreen}
+	}
+
+	// 部分成功の確認
+	var successCount, failCount int
+	for _, r := range outcome.Results {
+		if r.Err != nil {
+			failCount++
+		} else if !r.Skipped {
+			successCount++
+		}
+	}
+	if failCount > 0 && successCount > 0 {
+		var allAffected []ax.Window
+		for _, r := range outcome.Results {
+			allAffected = append(allAffected, r.Affected...)
+		}
+		return outcome, &ax.PartialSuccessError{
+			Affected: allAffected,
+			Cause:    fmt.Errorf("partial success: %d rules applied, %d failed", successCount, failCount),
+		}
+	}
+	if failCount > 0 && successCount == 0 {
+		// 全失敗の場合は最初のエラーを返す
+		for _, r := range outcome.Results {
+			if r.Err != nil {
+				return outcome, r.Err
+			}
+		}
+	}
+
+	return outcome, nil
+}
+
+// Plan runs the same matching as Apply, including first-match-wins bookkeeping,
+// and returns what Apply would do without moving or resizing any window.
+// Rules whose target frame cannot be computed carry the error in ApplyResult.Err.
+func Plan(ctx context.Context, svc ax.WindowService, presets []Preset, name string, ignoreApps []string, aliases []window.ScreenAlias) (*ApplyOutcome, error) {
+	return evaluate(ctx, svc, presets, name, ignoreApps, aliases, func(ax.Window, window.Rect, bool, bool) error {
+		return nil
+	})
+}
+
+// applyFunc moves (when move is set) and resizes (when resize is set) w to r.
+type applyFunc func(w ax.Window, r window.Rect, move, resize bool) error
+
+// evaluate matches the rules of the named preset against the current windows,
+// computes each target frame and hands it to apply.
+func evaluate(ctx context.Context, svc ax.WindowService, presets []Preset, name string, ignoreApps []string, aliases []window.ScreenAlias, apply applyFunc) (*ApplyOutcome, error) {
 	var target *Preset
 	for i := range presets {
 		if presets[i].Name == name {
@@ -63,37 +138,55 @@ func Apply(ctx context.Context, svc ax.WindowService, presets []Preset, name str
 		return nil, err
 	}
 
-	// 適用済みウィンドウの追跡 (PID+Title で一意に識別)
-	type winKey struct {
-		PID   uint32
-		Title string
+	// Screens are only needed to resolve relative geometry (percentages, expressions, snap),
+	// to scale recorded geometry and for aliases, selectors and "#N" screen references.
+	var screens []ax.Screen
+	for _, rule := range target.Rules {
+		if rule.needsScreen() || rule.scales(target.Scale) || window.NeedsScreens(aliases, rule.Screen) {
+			screens, err = svc.ListScreens(ctx)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
 	}
-	applied := make(map[winKey]bool)
+
+	// 適用済みウィンドウの追跡 (Window.ID で一意に識別)
+	applied := make(map[uint32]bool)
 
 	outcome := &ApplyOutcome{PresetName: name}
-	totalMatched := 0
-	totalFullscreen := 0
 
 	for i, rule := range target.Rules {
 		// Skip rules whose app is in the ignore list
 		if window.IsIgnoredApp(rule.App, ignoreApps) {
 			outcome.Results = append(outcome.Results, ApplyResult{
 				RuleIndex: i,
-				AppFilter: rule.App,
+				AppFilter: rule.target(),
 				Skipped:   true,
 				Reason:    "ignored",
 			})
 			continue
 		}
 
+		// Aliases, selectors and "#N" (the N-th screen) are matched by ID from here on.
+		rule.Screen = window.ResolveScreenRef(screens, aliases, rule.Screen)
+
 		// ルールに基づいてウィンドウをフィルタリング
-		matches := filterForRule(windows, rule)
+		m, err := window.CompileMatch(rule.matchSpec())
+		if err != nil {
+			outcome.Results = append(outcome.Results, ApplyResult{
+				RuleIndex: i,
+				AppFilter: rule.target(),
+				Err:       fmt.Errorf("rule[%d]: %w", i, err),
+			})
+			continue
+		}
+		matches := filterForRule(windows, rule, m)
 
 		// 適用済みウィンドウを除外 (first match wins)
 		var candidates []ax.Window
 		for _, w := range matches {
-			key := winKey{PID: w.PID, Title: w.Title}
-			if !applied[key] {
+			if !applied[w.ID] {
 				candidates = append(candidates, w)
 			}
 		}
@@ -101,7 +194,7 @@ func Apply(ctx context.Context, svc ax.WindowService, presets []Preset, name str
 		if len(candidates) == 0 {
 			outcome.Results = append(outcome.Results, ApplyResult{
 				RuleIndex: i,
-				AppFilter: rule.App,
+				AppFilter: rule.target(),
 				Skipped:   true,
 				Reason:    "no_match",
 			})
@@ -114,108 +207,103 @@ func Apply(ctx context.Context, svc ax.WindowService, presets []Preset, name str
 		for _, w := range candidates {
 			if w.State == ax.StateFullscreen {
 				fullscreenCount++
-				totalFullscreen++
 				continue
 			}
 			normal = append(normal, w)
 		}
-		totalMatched += len(candidates)
+		outcome.matched += len(candidates)
+		outcome.fullscreen += fullscreenCount
 
 		if len(normal) == 0 && fullscreenCount > 0 {
 			outcome.Results = append(outcome.Results, ApplyResult{
 				RuleIndex: i,
-				AppFilter: rule.App,
+				AppFilter: rule.target(),
 				Skipped:   true,
 				Reason:    "fullscreen",
 			})
 			// フルスクリーンでもappliedセットに追加
 			for _, w := range candidates {
-				applied[winKey{PID: w.PID, Title: w.Title}] = true
+				applied[w.ID] = true
 			}
 			continue
 		}
 
-		// マッチした全ウィンドウに対してMoveWindow/ResizeWindowを実行
-		var ruleAffected []ax.Window
-		var ruleErr error
+		// マッチした全ウィンドウに対して目標フレームを計算して適用
+		result := ApplyResult{RuleIndex: i, AppFilter: rule.target()}
 
 		for _, w := range normal {
-			if len(rule.Position) == 2 {
-				if err := svc.MoveWindow(ctx, w.PID, w.Title, rule.Position[0], rule.Position[1]); err != nil {
-					ruleErr = err
+			wr := rule
+			if rule.scalesOn(screens, target.Scale) {
+				s, ok := matchedScreen(screens, rule, w)
+				if !ok {
+					result.Err = fmt.Errorf("rule[%d]: cannot scale geometry: no screen found for window %q", i, w.Title)
 					break
 				}
-				w.X = rule.Position[0]
-				w.Y = rule.Position[1]
+				wr = rule.scaleTo(s, target.Scale)
 			}
-			if len(rule.Size) == 2 {
-				if err := svc.ResizeWindow(ctx, w.PID, w.Title, rule.Size[0], rule.Size[1]); err != nil {
-					ruleErr = err
+			var scr *ax.Screen
+			if rule.needsScreen() {
+				scr = targetScreen(screens, rule, w)
+				if scr == nil {
+					result.Err = fmt.Errorf("rule[%d]: cannot resolve relative geometry: no screen found for window %q", i, w.Title)
 					break
 				}
-				w.Width = rule.Size[0]
-				w.Height = rule.Size[1]
 			}
-			ruleAffected = append(ruleAffected, w)
-			applied[winKey{PID: w.PID, Title: w.Title}] = true
+			r, move, resize, err := targetFrame(wr, scr, w)
+			if err != nil {
+				result.Err = fmt.Errorf("rule[%d]: %w", i, err)
+				break
+			}
+			if err := apply(w, r, move, resize); err != nil {
+				result.Err = err
+				break
+			}
+			result.Before = append(result.Before, w)
+			w.X, w.Y, w.Width, w.Height = r.X, r.Y, r.W, r.H
+			result.Affected = append(result.Affected, w)
+			applied[w.ID] = true
 		}
 
-		outcome.Results = append(outcome.Results, ApplyResult{
-			RuleIndex: i,
-			AppFilter: rule.App,
-			Affected:  ruleAffected,
-			Err:       ruleErr,
-		})
+		outcome.Results = append(outcome.Results, result)
 	}
 
-	// 全マッチがフルスクリーンの場合
-	if totalMatched > 0 && totalMatched == totalFullscreen {
-		return outcome, &AllFullscreenError{Skipped: totalFullscreen}
-	}
+	return outcome, nil
+}
 
-	// 部分成功の確認
-	var successCount, failCount int
-	for _, r := range outcome.Results {
-		if r.Err != nil {
-			failCount++
-		} else if !r.Skipped {
-			successCount++
-		}
+// targetFrame computes the frame rule places w in. Components the rule does not set
+// keep the window's current values; move and resize report which ones the rule sets.
+func targetFrame(rule Rule, scr *ax.Screen, w ax.Window) (r window.Rect, move, resize bool, err error) {
+	r = w.Frame()
+	if rule.Snap != "" {
+		r, err = window.SnapFrame(w, *scr, rule.snapOptions())
+		return r, true, true, err
 	}
-	if failCount > 0 && successCount > 0 {
-		var allAffected []ax.Window
-		for _, r := range outcome.Results {
-			allAffected = append(allAffected, r.Affected...)
-		}
-		return outcome, &ax.PartialSuccessError{
-			Affected: allAffected,
-			Cause:    fmt.Errorf("partial success: %d rules applied, %d failed", successCount, failCount),
+	if len(rule.Position) == 2 {
+		if r.X, r.Y, err = resolvePosition(rule, scr); err != nil {
+			return r, false, false, err
 		}
+		move = true
 	}
-	if failCount > 0 && successCount == 0 {
-		// 全失敗の場合は最初のエラーを返す
-		for _, r := range outcome.Results {
-			if r.Err != nil {
-				return outcome, r.Err
-			}
+	if len(rule.Size) == 2 {
+		if r.W, r.H, err = resolveSize(rule, scr); err != nil {
+			return r, false, false, err
 		}
+		// relative sizes can only be checked once resolved against the screen
+		if r.W <= 0 || r.H <= 0 {
+			return r, false, false, fmt.Errorf("size resolves to %dx%d: width and height must be positive", r.W, r.H)
+		}
+		resize = true
 	}
-
-	return outcome, nil
+	return r, move, resize, nil
 }
 
 // filterForRule はルールの条件に基づいてウィンドウを絞り込む
-func filterForRule(windows []ax.Window, rule Rule) []ax.Window {
+func filterForRule(windows []ax.Window, rule Rule, m *window.Matcher) []ax.Window {
 	var result []ax.Window
-	lowerRuleTitle := strings.ToLower(rule.Title)
 
 	for _, w := range windows {
-		// app: case-insensitive exact match
-		if !strings.EqualFold(w.AppName, rule.App) {
-			continue
-		}
-		// title: case-insensitive partial match
-		if rule.Title != "" && !strings.Contains(strings.ToLower(w.Title), lowerRuleTitle) {
+		// app / title: exact or glob app, partial title, title_regex and exclusions
+		if !m.Match(w) {
 			continue
 		}
 		// screen: reuse MatchScreen
@@ -239,3 +327,23 @@ func filterForRule(windows []ax.Window, rule Rule) []ax.Window {
 	}
 	return result
 }
+
+// targetScreen returns the screen that relative geometry of rule is resolved against for w:
+// the matched screen limited to rule.Area.
+func targetScreen(screens []ax.Screen, rule Rule, w ax.Window) *ax.Screen {
+	s, ok := matchedScreen(screens, rule, w)
+	if !ok {
+		return nil
+	}
+	s = window.ScreenArea(s, rule.Area)
+	return &s
+}
+
+// matchedScreen returns the screen named by rule.Screen, or else the window's current screen.
+// A negated screen ("!ID", from not-primary) names no single screen, so the window's own is used.
+func matchedScreen(screens []ax.Screen, rule Rule, w ax.Window) (ax.Screen, bool) {
+	if rule.Screen != "" && !strings.HasPrefix(rule.Screen, "!") {
+		return window.FindScreen(screens, rule.Screen)
+	}
+	return window.ScreenOf(screens, w)
+}
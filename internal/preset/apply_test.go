@@ -7,6 +7,7 @@ import (
 
 	"github.com/peacock0803sz/mado/internal/ax"
 	"github.com/peacock0803sz/mado/internal/preset"
+	"github.com/peacock0803sz/mado/internal/window"
 )
 
 var testPresets = []preset.Preset{
@@ -14,32 +15,32 @@ var testPresets = []preset.Preset{
 		Name:        "coding",
 		Description: "Editor left, terminal right",
 		Rules: []preset.Rule{
-			{App: "Code", Position: []int{0, 0}, Size: []int{960, 1080}},
-			{App: "Terminal", Position: []int{960, 0}, Size: []int{960, 1080}},
+			{App: "Code", Position: []preset.Expr{"0", "0"}, Size: []preset.Expr{"960", "1080"}},
+			{App: "Terminal", Position: []preset.Expr{"960", "0"}, Size: []preset.Expr{"960", "1080"}},
 		},
 	},
 	{
 		Name:        "meeting",
 		Description: "Browser and notes",
 		Rules: []preset.Rule{
-			{App: "Safari", Title: "Zoom", Position: []int{0, 0}, Size: []int{1280, 1080}},
-			{App: "Notes", Position: []int{1280, 0}, Size: []int{640, 1080}},
+			{App: "Safari", Title: "Zoom", Position: []preset.Expr{"0", "0"}, Size: []preset.Expr{"1280", "1080"}},
+			{App: "Notes", Position: []preset.Expr{"1280", "0"}, Size: []preset.Expr{"640", "1080"}},
 		},
 	},
 }
 
 var testWindows = []ax.Window{
-	{AppName: "Code", Title: "main.go", PID: 100, State: ax.StateNormal, Width: 800, Height: 600},
-	{AppName: "Terminal", Title: "zsh", PID: 200, State: ax.StateNormal, Width: 800, Height: 600},
-	{AppName: "Safari", Title: "GitHub", PID: 300, State: ax.StateNormal, Width: 1440, Height: 900},
-	{AppName: "Safari", Title: "Zoom Meeting", PID: 300, State: ax.StateNormal, Width: 1440, Height: 900},
-	{AppName: "Safari", Title: "Apple", PID: 300, State: ax.StateNormal, Width: 1200, Height: 800},
-	{AppName: "Notes", Title: "Meeting Notes", PID: 400, State: ax.StateNormal, Width: 640, Height: 480},
+	{ID: 1, AppName: "Code", BundleID: "com.microsoft.VSCode", Title: "main.go", PID: 100, State: ax.StateNormal, Width: 800, Height: 600},
+	{ID: 2, AppName: "Terminal", Title: "zsh", PID: 200, State: ax.StateNormal, Width: 800, Height: 600},
+	{ID: 3, AppName: "Safari", Title: "GitHub", PID: 300, State: ax.StateNormal, Width: 1440, Height: 900},
+	{ID: 4, AppName: "Safari", Title: "Zoom Meeting", PID: 300, State: ax.StateNormal, Width: 1440, Height: 900},
+	{ID: 5, AppName: "Safari", Title: "Apple", PID: 300, State: ax.StateNormal, Width: 1200, Height: 800},
+	{ID: 6, AppName: "Notes", Title: "Meeting Notes", PID: 400, State: ax.StateNormal, Width: 640, Height: 480},
 }
 
 func TestApply_Success(t *testing.T) {
 	svc := &ax.MockWindowService{Windows: testWindows}
-	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", nil)
+	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -60,12 +61,12 @@ func TestApply_SkipNonRunningApp(t *testing.T) {
 	presets := []preset.Preset{{
 		Name: "test",
 		Rules: []preset.Rule{
-			{App: "Code", Position: []int{0, 0}},
-			{App: "Slack", Position: []int{960, 0}}, // Slackは起動していない
+			{App: "Code", Position: []preset.Expr{"0", "0"}},
+			{App: "Slack", Position: []preset.Expr{"960", "0"}}, // Slackは起動していない
 		},
 	}}
 	svc := &ax.MockWindowService{Windows: testWindows}
-	outcome, err := preset.Apply(context.Background(), svc, presets, "test", nil)
+	outcome, err := preset.Apply(context.Background(), svc, presets, "test", nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -83,11 +84,11 @@ func TestApply_SkipNonRunningApp(t *testing.T) {
 
 func TestApply_SkipFullscreen(t *testing.T) {
 	windows := []ax.Window{
-		{AppName: "Code", Title: "main.go", PID: 100, State: ax.StateFullscreen, Width: 1440, Height: 900},
-		{AppName: "Terminal", Title: "zsh", PID: 200, State: ax.StateNormal, Width: 800, Height: 600},
+		{ID: 7, AppName: "Code", Title: "main.go", PID: 100, State: ax.StateFullscreen, Width: 1440, Height: 900},
+		{ID: 8, AppName: "Terminal", Title: "zsh", PID: 200, State: ax.StateNormal, Width: 800, Height: 600},
 	}
 	svc := &ax.MockWindowService{Windows: windows}
-	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", nil)
+	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -106,11 +107,11 @@ func TestApply_SkipFullscreen(t *testing.T) {
 func TestApply_AllFullscreen(t *testing.T) {
 	// 全マッチがフルスクリーンの場合はAllFullscreenErrorを返す
 	windows := []ax.Window{
-		{AppName: "Code", Title: "main.go", PID: 100, State: ax.StateFullscreen, Width: 1440, Height: 900},
-		{AppName: "Terminal", Title: "zsh", PID: 200, State: ax.StateFullscreen, Width: 1440, Height: 900},
+		{ID: 9, AppName: "Code", Title: "main.go", PID: 100, State: ax.StateFullscreen, Width: 1440, Height: 900},
+		{ID: 10, AppName: "Terminal", Title: "zsh", PID: 200, State: ax.StateFullscreen, Width: 1440, Height: 900},
 	}
 	svc := &ax.MockWindowService{Windows: windows}
-	_, err := preset.Apply(context.Background(), svc, testPresets, "coding", nil)
+	_, err := preset.Apply(context.Background(), svc, testPresets, "coding", nil, nil)
 	if err == nil {
 		t.Fatal("expected AllFullscreenError, got nil")
 	}
@@ -125,11 +126,11 @@ func TestApply_MultiWindowMatch(t *testing.T) {
 	presets := []preset.Preset{{
 		Name: "browse",
 		Rules: []preset.Rule{
-			{App: "Safari", Position: []int{0, 0}, Size: []int{1440, 900}},
+			{App: "Safari", Position: []preset.Expr{"0", "0"}, Size: []preset.Expr{"1440", "900"}},
 		},
 	}}
 	svc := &ax.MockWindowService{Windows: testWindows}
-	outcome, err := preset.Apply(context.Background(), svc, presets, "browse", nil)
+	outcome, err := preset.Apply(context.Background(), svc, presets, "browse", nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -149,12 +150,12 @@ func TestApply_FirstMatchDedup(t *testing.T) {
 	presets := []preset.Preset{{
 		Name: "dedup",
 		Rules: []preset.Rule{
-			{App: "Safari", Title: "Zoom", Position: []int{0, 0}, Size: []int{1280, 1080}},
-			{App: "Safari", Position: []int{1280, 0}, Size: []int{640, 1080}},
+			{App: "Safari", Title: "Zoom", Position: []preset.Expr{"0", "0"}, Size: []preset.Expr{"1280", "1080"}},
+			{App: "Safari", Position: []preset.Expr{"1280", "0"}, Size: []preset.Expr{"640", "1080"}},
 		},
 	}}
 	svc := &ax.MockWindowService{Windows: testWindows}
-	outcome, err := preset.Apply(context.Background(), svc, presets, "dedup", nil)
+	outcome, err := preset.Apply(context.Background(), svc, presets, "dedup", nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -181,7 +182,7 @@ type partialMockService struct {
 	resizeCallCount    int
 }
 
-func (m *partialMockService) MoveWindow(_ context.Context, _ uint32, _ string, _, _ int) error {
+func (m *partialMockService) MoveWindow(_ context.Context, _ uint32, _, _ int) error {
 	m.moveCallCount++
 	if m.moveCallCount <= m.moveSuccessCount {
 		return nil
@@ -189,7 +190,7 @@ func (m *partialMockService) MoveWindow(_ context.Context, _ uint32, _ string, _
 	return m.MoveErr
 }
 
-func (m *partialMockService) ResizeWindow(_ context.Context, _ uint32, _ string, _, _ int) error {
+func (m *partialMockService) ResizeWindow(_ context.Context, _ uint32, _, _ int) error {
 	m.resizeCallCount++
 	if m.resizeCallCount <= m.resizeSuccessCount {
 		return nil
@@ -206,7 +207,7 @@ func TestApply_PartialSuccess(t *testing.T) {
 		moveSuccessCount:   1,
 		resizeSuccessCount: 100,
 	}
-	_, err := preset.Apply(context.Background(), svc, testPresets, "coding", nil)
+	_, err := preset.Apply(context.Background(), svc, testPresets, "coding", nil, nil)
 	if err == nil {
 		// Codeは1つのウィンドウなので成功する。
 		// Terminalも1つなので2回目のMoveWindowがエラーになる。
@@ -221,7 +222,7 @@ func TestApply_PartialSuccess(t *testing.T) {
 
 func TestApply_NotFound(t *testing.T) {
 	svc := &ax.MockWindowService{Windows: testWindows}
-	_, err := preset.Apply(context.Background(), svc, testPresets, "nonexistent", nil)
+	_, err := preset.Apply(context.Background(), svc, testPresets, "nonexistent", nil, nil)
 	if err == nil {
 		t.Fatal("expected NotFoundError, got nil")
 	}
@@ -234,7 +235,7 @@ func TestApply_NotFound(t *testing.T) {
 func TestApply_IgnoredAppSkipped(t *testing.T) {
 	svc := &ax.MockWindowService{Windows: testWindows}
 	ignoreApps := []string{"Code"}
-	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", ignoreApps)
+	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", ignoreApps, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -253,7 +254,7 @@ func TestApply_IgnoredAppSkipped(t *testing.T) {
 func TestApply_IgnoredAppNonIgnoredStillApplies(t *testing.T) {
 	svc := &ax.MockWindowService{Windows: testWindows}
 	ignoreApps := []string{"Code"}
-	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", ignoreApps)
+	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", ignoreApps, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -275,7 +276,7 @@ func TestApply_IgnoredAppNonIgnoredStillApplies(t *testing.T) {
 
 func TestApply_EmptyIgnoreAppsNoSkipping(t *testing.T) {
 	svc := &ax.MockWindowService{Windows: testWindows}
-	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", nil)
+	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -290,7 +291,7 @@ func TestApply_EmptyIgnoreAppsNoSkipping(t *testing.T) {
 func TestApply_IgnoredAppCaseInsensitive(t *testing.T) {
 	svc := &ax.MockWindowService{Windows: testWindows}
 	ignoreApps := []string{"code"} // lowercase, rule.App is "Code"
-	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", ignoreApps)
+	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", ignoreApps, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -309,7 +310,7 @@ func TestApply_IgnoredAppCaseInsensitive(t *testing.T) {
 func TestApply_IgnoredReasonInResult(t *testing.T) {
 	svc := &ax.MockWindowService{Windows: testWindows}
 	ignoreApps := []string{"Code"}
-	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", ignoreApps)
+	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", ignoreApps, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -339,7 +340,7 @@ func TestApply_IgnoredPlusPartialFailure(t *testing.T) {
 		MoveErr: errors.New("AX error"),
 	}
 	ignoreApps := []string{"Code"}
-	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", ignoreApps)
+	outcome, err := preset.Apply(context.Background(), svc, testPresets, "coding", ignoreApps, nil)
 	// Terminal move fails, so we expect an error
 	if err == nil {
 		t.Fatal("expected error for Terminal move failure, got nil")
@@ -356,3 +357,332 @@ func TestApply_IgnoredPlusPartialFailure(t *testing.T) {
 		t.Error("expected ignored result even with partial failure")
 	}
 }
+
+func TestApply_Snap(t *testing.T) {
+	presets := []preset.Preset{{
+		Name: "snap",
+		Rules: []preset.Rule{
+			{App: "Code", Snap: "left-two-thirds"},
+			{App: "Terminal", Snap: "right-third", Gap: 8, Margin: 8},
+		},
+	}}
+	windows := []ax.Window{
+		{ID: 11, AppName: "Code", Title: "main.go", PID: 100, State: ax.StateNormal, ScreenID: 1},
+		{ID: 12, AppName: "Terminal", Title: "zsh", PID: 200, State: ax.StateNormal, ScreenID: 1},
+	}
+	screens := []ax.Screen{{ID: 1, Name: "Built-in", Width: 1920, Height: 1080, IsPrimary: true}}
+	svc := &ax.MockWindowService{Windows: windows, Screens: screens}
+
+	outcome, err := preset.Apply(context.Background(), svc, presets, "snap", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	code := outcome.Results[0].Affected[0]
+	if code.X != 0 || code.Y != 0 || code.Width != 1280 || code.Height != 1080 {
+		t.Errorf("Code frame = (%d,%d %dx%d), want (0,0 1280x1080)", code.X, code.Y, code.Width, code.Height)
+	}
+	term := outcome.Results[1].Affected[0]
+	if term.X != 1283 || term.Y != 8 || term.Width != 629 || term.Height != 1064 {
+		t.Errorf("Terminal frame = (%d,%d %dx%d), want (1283,8 629x1064)", term.X, term.Y, term.Width, term.Height)
+	}
+}
+
+func TestApply_VisibleArea(t *testing.T) {
+	presets := []preset.Preset{{
+		Name: "visible",
+		Rules: []preset.Rule{
+			{App: "Code", Snap: "maximize", Area: "visible"},
+			{App: "Terminal", Position: []preset.Expr{"0%", "50%"}, Size: []preset.Expr{"100%", "50%"}, Area: "visible"},
+		},
+	}}
+	windows := []ax.Window{
+		{ID: 11, AppName: "Code", Title: "main.go", PID: 100, State: ax.StateNormal, ScreenID: 1},
+		{ID: 12, AppName: "Terminal", Title: "zsh", PID: 200, State: ax.StateNormal, ScreenID: 1},
+	}
+	screens := []ax.Screen{{ID: 1, Name: "Built-in", Width: 1920, Height: 1080, IsPrimary: true,
+		VisibleFrame: ax.Rect{X: 0, Y: 25, W: 1920, H: 985}}}
+	svc := &ax.MockWindowService{Windows: windows, Screens: screens}
+
+	outcome, err := preset.Apply(context.Background(), svc, presets, "visible", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	code := outcome.Results[0].Affected[0]
+	if code.X != 0 || code.Y != 25 || code.Width != 1920 || code.Height != 985 {
+		t.Errorf("Code frame = (%d,%d %dx%d), want (0,25 1920x985)", code.X, code.Y, code.Width, code.Height)
+	}
+	term := outcome.Results[1].Affected[0]
+	if term.X != 0 || term.Y != 518 || term.Width != 1920 || term.Height != 493 {
+		t.Errorf("Terminal frame = (%d,%d %dx%d), want (0,518 1920x493)", term.X, term.Y, term.Width, term.Height)
+	}
+}
+
+func TestApply_Scale(t *testing.T) {
+	// recorded on a 2560x1440 display: the right half of the screen
+	rule := preset.Rule{
+		App: "Code", Position: []preset.Expr{"1280", "0"}, Size: []preset.Expr{"1280", "1440"},
+		Reference: &ax.Rect{X: 0, Y: 0, W: 2560, H: 1440},
+	}
+	windows := []ax.Window{{ID: 11, AppName: "Code", Title: "main.go", PID: 100, State: ax.StateNormal, ScreenID: 1}}
+	tests := []struct {
+		scale  string
+		screen ax.Screen
+		want   ax.Window
+	}{
+		{"", ax.Screen{ID: 1, Width: 1920, Height: 1080}, ax.Window{X: 960, Y: 0, Width: 960, Height: 1080}},
+		{preset.ScaleProportional, ax.Screen{ID: 1, X: 1440, Width: 1920, Height: 1200}, ax.Window{X: 2400, Y: 0, Width: 960, Height: 1200}},
+		{preset.ScaleFit, ax.Screen{ID: 1, Width: 1920, Height: 1200}, ax.Window{X: 960, Y: 60, Width: 960, Height: 1080}},
+		{preset.ScaleNone, ax.Screen{ID: 1, Width: 1920, Height: 1080}, ax.Window{X: 1280, Y: 0, Width: 1280, Height: 1440}},
+		// the reference screen itself: the recorded frame is used as is
+		{preset.ScaleFit, ax.Screen{ID: 1, Width: 2560, Height: 1440}, ax.Window{X: 1280, Y: 0, Width: 1280, Height: 1440}},
+		// same size at another origin: the recorded frame moves with the screen
+		{preset.ScaleFit, ax.Screen{ID: 1, X: 100, Width: 2560, Height: 1440}, ax.Window{X: 1380, Y: 0, Width: 1280, Height: 1440}},
+	}
+	for _, tt := range tests {
+		presets := []preset.Preset{{Name: "scaled", Scale: tt.scale, Rules: []preset.Rule{rule}}}
+		svc := &ax.MockWindowService{Windows: windows, Screens: []ax.Screen{tt.screen}}
+		outcome, err := preset.Apply(context.Background(), svc, presets, "scaled", nil, nil)
+		if err != nil {
+			t.Fatalf("scale %q: unexpected error: %v", tt.scale, err)
+		}
+		got := outcome.Results[0].Affected[0]
+		if got.X != tt.want.X || got.Y != tt.want.Y || got.Width != tt.want.Width || got.Height != tt.want.Height {
+			t.Errorf("scale %q on %dx%d: frame = (%d,%d %dx%d), want (%d,%d %dx%d)", tt.scale, tt.screen.Width, tt.screen.Height,
+				got.X, got.Y, got.Width, got.Height, tt.want.X, tt.want.Y, tt.want.Width, tt.want.Height)
+		}
+	}
+}
+
+func TestApply_ScreenIndex(t *testing.T) {
+	presets := []preset.Preset{{
+		Name: "external",
+		Rules: []preset.Rule{
+			{App: "Safari", Screen: "#2", Position: []preset.Expr{"0%", "0%"}, Size: []preset.Expr{"50%", "100%"}},
+		},
+	}}
+	// Built-in sits to the right of the external display, so the external one is #1.
+	screens := []ax.Screen{
+		{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900, IsPrimary: true},
+		{ID: 2, Name: "External", X: -2560, Y: 0, Width: 2560, Height: 1440},
+	}
+	windows := []ax.Window{
+		{ID: 13, AppName: "Safari", Title: "GitHub", PID: 100, State: ax.StateNormal, ScreenID: 2},
+		{ID: 14, AppName: "Safari", Title: "Apple", PID: 100, State: ax.StateNormal, ScreenID: 1},
+	}
+	svc := &ax.MockWindowService{Windows: windows, Screens: screens}
+
+	outcome, err := preset.Apply(context.Background(), svc, presets, "external", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	affected := outcome.Results[0].Affected
+	if len(affected) != 1 || affected[0].Title != "Apple" {
+		t.Fatalf("expected only the window on #2 (Built-in), got %+v", affected)
+	}
+	w := affected[0]
+	if w.X != 0 || w.Y != 0 || w.Width != 720 || w.Height != 900 {
+		t.Errorf("frame = (%d,%d %dx%d), want (0,0 720x900)", w.X, w.Y, w.Width, w.Height)
+	}
+}
+
+func TestApply_ScreenAlias(t *testing.T) {
+	presets := []preset.Preset{{
+		Name: "external",
+		Rules: []preset.Rule{
+			{App: "Safari", Screen: "external", Position: []preset.Expr{"0%", "0%"}, Size: []preset.Expr{"50%", "100%"}},
+		},
+	}}
+	screens := []ax.Screen{
+		{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900, IsPrimary: true},
+		{ID: 2, Name: "Display 2", X: -2560, Y: 0, Width: 2560, Height: 1440},
+	}
+	windows := []ax.Window{
+		{ID: 13, AppName: "Safari", Title: "GitHub", PID: 100, State: ax.StateNormal, ScreenID: 2},
+		{ID: 14, AppName: "Safari", Title: "Apple", PID: 100, State: ax.StateNormal, ScreenID: 1},
+	}
+	svc := &ax.MockWindowService{Windows: windows, Screens: screens}
+
+	aliases := []window.ScreenAlias{{Alias: "external", Resolution: "2560x1440"}}
+
+	outcome, err := preset.Apply(context.Background(), svc, presets, "external", nil, aliases)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	affected := outcome.Results[0].Affected
+	if len(affected) != 1 || affected[0].Title != "GitHub" {
+		t.Fatalf("expected only the window on the external screen, got %+v", affected)
+	}
+	w := affected[0]
+	if w.X != -2560 || w.Y != 0 || w.Width != 1280 || w.Height != 1440 {
+		t.Errorf("frame = (%d,%d %dx%d), want (-2560,0 1280x1440)", w.X, w.Y, w.Width, w.Height)
+	}
+}
+
+func TestApply_ScreenSelector(t *testing.T) {
+	presets := []preset.Preset{{
+		Name: "docked",
+		Rules: []preset.Rule{
+			{App: "Mail", Screen: "primary", Position: []preset.Expr{"0%", "0%"}, Size: []preset.Expr{"100%", "100%"}},
+			{App: "Safari", Screen: "not-primary", Position: []preset.Expr{"0%", "0%"}, Size: []preset.Expr{"50%", "100%"}},
+		},
+	}}
+	screens := []ax.Screen{
+		{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900, IsPrimary: true},
+		{ID: 2, Name: "Display 2", X: -2560, Y: 0, Width: 2560, Height: 1440},
+		{ID: 3, Name: "Display 3", X: 1440, Y: 0, Width: 1920, Height: 1080},
+	}
+	windows := []ax.Window{
+		{ID: 20, AppName: "Mail", Title: "Inbox", PID: 100, State: ax.StateNormal, ScreenID: 1},
+		{ID: 21, AppName: "Safari", Title: "GitHub", PID: 200, State: ax.StateNormal, ScreenID: 2},
+		{ID: 22, AppName: "Safari", Title: "Apple", PID: 200, State: ax.StateNormal, ScreenID: 3},
+		{ID: 23, AppName: "Safari", Title: "Docs", PID: 200, State: ax.StateNormal, ScreenID: 1},
+	}
+	svc := &ax.MockWindowService{Windows: windows, Screens: screens}
+
+	outcome, err := preset.Apply(context.Background(), svc, presets, "docked", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := outcome.Results[0].Affected; len(got) != 1 || got[0].Width != 1440 || got[0].Height != 900 {
+		t.Errorf("primary: expected Mail to fill the built-in screen, got %+v", got)
+	}
+	// every window off the primary screen is placed on its own screen
+	want := map[string][4]int{
+		"GitHub": {-2560, 0, 1280, 1440},
+		"Apple":  {1440, 0, 960, 1080},
+	}
+	affected := outcome.Results[1].Affected
+	if len(affected) != len(want) {
+		t.Fatalf("not-primary: expected %d windows, got %+v", len(want), affected)
+	}
+	for _, w := range affected {
+		if got := [4]int{w.X, w.Y, w.Width, w.Height}; got != want[w.Title] {
+			t.Errorf("%s: frame = %v, want %v", w.Title, got, want[w.Title])
+		}
+	}
+}
+
+func TestApply_SameTitleWindowsAreDistinct(t *testing.T) {
+	// 同じタイトルのウィンドウは ID で区別される
+	presets := []preset.Preset{{
+		Name: "terms",
+		Rules: []preset.Rule{
+			{App: "Terminal", Title: "zsh", Position: []preset.Expr{"0", "0"}},
+			{App: "Terminal", Position: []preset.Expr{"960", "0"}},
+		},
+	}}
+	windows := []ax.Window{
+		{ID: 10, AppName: "Terminal", Title: "zsh", PID: 200, State: ax.StateNormal},
+		{ID: 11, AppName: "Terminal", Title: "zsh", PID: 200, State: ax.StateNormal},
+	}
+	svc := &ax.MockWindowService{Windows: windows}
+	outcome, err := preset.Apply(context.Background(), svc, presets, "terms", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The first rule takes both windows; the second finds nothing left to apply.
+	if got := outcome.Results[0].Affected; len(got) != 2 || got[0].ID != 10 || got[1].ID != 11 {
+		t.Errorf("rule 0: expected windows 10 and 11, got %+v", got)
+	}
+	if r := outcome.Results[1]; !r.Skipped || r.Reason != "no_match" {
+		t.Errorf("rule 1: expected no_match skip, got %+v", r)
+	}
+}
+
+func TestPlan_DoesNotMove(t *testing.T) {
+	presets := []preset.Preset{{
+		Name: "plan",
+		Rules: []preset.Rule{
+			{App: "Code", Position: []preset.Expr{"0", "0"}},
+			{App: "Safari", Title: "Zoom", Size: []preset.Expr{"1280", "1080"}},
+			{App: "Safari", Position: []preset.Expr{"1280", "0"}},
+			{App: "Slack", Position: []preset.Expr{"0", "0"}},
+			{App: "Terminal", Position: []preset.Expr{"0", "0"}},
+		},
+	}}
+	svc := &ax.MockWindowService{
+		Windows: testWindows,
+		MoveErr: errors.New("must not be called"),
+	}
+	outcome, err := preset.Plan(context.Background(), svc, presets, "plan", []string{"terminal"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outcome.Results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(outcome.Results))
+	}
+
+	code := outcome.Results[0]
+	if code.Err != nil || len(code.Affected) != 1 {
+		t.Fatalf("rule 0: expected 1 planned window, got %+v", code)
+	}
+	// size is kept when the rule only sets position
+	if w := code.Affected[0]; w.X != 0 || w.Y != 0 || w.Width != 800 || w.Height != 600 {
+		t.Errorf("rule 0 target = (%d,%d %dx%d), want (0,0 800x600)", w.X, w.Y, w.Width, w.Height)
+	}
+	if b := code.Before[0]; b.AppName != "Code" || b.Width != 800 {
+		t.Errorf("rule 0 before = %+v", b)
+	}
+
+	// first match wins: the Zoom window is only planned by rule 1
+	if n := len(outcome.Results[1].Affected); n != 1 {
+		t.Errorf("rule 1: expected 1 window, got %d", n)
+	}
+	if n := len(outcome.Results[2].Affected); n != 2 {
+		t.Errorf("rule 2: expected 2 windows, got %d", n)
+	}
+	if r := outcome.Results[3]; r.Reason != "no_match" {
+		t.Errorf("rule 3: expected no_match, got %q", r.Reason)
+	}
+	if r := outcome.Results[4]; r.Reason != "ignored" {
+		t.Errorf("rule 4: expected ignored, got %q", r.Reason)
+	}
+}
+
+func TestPlan_NotFound(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: testWindows}
+	_, err := preset.Plan(context.Background(), svc, testPresets, "nope", nil, nil)
+	var notFound *preset.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected *preset.NotFoundError, got %T: %v", err, err)
+	}
+}
+
+func TestApply_GlobAndExclusions(t *testing.T) {
+	presets := []preset.Preset{{
+		Name: "browsers",
+		Rules: []preset.Rule{
+			{App: "saf*", ExcludeTitle: "zoom", TitleRegex: `^[A-Z]`, Position: []preset.Expr{"0", "0"}},
+		},
+	}}
+	svc := &ax.MockWindowService{Windows: testWindows}
+	outcome, err := preset.Apply(context.Background(), svc, presets, "browsers", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := outcome.Results[0].Affected
+	if len(got) != 2 || got[0].Title != "GitHub" || got[1].Title != "Apple" {
+		t.Errorf("expected GitHub and Apple, got %+v", got)
+	}
+}
+
+func TestApply_BundleID(t *testing.T) {
+	presets := []preset.Preset{{
+		Name: "editor",
+		Rules: []preset.Rule{
+			{BundleID: "com.microsoft.vscode", Position: []preset.Expr{"0", "0"}},
+		},
+	}}
+	svc := &ax.MockWindowService{Windows: testWindows}
+	outcome, err := preset.Apply(context.Background(), svc, presets, "editor", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := outcome.Results[0]
+	if r.AppFilter != "com.microsoft.vscode" {
+		t.Errorf("AppFilter = %q, want the bundle ID", r.AppFilter)
+	}
+	if len(r.Affected) != 1 || r.Affected[0].ID != 1 {
+		t.Errorf("expected only the Code window, got %+v", r.Affected)
+	}
+}
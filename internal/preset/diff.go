@@ -0,0 +1,92 @@
+package preset
+
+import (
+	"context"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// Drift is the difference between a window's current frame and the frame a rule places it in.
+type Drift struct {
+	Window ax.Window // current frame
+	Target window.Rect
+}
+
+// Delta returns the target frame minus the current frame, component-wise.
+func (d Drift) Delta() window.Rect {
+	return window.Rect{
+		X: d.Target.X - d.Window.X,
+		Y: d.Target.Y - d.Window.Y,
+		W: d.Target.W - d.Window.Width,
+		H: d.Target.H - d.Window.Height,
+	}
+}
+
+// Max returns the largest absolute component of Delta in pixels.
+func (d Drift) Max() int {
+	delta := d.Delta()
+	return max(abs(delta.X), abs(delta.Y), abs(delta.W), abs(delta.H))
+}
+
+// DiffResult holds the drift of the windows matched by a single rule.
+type DiffResult struct {
+	RuleIndex int
+	AppFilter string
+	Windows   []Drift
+	Skipped   bool
+	Reason    string
+	Err       error
+}
+
+// DiffOutcome holds the drift of every rule of a preset.
+type DiffOutcome struct {
+	PresetName string
+	Results    []DiffResult
+}
+
+// MaxDrift returns the largest drift of any matched window, or 0 when nothing matched.
+func (o *DiffOutcome) MaxDrift() int {
+	m := 0
+	for _, r := range o.Results {
+		for _, d := range r.Windows {
+			m = max(m, d.Max())
+		}
+	}
+	return m
+}
+
+// Diff compares the current window frames with the frames the named preset would apply.
+// Matching is the same as Apply; components a rule does not set never drift.
+func Diff(ctx context.Context, svc ax.WindowService, presets []Preset, name string, ignoreApps []string, aliases []window.ScreenAlias) (*DiffOutcome, error) {
+	plan, err := Plan(ctx, svc, presets, name, ignoreApps, aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := &DiffOutcome{PresetName: name}
+	for _, r := range plan.Results {
+		result := DiffResult{
+			RuleIndex: r.RuleIndex,
+			AppFilter: r.AppFilter,
+			Skipped:   r.Skipped,
+			Reason:    r.Reason,
+			Err:       r.Err,
+		}
+		for i, w := range r.Affected {
+			result.Windows = append(result.Windows, Drift{
+				Window: r.Before[i],
+				Target: w.Frame(),
+			})
+		}
+		outcome.Results = append(outcome.Results, result)
+	}
+	return outcome, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,65 @@
+package preset_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/preset"
+)
+
+func TestDiff(t *testing.T) {
+	presets := []preset.Preset{{
+		Name: "drift",
+		Rules: []preset.Rule{
+			{App: "Code", Position: []preset.Expr{"0", "0"}, Size: []preset.Expr{"800", "600"}},
+			{App: "Terminal", Position: []preset.Expr{"3", "-2"}},
+			{App: "Slack", Position: []preset.Expr{"0", "0"}},
+		},
+	}}
+	svc := &ax.MockWindowService{
+		Windows: testWindows,
+		MoveErr: errors.New("must not be called"),
+	}
+	outcome, err := preset.Diff(context.Background(), svc, presets, "drift", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outcome.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(outcome.Results))
+	}
+
+	code := outcome.Results[0].Windows
+	if len(code) != 1 || code[0].Max() != 0 {
+		t.Errorf("rule 0: expected no drift, got %+v", code)
+	}
+
+	// size is not set by the rule, so only the position drifts
+	term := outcome.Results[1].Windows
+	if len(term) != 1 {
+		t.Fatalf("rule 1: expected 1 window, got %d", len(term))
+	}
+	if d := term[0].Delta(); d.X != 3 || d.Y != -2 || d.W != 0 || d.H != 0 {
+		t.Errorf("rule 1 delta = %+v, want (3, -2, 0, 0)", d)
+	}
+	if m := term[0].Max(); m != 3 {
+		t.Errorf("rule 1 max drift = %d, want 3", m)
+	}
+
+	if r := outcome.Results[2]; !r.Skipped || r.Reason != "no_match" {
+		t.Errorf("rule 2: expected no_match skip, got %+v", r)
+	}
+	if m := outcome.MaxDrift(); m != 3 {
+		t.Errorf("MaxDrift() = %d, want 3", m)
+	}
+}
+
+func TestDiff_NotFound(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: testWindows}
+	_, err := preset.Diff(context.Background(), svc, testPresets, "nope", nil, nil)
+	var notFound *preset.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected *preset.NotFoundError, got %T: %v", err, err)
+	}
+}
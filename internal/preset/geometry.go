@@ -0,0 +1,464 @@
+package preset
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// Expr is a single component of a rule's position or size.
+//
+// Three forms are accepted:
+//   - an integer (e.g. 960): absolute pixels in global coordinates
+//   - a fraction (e.g. 0.5): a share of the target screen's width or height
+//   - a string expression (e.g. "50%", "screen.width - 400"): evaluated against the target screen
+//
+// Fractions and expressions are resolved at apply time. Relative positions are
+// offset from the target screen's origin, so "0%" is the screen edge while the
+// integer 0 is the global origin.
+type Expr string
+
+// Px returns an absolute pixel Expr.
+func Px(n int) Expr {
+	return Expr(strconv.Itoa(n))
+}
+
+// Absolute returns the pixel value when e is a plain integer.
+func (e Expr) Absolute() (int, bool) {
+	n, err := strconv.Atoi(string(e))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// fraction returns the value when e is a plain decimal number such as 0.5.
+func (e Expr) fraction() (float64, bool) {
+	if _, ok := e.Absolute(); ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(string(e), 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// Validate reports whether e is a well-formed geometry value.
+func (e Expr) Validate() error {
+	if _, ok := e.Absolute(); ok {
+		return nil
+	}
+	if f, ok := e.fraction(); ok {
+		if f < 0 || f > 1 {
+			return fmt.Errorf("fraction %s must be between 0 and 1", e)
+		}
+		return nil
+	}
+	_, err := parseExpr(string(e))
+	return err
+}
+
+// geomEnv holds the values an Expr is evaluated against.
+type geomEnv struct {
+	ref    float64 // length of the axis the value belongs to (base for % and fractions)
+	width  float64
+	height float64
+}
+
+// eval resolves e to pixels relative to the screen origin (or absolute for integers).
+func (e Expr) eval(env geomEnv) (int, error) {
+	if n, ok := e.Absolute(); ok {
+		return n, nil
+	}
+	if f, ok := e.fraction(); ok {
+		return int(math.Round(f * env.ref)), nil
+	}
+	n, err := parseExpr(string(e))
+	if err != nil {
+		return 0, err
+	}
+	v, err := n.eval(env)
+	if err != nil {
+		return 0, fmt.Errorf("expression %q: %w", string(e), err)
+	}
+	return int(math.Round(v)), nil
+}
+
+// MarshalYAML emits integers and fractions as YAML numbers and expressions as strings.
+func (e Expr) MarshalYAML() (any, error) {
+	if _, ok := e.Absolute(); ok {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: string(e)}, nil
+	}
+	if _, ok := e.fraction(); ok {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: string(e)}, nil
+	}
+	return string(e), nil
+}
+
+// UnmarshalYAML accepts an integer, a decimal number or a string expression.
+func (e *Expr) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: geometry value must be a number or string", node.Line)
+	}
+	switch node.ShortTag() {
+	case "!!int":
+		var n int
+		if err := node.Decode(&n); err != nil {
+			return err
+		}
+		*e = Px(n)
+	case "!!float":
+		var f float64
+		if err := node.Decode(&f); err != nil {
+			return err
+		}
+		*e = Expr(formatFraction(f))
+	default:
+		*e = Expr(strings.TrimSpace(node.Value))
+	}
+	return nil
+}
+
+// MarshalJSON emits integers and fractions as JSON numbers and expressions as strings.
+func (e Expr) MarshalJSON() ([]byte, error) {
+	if _, ok := e.Absolute(); ok {
+		return []byte(e), nil
+	}
+	if _, ok := e.fraction(); ok {
+		return []byte(e), nil
+	}
+	return json.Marshal(string(e))
+}
+
+// UnmarshalJSON accepts a JSON number or string.
+func (e *Expr) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*e = Expr(strings.TrimSpace(s))
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("geometry value must be a number or string: %w", err)
+	}
+	if i, err := n.Int64(); err == nil {
+		*e = Px(int(i))
+		return nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return err
+	}
+	*e = Expr(formatFraction(f))
+	return nil
+}
+
+// formatFraction renders f so that it is never mistaken for an integer (1 → "1.0").
+func formatFraction(f float64) string {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
+
+// target returns how r refers to its app in output: the app name, or else the bundle ID.
+func (r Rule) target() string {
+	if r.App != "" {
+		return r.App
+	}
+	return r.BundleID
+}
+
+// matchSpec returns the app and title filters of r.
+func (r Rule) matchSpec() window.MatchSpec {
+	return window.MatchSpec{
+		App:          r.App,
+		BundleID:     r.BundleID,
+		Title:        r.Title,
+		TitleRegex:   r.TitleRegex,
+		ExcludeApp:   r.ExcludeApp,
+		ExcludeTitle: r.ExcludeTitle,
+	}
+}
+
+// needsScreen reports whether applying r requires the target screen geometry.
+func (r Rule) needsScreen() bool {
+	return r.Snap != "" || r.isRelative()
+}
+
+// snapOptions returns the window snap parameters described by r.
+func (r Rule) snapOptions() window.SnapOptions {
+	return window.SnapOptions{Position: r.Snap, Gap: r.Gap, Margin: r.Margin}
+}
+
+// isRelative reports whether any position or size component of r depends on a screen.
+func (r Rule) isRelative() bool {
+	for _, e := range r.Position {
+		if _, ok := e.Absolute(); !ok {
+			return true
+		}
+	}
+	for _, e := range r.Size {
+		if _, ok := e.Absolute(); !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePosition resolves r.Position against s.
+// Relative components are offset by the screen origin. s may be nil when the rule is absolute.
+func resolvePosition(r Rule, s *ax.Screen) (int, int, error) {
+	return resolvePair(r.Position, s, true)
+}
+
+// resolveSize resolves r.Size against s. s may be nil when the rule is absolute.
+func resolveSize(r Rule, s *ax.Screen) (int, int, error) {
+	return resolvePair(r.Size, s, false)
+}
+
+func resolvePair(pair []Expr, s *ax.Screen, position bool) (int, int, error) {
+	var out [2]int
+	for i, e := range pair[:2] {
+		if n, ok := e.Absolute(); ok {
+			out[i] = n
+			continue
+		}
+		if s == nil {
+			return 0, 0, fmt.Errorf("value %q requires a target screen", string(e))
+		}
+		env := geomEnv{width: float64(s.Width), height: float64(s.Height)}
+		origin := s.X
+		env.ref = env.width
+		if i == 1 {
+			origin = s.Y
+			env.ref = env.height
+		}
+		v, err := e.eval(env)
+		if err != nil {
+			return 0, 0, err
+		}
+		if position {
+			v += origin
+		}
+		out[i] = v
+	}
+	return out[0], out[1], nil
+}
+
+// --- expression parser ---
+//
+// Grammar:
+//
+//	expr   = term { ("+" | "-") term }
+//	term   = unary { ("*" | "/") unary }
+//	unary  = "-" unary | atom
+//	atom   = number [ "%" ] | "screen.width" | "screen.height" | "(" expr ")"
+
+type exprNode interface {
+	eval(env geomEnv) (float64, error)
+}
+
+type numNode float64
+
+func (n numNode) eval(geomEnv) (float64, error) { return float64(n), nil }
+
+type percentNode float64
+
+func (n percentNode) eval(env geomEnv) (float64, error) { return float64(n) / 100 * env.ref, nil }
+
+type varNode string
+
+func (n varNode) eval(env geomEnv) (float64, error) {
+	if n == "screen.width" {
+		return env.width, nil
+	}
+	return env.height, nil
+}
+
+type negNode struct{ x exprNode }
+
+func (n negNode) eval(env geomEnv) (float64, error) {
+	v, err := n.x.eval(env)
+	return -v, err
+}
+
+type binNode struct {
+	op   byte
+	l, r exprNode
+}
+
+func (n binNode) eval(env geomEnv) (float64, error) {
+	l, err := n.l.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	r, err := n.r.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	default:
+		if r == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	}
+}
+
+type exprParser struct {
+	src string
+	pos int
+}
+
+// parseExpr parses a geometry expression such as "screen.width - 400" or "50% + 10".
+func parseExpr(src string) (exprNode, error) {
+	p := &exprParser{src: src}
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, fmt.Errorf("empty expression")
+	}
+	n, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos])
+	}
+	return n, nil
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid expression %q at column %d: %s", p.src, p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) || (p.src[p.pos] != '+' && p.src[p.pos] != '-') {
+			return left, nil
+		}
+		op := p.src[p.pos]
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binNode{op: op, l: left, r: right}
+	}
+}
+
+func (p *exprParser) parseTerm() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) || (p.src[p.pos] != '*' && p.src[p.pos] != '/') {
+			return left, nil
+		}
+		op := p.src[p.pos]
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binNode{op: op, l: left, r: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == '-' {
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negNode{x: x}, nil
+	}
+	return p.parseAtom()
+}
+
+func (p *exprParser) parseAtom() (exprNode, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of expression")
+	}
+	c := p.src[p.pos]
+	switch {
+	case c == '(':
+		p.pos++
+		n, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.src[p.pos] != ')' {
+			return nil, p.errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return n, nil
+	case c >= '0' && c <= '9' || c == '.':
+		start := p.pos
+		for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		text := p.src[start:p.pos]
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("invalid number %q", text)
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == '%' {
+			p.pos++
+			return percentNode(v), nil
+		}
+		return numNode(v), nil
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		start := p.pos
+		for p.pos < len(p.src) && isIdentChar(p.src[p.pos]) {
+			p.pos++
+		}
+		switch ident := p.src[start:p.pos]; ident {
+		case "screen.width", "screen.height":
+			return varNode(ident), nil
+		default:
+			p.pos = start
+			return nil, p.errorf("unknown identifier %q (want screen.width or screen.height)", ident)
+		}
+	default:
+		return nil, p.errorf("unexpected %q", c)
+	}
+}
+
+func isIdentChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_'
+}
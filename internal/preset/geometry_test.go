@@ -0,0 +1,205 @@
+package preset_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/preset"
+)
+
+var geometryScreens = []ax.Screen{
+	{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1920, Height: 1080, IsPrimary: true},
+	{ID: 2, Name: "External", X: 1920, Y: 0, Width: 2560, Height: 1440},
+}
+
+func TestExpr_YAMLRoundTrip(t *testing.T) {
+	src := "position: [0, \"50%\"]\nsize: [0.5, \"screen.width - 400\"]\n"
+	var r preset.Rule
+	if err := yaml.Unmarshal([]byte(src), &r); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want := []preset.Expr{"0", "50%", "0.5", "screen.width - 400"}
+	got := append(append([]preset.Expr{}, r.Position...), r.Size...)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("value[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	out, err := yaml.Marshal(preset.Rule{App: "Code", Position: r.Position, Size: r.Size})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(out), "position: [0, 50%]") {
+		t.Errorf("expected integer and expression in position, got:\n%s", out)
+	}
+	if !strings.Contains(string(out), "size: [0.5, screen.width - 400]") {
+		t.Errorf("expected fraction and expression in size, got:\n%s", out)
+	}
+}
+
+func TestExpr_YAMLFractionOne(t *testing.T) {
+	// 1.0 is a fraction (full screen), not the integer 1.
+	var r preset.Rule
+	if err := yaml.Unmarshal([]byte("size: [1.0, 1]\n"), &r); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Size[0].Absolute(); ok {
+		t.Errorf("1.0 should be a fraction, got absolute %q", r.Size[0])
+	}
+	if n, ok := r.Size[1].Absolute(); !ok || n != 1 {
+		t.Errorf("1 should be absolute 1, got %q", r.Size[1])
+	}
+}
+
+func TestExpr_JSON(t *testing.T) {
+	data, err := json.Marshal([]preset.Expr{"10", "0.25", "50%"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `[10,0.25,"50%"]` {
+		t.Errorf("got %s", data)
+	}
+	var back []preset.Expr
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back[0] != "10" || back[1] != "0.25" || back[2] != "50%" {
+		t.Errorf("round trip mismatch: %v", back)
+	}
+}
+
+func TestExpr_Validate(t *testing.T) {
+	tests := []struct {
+		expr    preset.Expr
+		wantErr string
+	}{
+		{"100", ""},
+		{"-1920", ""},
+		{"0.5", ""},
+		{"50%", ""},
+		{"screen.width - 400", ""},
+		{"(screen.height - 20) / 2", ""},
+		{"-25% + 10", ""},
+		{"1.5", "between 0 and 1"},
+		{"", "empty expression"},
+		{"screen.depth", "unknown identifier"},
+		{"50% +", "unexpected end"},
+		{"(50%", "missing closing parenthesis"},
+		{"50 $", "column 4"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.expr), func(t *testing.T) {
+			err := tt.expr.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePresets_InvalidExpr(t *testing.T) {
+	presets := []preset.Preset{{
+		Name: "broken",
+		Rules: []preset.Rule{
+			{App: "Code", Position: []preset.Expr{"0", "screen.top"}},
+		},
+	}}
+	errs := preset.ValidatePresets(presets)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if errs[0].Field != "rules[0].position[1]" {
+		t.Errorf("field = %q, want rules[0].position[1]", errs[0].Field)
+	}
+}
+
+func TestApply_RelativeGeometry(t *testing.T) {
+	presets := []preset.Preset{{
+		Name: "halves",
+		Rules: []preset.Rule{
+			{App: "Code", Position: []preset.Expr{"0%", "0%"}, Size: []preset.Expr{"0.5", "100%"}},
+			{App: "Terminal", Screen: "External", Position: []preset.Expr{"screen.width - 800", "10"}, Size: []preset.Expr{"800", "50%"}},
+		},
+	}}
+	windows := []ax.Window{
+		{ID: 1, AppName: "Code", Title: "main.go", PID: 1, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
+		{ID: 2, AppName: "Terminal", Title: "zsh", PID: 2, State: ax.StateNormal, ScreenID: 2, ScreenName: "External"},
+	}
+	svc := &ax.MockWindowService{Windows: windows, Screens: geometryScreens}
+
+	outcome, err := preset.Apply(context.Background(), svc, presets, "halves", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	code := outcome.Results[0].Affected[0]
+	if code.X != 0 || code.Y != 0 || code.Width != 960 || code.Height != 1080 {
+		t.Errorf("Code frame = (%d,%d %dx%d), want (0,0 960x1080)", code.X, code.Y, code.Width, code.Height)
+	}
+	// Integer 10 stays absolute; the expression is offset by the external screen origin.
+	term := outcome.Results[1].Affected[0]
+	if term.X != 1920+2560-800 || term.Y != 10 || term.Width != 800 || term.Height != 720 {
+		t.Errorf("Terminal frame = (%d,%d %dx%d), want (3680,10 800x720)", term.X, term.Y, term.Width, term.Height)
+	}
+}
+
+func TestApply_RelativeGeometryUnknownScreen(t *testing.T) {
+	presets := []preset.Preset{{
+		Name:  "half",
+		Rules: []preset.Rule{{App: "Code", Size: []preset.Expr{"50%", "100%"}}},
+	}}
+	windows := []ax.Window{
+		{ID: 3, AppName: "Code", Title: "main.go", PID: 1, State: ax.StateNormal, ScreenID: 99},
+	}
+	svc := &ax.MockWindowService{Windows: windows, Screens: geometryScreens}
+
+	outcome, err := preset.Apply(context.Background(), svc, presets, "half", nil, nil)
+	if err == nil {
+		t.Fatal("expected error for window on unknown screen, got nil")
+	}
+	if outcome.Results[0].Err == nil {
+		t.Error("expected rule error to be recorded")
+	}
+}
+
+func TestValidatePresets_ZeroFractionSize(t *testing.T) {
+	presets := []preset.Preset{{
+		Name:  "flat",
+		Rules: []preset.Rule{{App: "Code", Size: []preset.Expr{"0.5", "0.0"}}},
+	}}
+	errs := preset.ValidatePresets(presets)
+	if len(errs) != 1 || errs[0].Field != "rules[0].size[1]" {
+		t.Fatalf("expected one error for rules[0].size[1], got %v", errs)
+	}
+}
+
+func TestApply_RelativeSizeNotPositive(t *testing.T) {
+	presets := []preset.Preset{{
+		Name:  "narrow",
+		Rules: []preset.Rule{{App: "Code", Size: []preset.Expr{"screen.width - 5000", "100%"}}},
+	}}
+	windows := []ax.Window{
+		{ID: 4, AppName: "Code", Title: "main.go", PID: 1, State: ax.StateNormal, Width: 800, Height: 600, ScreenID: 1},
+	}
+	svc := &ax.MockWindowService{Windows: windows, Screens: geometryScreens}
+
+	outcome, err := preset.Apply(context.Background(), svc, presets, "narrow", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "rule[0]: size resolves to -3080x1080") {
+		t.Fatalf("error = %v, want a rule error for the non-positive width", err)
+	}
+	if len(outcome.Results[0].Affected) != 0 {
+		t.Errorf("expected no window to be resized, got %+v", outcome.Results[0].Affected)
+	}
+}
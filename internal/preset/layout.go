@@ -0,0 +1,87 @@
+package preset
+
+import (
+	"fmt"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// RuleFrame is the frame a rule places its windows in, worked out from the screens alone.
+// Rules whose frame depends on the window they move (a missing position or size, or
+// snap "center") have no frame and a Reason instead.
+type RuleFrame struct {
+	RuleIndex int
+	AppFilter string
+	Title     string // title filter of the rule, if any
+	Frame     window.Rect
+	Reason    string // "depends_on_window" or "no_screen" when Frame is unset
+}
+
+// AxScreens returns the screens stored with the preset as ax.Screen values.
+// IDs are assigned in the order the screens are listed, starting at 1.
+func (p Preset) AxScreens() []ax.Screen {
+	screens := make([]ax.Screen, len(p.Screens))
+	for i, s := range p.Screens {
+		screens[i] = ax.Screen{
+			ID:   uint32(i + 1),
+			Name: s.Name,
+			X:    s.X, Y: s.Y, Width: s.Width, Height: s.Height,
+			IsPrimary: s.Primary,
+		}
+		if s.Visible != nil {
+			screens[i].VisibleFrame = *s.Visible
+		}
+	}
+	return screens
+}
+
+// Layout computes the frame of every rule of p on screens without looking at any
+// window. Rules without a screen are placed on the primary screen (or the first
+// screen when none is primary); aliases are the screen aliases rule screens may refer to.
+func Layout(p Preset, screens []ax.Screen, aliases []window.ScreenAlias) ([]RuleFrame, error) {
+	frames := make([]RuleFrame, 0, len(p.Rules))
+	for i, rule := range p.Rules {
+		f := RuleFrame{RuleIndex: i, AppFilter: rule.target(), Title: rule.Title}
+
+		scr, ok := layoutScreen(screens, window.ResolveScreenRef(screens, aliases, rule.Screen))
+		if !ok && rule.needsScreen() {
+			f.Reason = "no_screen"
+			frames = append(frames, f)
+			continue
+		}
+		if rule.Snap == "center" || (rule.Snap == "" && (len(rule.Position) != 2 || len(rule.Size) != 2)) {
+			f.Reason = "depends_on_window"
+			frames = append(frames, f)
+			continue
+		}
+
+		if ok && rule.scalesOn(screens, p.Scale) {
+			rule = rule.scaleTo(scr, p.Scale)
+		}
+		scr = window.ScreenArea(scr, rule.Area)
+		r, _, _, err := targetFrame(rule, &scr, ax.Window{})
+		if err != nil {
+			return nil, fmt.Errorf("rule[%d]: %w", i, err)
+		}
+		f.Frame = r
+		frames = append(frames, f)
+	}
+	return frames, nil
+}
+
+// layoutScreen returns the screen named by ref, or the primary screen when ref is empty.
+func layoutScreen(screens []ax.Screen, ref string) (ax.Screen, bool) {
+	if ref != "" {
+		return window.FindScreen(screens, ref)
+	}
+	for _, s := range screens {
+		if s.IsPrimary {
+			return s, true
+		}
+	}
+	if ordered := window.OrderScreens(screens); len(ordered) > 0 {
+		return ordered[0], true
+	}
+	return ax.Screen{}, false
+}
@@ -0,0 +1,68 @@
+package preset_test
+
+import (
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/preset"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+func TestLayout(t *testing.T) {
+	p := preset.Preset{
+		Name: "coding",
+		Rules: []preset.Rule{
+			{App: "Code", Snap: "left-half"},
+			{App: "Terminal", Screen: "External", Position: []preset.Expr{"0%", "0%"}, Size: []preset.Expr{"50%", "100%"}},
+			{App: "Slack", Position: []preset.Expr{"100", "100"}, Size: []preset.Expr{"800", "600"}},
+			{App: "Notes", Position: []preset.Expr{"0", "0"}},
+			{App: "Safari", Screen: "Missing", Snap: "maximize"},
+		},
+	}
+	frames, err := preset.Layout(p, geometryScreens, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []struct {
+		frame  window.Rect
+		reason string
+	}{
+		{frame: window.Rect{X: 0, Y: 0, W: 960, H: 1080}},
+		{frame: window.Rect{X: 1920, Y: 0, W: 1280, H: 1440}},
+		{frame: window.Rect{X: 100, Y: 100, W: 800, H: 600}},
+		{reason: "depends_on_window"},
+		{reason: "no_screen"},
+	}
+	if len(frames) != len(want) {
+		t.Fatalf("got %d frames, want %d", len(frames), len(want))
+	}
+	for i, w := range want {
+		if frames[i].Frame != w.frame || frames[i].Reason != w.reason {
+			t.Errorf("rule[%d] = %+v / %q, want %+v / %q", i, frames[i].Frame, frames[i].Reason, w.frame, w.reason)
+		}
+	}
+}
+
+func TestLayout_StoredScreens(t *testing.T) {
+	p := preset.Preset{
+		Name: "laptop",
+		Screens: []preset.Screen{
+			{Name: "External", X: 1440, Y: 0, Width: 1920, Height: 1080},
+			{Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900, Primary: true},
+		},
+		Rules: []preset.Rule{
+			{App: "Code", Snap: "maximize"},
+			{App: "Terminal", Screen: "#2", Snap: "right-half"},
+		},
+	}
+	frames, err := preset.Layout(p, p.AxScreens(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// rules without a screen go to the primary screen; "#2" is the second from the left
+	if got, want := frames[0].Frame, (window.Rect{X: 0, Y: 0, W: 1440, H: 900}); got != want {
+		t.Errorf("rule[0] = %+v, want %+v", got, want)
+	}
+	if got, want := frames[1].Frame, (window.Rect{X: 2400, Y: 0, W: 960, H: 1080}); got != want {
+		t.Errorf("rule[1] = %+v, want %+v", got, want)
+	}
+}
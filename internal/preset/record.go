@@ -3,6 +3,7 @@ package preset
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/peacock0803sz/mado/internal/ax"
 	"github.com/peacock0803sz/mado/internal/window"
@@ -10,12 +11,17 @@ import (
 
 // RecordOptions holds optional parameters for Record.
 type RecordOptions struct {
-	Screen string // filter by screen name or ID (empty = all screens)
+	Screen string // filter by screen name, ID or alias (empty = all screens)
+	// ScreenAliases are the aliases Screen may refer to.
+	ScreenAliases []window.ScreenAlias
 }
 
 // Record captures the current window layout and returns it as a Preset.
 // Only windows with StateNormal are included. When multiple windows belong to
 // the same application, the title field is populated for disambiguation.
+// Each rule keeps the screen its window is on as Reference, so that the preset
+// can be scaled to screens of other sizes, and the connected screens are stored as
+// Screens, so that the layout can be drawn without them.
 func Record(ctx context.Context, svc ax.WindowService, name string, opts RecordOptions) (*Preset, error) {
 	if !namePattern.MatchString(name) {
 		return nil, fmt.Errorf("invalid preset name %q: must match %s", name, namePattern.String())
@@ -25,6 +31,11 @@ func Record(ctx context.Context, svc ax.WindowService, name string, opts RecordO
 	if err != nil {
 		return nil, err
 	}
+	screens, err := svc.ListScreens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts.Screen = window.ResolveScreenRef(screens, opts.ScreenAliases, opts.Screen)
 
 	// Count normal windows per application name to decide title inclusion.
 	appCount := make(map[string]int)
@@ -44,12 +55,16 @@ func Record(ctx context.Context, svc ax.WindowService, name string, opts RecordO
 	for _, w := range normal {
 		r := Rule{
 			App:      w.AppName,
-			Position: []int{w.X, w.Y},
-			Size:     []int{w.Width, w.Height},
+			Position: []Expr{Px(w.X), Px(w.Y)},
+			Size:     []Expr{Px(w.Width), Px(w.Height)},
 		}
 		if appCount[w.AppName] > 1 {
 			r.Title = w.Title
 		}
+		if s, ok := window.ScreenOf(screens, w); ok {
+			ref := s.Bounds()
+			r.Reference = &ref
+		}
 		// Capture desktop number; skip desktop=-1 (unknown) so the rule matches any desktop.
 		if w.Desktop >= 0 {
 			d := w.Desktop
@@ -59,7 +74,29 @@ func Record(ctx context.Context, svc ax.WindowService, name string, opts RecordO
 	}
 
 	return &Preset{
-		Name:  name,
-		Rules: rules,
+		Name:    name,
+		Rules:   rules,
+		Screens: recordScreens(screens),
 	}, nil
 }
+
+// recordScreens returns screens as stored with a preset, ordered left-to-right, then
+// top-to-bottom. The visible area is kept only where it differs from the bounds.
+func recordScreens(screens []ax.Screen) []Screen {
+	ordered := window.OrderScreens(screens)
+	result := make([]Screen, len(ordered))
+	for i, s := range ordered {
+		result[i] = Screen{
+			Name: s.Name,
+			X:    s.X, Y: s.Y, Width: s.Width, Height: s.Height,
+			Primary: s.IsPrimary,
+		}
+		if result[i].Name == "" {
+			result[i].Name = strconv.FormatUint(uint64(s.ID), 10)
+		}
+		if v := s.Visible(); v != s.Bounds() {
+			result[i].Visible = &v
+		}
+	}
+	return result
+}
@@ -2,6 +2,7 @@ package preset
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/peacock0803sz/mado/internal/ax"
@@ -10,8 +11,8 @@ import (
 func TestRecord_TwoWindows(t *testing.T) {
 	svc := &ax.MockWindowService{
 		Windows: []ax.Window{
-			{AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
-			{AppName: "Terminal", Title: "zsh", PID: 2, X: 960, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
+			{ID: 1, AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
+			{ID: 2, AppName: "Terminal", Title: "zsh", PID: 2, X: 960, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
 		},
 	}
 
@@ -38,10 +39,10 @@ func TestRecord_TwoWindows(t *testing.T) {
 	if r0.App != "Code" {
 		t.Errorf("rules[0].App = %q, want %q", r0.App, "Code")
 	}
-	if r0.Position[0] != 0 || r0.Position[1] != 0 {
+	if r0.Position[0] != "0" || r0.Position[1] != "0" {
 		t.Errorf("rules[0].Position = %v, want [0, 0]", r0.Position)
 	}
-	if r0.Size[0] != 960 || r0.Size[1] != 1080 {
+	if r0.Size[0] != "960" || r0.Size[1] != "1080" {
 		t.Errorf("rules[0].Size = %v, want [960, 1080]", r0.Size)
 	}
 }
@@ -49,8 +50,8 @@ func TestRecord_TwoWindows(t *testing.T) {
 func TestRecord_SameAppMultipleWindows(t *testing.T) {
 	svc := &ax.MockWindowService{
 		Windows: []ax.Window{
-			{AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
-			{AppName: "Code", Title: "test.go", PID: 1, X: 960, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
+			{ID: 3, AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
+			{ID: 4, AppName: "Code", Title: "test.go", PID: 1, X: 960, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
 		},
 	}
 
@@ -75,10 +76,10 @@ func TestRecord_SameAppMultipleWindows(t *testing.T) {
 func TestRecord_FiltersNonNormal(t *testing.T) {
 	svc := &ax.MockWindowService{
 		Windows: []ax.Window{
-			{AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
-			{AppName: "Finder", Title: "Downloads", PID: 3, State: ax.StateMinimized},
-			{AppName: "Safari", Title: "Google", PID: 4, State: ax.StateFullscreen},
-			{AppName: "Mail", Title: "Inbox", PID: 5, State: ax.StateHidden},
+			{ID: 5, AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal},
+			{ID: 6, AppName: "Finder", Title: "Downloads", PID: 3, State: ax.StateMinimized},
+			{ID: 7, AppName: "Safari", Title: "Google", PID: 4, State: ax.StateFullscreen},
+			{ID: 8, AppName: "Mail", Title: "Inbox", PID: 5, State: ax.StateHidden},
 		},
 	}
 
@@ -120,9 +121,9 @@ func TestRecord_InvalidName(t *testing.T) {
 func TestRecord_ScreenFilter(t *testing.T) {
 	svc := &ax.MockWindowService{
 		Windows: []ax.Window{
-			{AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in Retina Display"},
-			{AppName: "Terminal", Title: "zsh", PID: 2, X: 960, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal, ScreenID: 2, ScreenName: "DELL U2720Q"},
-			{AppName: "Safari", Title: "Google", PID: 3, X: 0, Y: 0, Width: 1920, Height: 1080, State: ax.StateNormal, ScreenID: 2, ScreenName: "DELL U2720Q"},
+			{ID: 9, AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in Retina Display"},
+			{ID: 10, AppName: "Terminal", Title: "zsh", PID: 2, X: 960, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal, ScreenID: 2, ScreenName: "DELL U2720Q"},
+			{ID: 11, AppName: "Safari", Title: "Google", PID: 3, X: 0, Y: 0, Width: 1920, Height: 1080, State: ax.StateNormal, ScreenID: 2, ScreenName: "DELL U2720Q"},
 		},
 	}
 
@@ -143,11 +144,93 @@ func TestRecord_ScreenFilter(t *testing.T) {
 	}
 }
 
+func TestRecord_Reference(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: []ax.Window{
+			{ID: 1, AppName: "Code", Title: "main.go", PID: 1, X: 1440, Y: 0, Width: 1280, Height: 1440, State: ax.StateNormal, ScreenID: 2},
+			{ID: 2, AppName: "Terminal", Title: "zsh", PID: 2, X: 0, Y: 0, Width: 720, Height: 900, State: ax.StateNormal, ScreenID: 99},
+		},
+		Screens: []ax.Screen{
+			{ID: 1, Name: "Built-in", Width: 1440, Height: 900, IsPrimary: true},
+			{ID: 2, Name: "External", X: 1440, Width: 2560, Height: 1440},
+		},
+	}
+
+	p, err := Record(context.Background(), svc, "coding", RecordOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ax.Rect{X: 1440, Y: 0, W: 2560, H: 1440}
+	if ref := p.Rules[0].Reference; ref == nil || *ref != want {
+		t.Errorf("rules[0].Reference = %v, want %+v", ref, want)
+	}
+	// a window on an unknown screen has no reference
+	if ref := p.Rules[1].Reference; ref != nil {
+		t.Errorf("rules[1].Reference = %+v, want nil", *ref)
+	}
+}
+
+func TestRecord_Screens(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: []ax.Window{
+			{ID: 1, AppName: "Code", Title: "main.go", PID: 1, Width: 720, Height: 900, State: ax.StateNormal, ScreenID: 1},
+		},
+		Screens: []ax.Screen{
+			{ID: 2, Name: "External", X: 1440, Width: 2560, Height: 1440},
+			{ID: 1, Name: "Built-in", Width: 1440, Height: 900, IsPrimary: true,
+				VisibleFrame: ax.Rect{Y: 25, W: 1440, H: 875}},
+		},
+	}
+
+	p, err := Record(context.Background(), svc, "coding", RecordOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	visible := ax.Rect{Y: 25, W: 1440, H: 875}
+	want := []Screen{
+		{Name: "Built-in", Width: 1440, Height: 900, Primary: true, Visible: &visible},
+		{Name: "External", X: 1440, Width: 2560, Height: 1440},
+	}
+	if !reflect.DeepEqual(p.Screens, want) {
+		t.Errorf("screens = %+v, want %+v", p.Screens, want)
+	}
+}
+
+func TestRecord_ApplyRoundTrip(t *testing.T) {
+	screens := []ax.Screen{
+		{ID: 1, Name: "Built-in", Width: 1440, Height: 900, IsPrimary: true},
+		{ID: 2, Name: "External", X: 1440, Width: 2560, Height: 1440},
+	}
+	svc := &ax.MockWindowService{
+		Windows: []ax.Window{
+			{ID: 1, AppName: "Code", Title: "main.go", PID: 1, X: 1440, Y: 0, Width: 1280, Height: 1440, State: ax.StateNormal, ScreenID: 2},
+		},
+		Screens: screens,
+	}
+	p, err := Record(context.Background(), svc, "coding", RecordOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the window has since moved to the other screen; applying puts it back
+	svc.Windows = []ax.Window{
+		{ID: 1, AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 700, Height: 500, State: ax.StateNormal, ScreenID: 1},
+	}
+	outcome, err := Apply(context.Background(), svc, []Preset{*p}, "coding", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w := outcome.Results[0].Affected[0]
+	if w.X != 1440 || w.Y != 0 || w.Width != 1280 || w.Height != 1440 {
+		t.Errorf("frame = (%d,%d %dx%d), want (1440,0 1280x1440)", w.X, w.Y, w.Width, w.Height)
+	}
+}
+
 func TestRecord_ScreenFilterByID(t *testing.T) {
 	svc := &ax.MockWindowService{
 		Windows: []ax.Window{
-			{AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
-			{AppName: "Terminal", Title: "zsh", PID: 2, X: 960, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal, ScreenID: 2, ScreenName: "External"},
+			{ID: 12, AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
+			{ID: 13, AppName: "Terminal", Title: "zsh", PID: 2, X: 960, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal, ScreenID: 2, ScreenName: "External"},
 		},
 	}
 
@@ -168,7 +251,7 @@ func TestRecord_ScreenFilterByID(t *testing.T) {
 func TestRecord_ScreenFilterNoMatch(t *testing.T) {
 	svc := &ax.MockWindowService{
 		Windows: []ax.Window{
-			{AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
+			{ID: 14, AppName: "Code", Title: "main.go", PID: 1, X: 0, Y: 0, Width: 960, Height: 1080, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
 		},
 	}
 
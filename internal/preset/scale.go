@@ -0,0 +1,82 @@
+package preset
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+)
+
+// validateScale returns an error when policy is not a known scale policy.
+// An empty policy means ScaleProportional.
+func validateScale(policy string) error {
+	switch policy {
+	case "", ScaleNone, ScaleProportional, ScaleFit:
+		return nil
+	}
+	return fmt.Errorf("invalid scale %q (want %s, %s or %s)", policy, ScaleNone, ScaleProportional, ScaleFit)
+}
+
+// scales reports whether the absolute geometry of r is scaled under policy.
+func (r Rule) scales(policy string) bool {
+	return r.Reference != nil && r.Reference.W > 0 && r.Reference.H > 0 &&
+		policy != ScaleNone && (len(r.Position) == 2 || len(r.Size) == 2)
+}
+
+// scalesOn reports whether the absolute geometry of r is scaled under policy with
+// screens connected. It is not when one of them has exactly the bounds of the
+// reference screen: the recorded geometry then still holds, whichever screen the
+// window is on now.
+func (r Rule) scalesOn(screens []ax.Screen, policy string) bool {
+	if !r.scales(policy) {
+		return false
+	}
+	for _, s := range screens {
+		if s.Bounds() == *r.Reference {
+			return false
+		}
+	}
+	return true
+}
+
+// scaleTo returns r with its absolute position and size mapped from r.Reference onto
+// s. Relative components already follow the screen and are left alone, as is r when
+// s has the bounds of the reference screen.
+func (r Rule) scaleTo(s ax.Screen, policy string) Rule {
+	ref := *r.Reference
+	if s.Bounds() == ref {
+		return r
+	}
+	sx := float64(s.Width) / float64(ref.W)
+	sy := float64(s.Height) / float64(ref.H)
+	offX, offY := 0.0, 0.0
+	if policy == ScaleFit {
+		sx = math.Min(sx, sy)
+		sy = sx
+		offX = (float64(s.Width) - float64(ref.W)*sx) / 2
+		offY = (float64(s.Height) - float64(ref.H)*sy) / 2
+	}
+
+	scale := func(pair []Expr, f [2]func(int) int) []Expr {
+		if len(pair) != 2 {
+			return pair
+		}
+		out := make([]Expr, 2)
+		for i, e := range pair {
+			out[i] = e
+			if n, ok := e.Absolute(); ok {
+				out[i] = Px(f[i](n))
+			}
+		}
+		return out
+	}
+	r.Position = scale(r.Position, [2]func(int) int{
+		func(x int) int { return s.X + int(math.Round(offX+float64(x-ref.X)*sx)) },
+		func(y int) int { return s.Y + int(math.Round(offY+float64(y-ref.Y)*sy)) },
+	})
+	r.Size = scale(r.Size, [2]func(int) int{
+		func(w int) int { return int(math.Round(float64(w) * sx)) },
+		func(h int) int { return int(math.Round(float64(h) * sy)) },
+	})
+	return r
+}
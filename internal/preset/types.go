@@ -1,21 +1,70 @@
 // Package preset implements YAML preset management for window layouts.
 package preset
 
+import "github.com/peacock0803sz/mado/internal/ax"
+
 // Preset is a named window layout definition loaded from the config file.
 type Preset struct {
 	Name        string `json:"name"        yaml:"name"`
 	Description string `json:"description" yaml:"description,omitempty"`
 	Rules       []Rule `json:"rules"       yaml:"rules"`
+	// Screens optionally records the screen arrangement the preset was designed for, so
+	// that its layout can be drawn without querying the connected displays.
+	Screens []Screen `json:"screens,omitempty" yaml:"screens,omitempty"`
+	// Scale is how absolute geometry of rules with a Reference screen is adapted to a
+	// screen of a different size: ScaleProportional (the default), ScaleFit or ScaleNone.
+	Scale string `json:"scale,omitempty" yaml:"scale,omitempty"`
+}
+
+// Scale policies of a preset.
+const (
+	ScaleNone         = "none"         // use absolute geometry as recorded
+	ScaleProportional = "proportional" // stretch x and y to the screen independently
+	ScaleFit          = "fit"          // keep the aspect ratio and center on the screen
+)
+
+// Screen is a display stored with a preset, in the global coordinates of ax.Screen.
+type Screen struct {
+	Name    string `json:"name"              yaml:"name"`
+	X       int    `json:"x"                 yaml:"x"`
+	Y       int    `json:"y"                 yaml:"y"`
+	Width   int    `json:"width"             yaml:"width"`
+	Height  int    `json:"height"            yaml:"height"`
+	Primary bool   `json:"primary,omitempty" yaml:"primary,omitempty"`
+	// Visible is the usable area without the menu bar and Dock; nil = the full bounds.
+	Visible *ax.Rect `json:"visible,omitempty" yaml:"visible,omitempty"`
 }
 
 // Rule is a single window operation instruction within a preset.
 type Rule struct {
-	App    string `json:"app"                yaml:"app"`
-	Title  string `json:"title,omitempty"     yaml:"title,omitempty"`
-	Screen string `json:"screen,omitempty"    yaml:"screen,omitempty"`
+	// App matches the app name case-insensitively, exactly or as a glob ("Google Chrome*").
+	// BundleID matches the bundle identifier instead, which does not depend on the locale
+	// or version of the app. At least one of them is required.
+	App      string `json:"app,omitempty"       yaml:"app,omitempty"`
+	BundleID string `json:"bundle_id,omitempty" yaml:"bundle_id,omitempty"`
+	Title    string `json:"title,omitempty"     yaml:"title,omitempty"`
+	Screen   string `json:"screen,omitempty"    yaml:"screen,omitempty"`
+	// TitleRegex, ExcludeApp and ExcludeTitle further narrow the matched windows
+	// (see window.MatchSpec).
+	TitleRegex   string `json:"title_regex,omitempty"   yaml:"title_regex,omitempty"`
+	ExcludeApp   string `json:"exclude_app,omitempty"   yaml:"exclude_app,omitempty"`
+	ExcludeTitle string `json:"exclude_title,omitempty" yaml:"exclude_title,omitempty"`
 	// Desktop scopes this rule to a specific desktop (1-based Mission Control order).
 	// nil = no filter (matches all desktops); *Desktop=0 = match only all-desktops windows.
-	Desktop  *int  `json:"desktop,omitempty"   yaml:"desktop,omitempty"`
-	Position []int `json:"position,omitempty"  yaml:"position,omitempty,flow"`
-	Size     []int `json:"size,omitempty"      yaml:"size,omitempty,flow"`
+	Desktop *int `json:"desktop,omitempty"   yaml:"desktop,omitempty"`
+	// Position and Size accept absolute pixels or screen-relative values (see Expr).
+	Position []Expr `json:"position,omitempty"  yaml:"position,omitempty,flow"`
+	Size     []Expr `json:"size,omitempty"      yaml:"size,omitempty,flow"`
+	// Snap places the window at a named snap position (e.g. "left-half") on its screen
+	// instead of Position/Size. Gap and Margin tune the snapped frame in pixels.
+	Snap   string `json:"snap,omitempty"      yaml:"snap,omitempty"`
+	Gap    int    `json:"gap,omitempty"       yaml:"gap,omitempty"`
+	Margin int    `json:"margin,omitempty"    yaml:"margin,omitempty"`
+	// Area is the part of the target screen that snap positions and relative geometry
+	// refer to: "frame" (the full display, the default) or "visible" (without the
+	// menu bar and Dock).
+	Area string `json:"area,omitempty" yaml:"area,omitempty"`
+	// Reference is the screen the absolute position and size were recorded on. When the
+	// target screen has a different size they are scaled by the preset's Scale policy.
+	Reference *ax.Rect `json:"reference,omitempty" yaml:"reference,omitempty,flow"`
 }
@@ -1,8 +1,11 @@
 package preset
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
+
+	"github.com/peacock0803sz/mado/internal/window"
 )
 
 // namePattern validates preset names: starts with alphanumeric, then alphanumeric/hyphen/underscore.
@@ -56,6 +59,14 @@ func ValidatePresets(presets []Preset) []ValidationError {
 			name = prefix
 		}
 
+		if err := validateScale(p.Scale); err != nil {
+			errs = append(errs, ValidationError{
+				Preset:  name,
+				Field:   "scale",
+				Message: err.Error(),
+			})
+		}
+
 		// Validate rules
 		if len(p.Rules) == 0 {
 			errs = append(errs, ValidationError{
@@ -68,11 +79,20 @@ func ValidatePresets(presets []Preset) []ValidationError {
 		for j, r := range p.Rules {
 			ruleField := fmt.Sprintf("rules[%d]", j)
 
-			if r.App == "" {
+			if r.App == "" && r.BundleID == "" {
 				errs = append(errs, ValidationError{
 					Preset:  name,
 					Field:   ruleField,
-					Message: "app is required",
+					Message: "app or bundle_id is required",
+				})
+			}
+
+			var patErr *window.PatternError
+			if _, err := window.CompileMatch(r.matchSpec()); errors.As(err, &patErr) {
+				errs = append(errs, ValidationError{
+					Preset:  name,
+					Field:   ruleField + "." + patErr.Field,
+					Message: patErr.Error(),
 				})
 			}
 
@@ -85,25 +105,70 @@ func ValidatePresets(presets []Preset) []ValidationError {
 				})
 			}
 
-			hasPosition := len(r.Position) > 0
-			hasSize := len(r.Size) > 0
+			if window.IsScreenIndex(r.Screen) {
+				if _, ok := window.ParseScreenIndex(r.Screen); !ok {
+					errs = append(errs, ValidationError{
+						Preset:  name,
+						Field:   ruleField + ".screen",
+						Message: fmt.Sprintf("invalid screen index %q: must be #N with N >= 1", r.Screen),
+					})
+				}
+			}
 
-			if !hasPosition && !hasSize {
+			if err := window.ValidateArea(r.Area); err != nil {
 				errs = append(errs, ValidationError{
 					Preset:  name,
-					Field:   ruleField,
-					Message: "position or size is required",
+					Field:   ruleField + ".area",
+					Message: err.Error(),
+				})
+			}
+
+			if r.Reference != nil && (r.Reference.W <= 0 || r.Reference.H <= 0) {
+				errs = append(errs, ValidationError{
+					Preset:  name,
+					Field:   ruleField + ".reference",
+					Message: "width and height must be positive",
 				})
 			}
 
-			if hasPosition && len(r.Position) != 2 {
+			hasPosition := len(r.Position) > 0
+			hasSize := len(r.Size) > 0
+
+			if r.Snap != "" {
+				if hasPosition || hasSize {
+					errs = append(errs, ValidationError{
+						Preset:  name,
+						Field:   ruleField + ".snap",
+						Message: "snap cannot be combined with position or size",
+					})
+				}
+				if err := window.ValidateSnap(r.snapOptions()); err != nil {
+					errs = append(errs, ValidationError{
+						Preset:  name,
+						Field:   ruleField + ".snap",
+						Message: err.Error(),
+					})
+				}
+			} else if !hasPosition && !hasSize {
 				errs = append(errs, ValidationError{
 					Preset:  name,
-					Field:   ruleField + ".position",
-					Message: "position must have exactly 2 values [x, y]",
+					Field:   ruleField,
+					Message: "position, size or snap is required",
 				})
 			}
 
+			if hasPosition {
+				if len(r.Position) != 2 {
+					errs = append(errs, ValidationError{
+						Preset:  name,
+						Field:   ruleField + ".position",
+						Message: "position must have exactly 2 values [x, y]",
+					})
+				} else {
+					errs = append(errs, validateExprs(name, ruleField+".position", r.Position)...)
+				}
+			}
+
 			if hasSize {
 				if len(r.Size) != 2 {
 					errs = append(errs, ValidationError{
@@ -112,23 +177,53 @@ func ValidatePresets(presets []Preset) []ValidationError {
 						Message: "size must have exactly 2 values [width, height]",
 					})
 				} else {
-					if r.Size[0] <= 0 {
+					errs = append(errs, validateExprs(name, ruleField+".size", r.Size)...)
+					// Relative sizes depend on the screen; only absolute values and
+					// fractions can be checked here.
+					if w, ok := r.Size[0].Absolute(); ok && w <= 0 {
 						errs = append(errs, ValidationError{
 							Preset:  name,
 							Field:   ruleField + ".size",
 							Message: "width must be positive",
 						})
 					}
-					if r.Size[1] <= 0 {
+					if h, ok := r.Size[1].Absolute(); ok && h <= 0 {
 						errs = append(errs, ValidationError{
 							Preset:  name,
 							Field:   ruleField + ".size",
 							Message: "height must be positive",
 						})
 					}
+					for k, e := range r.Size {
+						if f, ok := e.fraction(); ok && f <= 0 {
+							errs = append(errs, ValidationError{
+								Preset:  name,
+								Field:   fmt.Sprintf("%s.size[%d]", ruleField, k),
+								Message: fmt.Sprintf("size fraction %s must be greater than 0", e),
+							})
+						}
+					}
 				}
 			}
 		}
+
+		for j, s := range p.Screens {
+			screenField := fmt.Sprintf("screens[%d]", j)
+			if s.Name == "" {
+				errs = append(errs, ValidationError{
+					Preset:  name,
+					Field:   screenField + ".name",
+					Message: "name is required",
+				})
+			}
+			if s.Width <= 0 || s.Height <= 0 {
+				errs = append(errs, ValidationError{
+					Preset:  name,
+					Field:   screenField,
+					Message: "width and height must be positive",
+				})
+			}
+		}
 	}
 
 	if len(errs) == 0 {
@@ -136,3 +231,18 @@ func ValidatePresets(presets []Preset) []ValidationError {
 	}
 	return errs
 }
+
+// validateExprs checks that every geometry value in a position or size pair is well-formed.
+func validateExprs(presetName, field string, exprs []Expr) []ValidationError {
+	var errs []ValidationError
+	for i, e := range exprs {
+		if err := e.Validate(); err != nil {
+			errs = append(errs, ValidationError{
+				Preset:  presetName,
+				Field:   fmt.Sprintf("%s[%d]", field, i),
+				Message: err.Error(),
+			})
+		}
+	}
+	return errs
+}
@@ -3,6 +3,7 @@ package preset_test
 import (
 	"testing"
 
+	"github.com/peacock0803sz/mado/internal/ax"
 	"github.com/peacock0803sz/mado/internal/preset"
 )
 
@@ -10,7 +11,7 @@ func TestValidatePresets_Valid(t *testing.T) {
 	presets := []preset.Preset{{
 		Name: "coding",
 		Rules: []preset.Rule{
-			{App: "Code", Position: []int{0, 0}, Size: []int{960, 1080}},
+			{App: "Code", Position: []preset.Expr{"0", "0"}, Size: []preset.Expr{"960", "1080"}},
 		},
 	}}
 	if errs := preset.ValidatePresets(presets); errs != nil {
@@ -22,7 +23,7 @@ func TestValidatePresets_EmptyName(t *testing.T) {
 	presets := []preset.Preset{{
 		Name: "",
 		Rules: []preset.Rule{
-			{App: "Code", Position: []int{0, 0}},
+			{App: "Code", Position: []preset.Expr{"0", "0"}},
 		},
 	}}
 	errs := preset.ValidatePresets(presets)
@@ -42,8 +43,8 @@ func TestValidatePresets_EmptyName(t *testing.T) {
 
 func TestValidatePresets_DuplicateNames(t *testing.T) {
 	presets := []preset.Preset{
-		{Name: "coding", Rules: []preset.Rule{{App: "Code", Position: []int{0, 0}}}},
-		{Name: "coding", Rules: []preset.Rule{{App: "Terminal", Position: []int{960, 0}}}},
+		{Name: "coding", Rules: []preset.Rule{{App: "Code", Position: []preset.Expr{"0", "0"}}}},
+		{Name: "coding", Rules: []preset.Rule{{App: "Terminal", Position: []preset.Expr{"960", "0"}}}},
 	}
 	errs := preset.ValidatePresets(presets)
 	if errs == nil {
@@ -64,7 +65,7 @@ func TestValidatePresets_MissingApp(t *testing.T) {
 	presets := []preset.Preset{{
 		Name: "broken",
 		Rules: []preset.Rule{
-			{Position: []int{0, 0}},
+			{Position: []preset.Expr{"0", "0"}},
 		},
 	}}
 	errs := preset.ValidatePresets(presets)
@@ -73,7 +74,7 @@ func TestValidatePresets_MissingApp(t *testing.T) {
 	}
 	found := false
 	for _, e := range errs {
-		if e.Message == "app is required" {
+		if e.Message == "app or bundle_id is required" {
 			found = true
 		}
 	}
@@ -82,6 +83,51 @@ func TestValidatePresets_MissingApp(t *testing.T) {
 	}
 }
 
+func TestValidatePresets_BundleIDOnly(t *testing.T) {
+	presets := []preset.Preset{{
+		Name: "by-bundle",
+		Rules: []preset.Rule{
+			{BundleID: "com.microsoft.VSCode", Position: []preset.Expr{"0", "0"}},
+		},
+	}}
+	if errs := preset.ValidatePresets(presets); errs != nil {
+		t.Errorf("expected no errors for bundle_id-only rule, got %v", errs)
+	}
+}
+
+func TestValidatePresets_Screens(t *testing.T) {
+	presets := []preset.Preset{{
+		Name:    "stored",
+		Rules:   []preset.Rule{{App: "Code", Snap: "maximize"}},
+		Screens: []preset.Screen{{Name: "Built-in", Width: 1440, Height: 900}, {Width: 0, Height: 900}},
+	}}
+	errs := preset.ValidatePresets(presets)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if errs[0].Field != "screens[1].name" || errs[1].Field != "screens[1]" {
+		t.Errorf("unexpected fields: %q, %q", errs[0].Field, errs[1].Field)
+	}
+}
+
+func TestValidatePresets_Scale(t *testing.T) {
+	presets := []preset.Preset{{
+		Name:  "scaled",
+		Scale: "stretch",
+		Rules: []preset.Rule{{
+			App: "Code", Position: []preset.Expr{"0", "0"}, Size: []preset.Expr{"1280", "1440"},
+			Reference: &ax.Rect{W: 2560},
+		}},
+	}}
+	errs := preset.ValidatePresets(presets)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if errs[0].Field != "scale" || errs[1].Field != "rules[0].reference" {
+		t.Errorf("unexpected fields: %q, %q", errs[0].Field, errs[1].Field)
+	}
+}
+
 func TestValidatePresets_MissingPositionAndSize(t *testing.T) {
 	presets := []preset.Preset{{
 		Name: "broken",
@@ -95,7 +141,7 @@ func TestValidatePresets_MissingPositionAndSize(t *testing.T) {
 	}
 	found := false
 	for _, e := range errs {
-		if e.Message == "position or size is required" {
+		if e.Message == "position, size or snap is required" {
 			found = true
 		}
 	}
@@ -108,7 +154,7 @@ func TestValidatePresets_NegativeSize(t *testing.T) {
 	presets := []preset.Preset{{
 		Name: "broken",
 		Rules: []preset.Rule{
-			{App: "Code", Size: []int{-1, 1080}},
+			{App: "Code", Size: []preset.Expr{"-1", "1080"}},
 		},
 	}}
 	errs := preset.ValidatePresets(presets)
@@ -130,7 +176,7 @@ func TestValidatePresets_InvalidNameChars(t *testing.T) {
 	presets := []preset.Preset{{
 		Name: "-invalid",
 		Rules: []preset.Rule{
-			{App: "Code", Position: []int{0, 0}},
+			{App: "Code", Position: []preset.Expr{"0", "0"}},
 		},
 	}}
 	errs := preset.ValidatePresets(presets)
@@ -144,7 +190,7 @@ func TestValidatePresets_PositionOnly(t *testing.T) {
 	presets := []preset.Preset{{
 		Name: "pos-only",
 		Rules: []preset.Rule{
-			{App: "Code", Position: []int{100, 200}},
+			{App: "Code", Position: []preset.Expr{"100", "200"}},
 		},
 	}}
 	if errs := preset.ValidatePresets(presets); errs != nil {
@@ -157,10 +203,94 @@ func TestValidatePresets_SizeOnly(t *testing.T) {
 	presets := []preset.Preset{{
 		Name: "size-only",
 		Rules: []preset.Rule{
-			{App: "Code", Size: []int{960, 1080}},
+			{App: "Code", Size: []preset.Expr{"960", "1080"}},
 		},
 	}}
 	if errs := preset.ValidatePresets(presets); errs != nil {
 		t.Errorf("expected no errors for size-only rule, got %v", errs)
 	}
 }
+
+func TestValidatePresets_Snap(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  preset.Rule
+		field string
+	}{
+		{"valid", preset.Rule{App: "Code", Snap: "left-half", Gap: 8}, ""},
+		{"unknown position", preset.Rule{App: "Code", Snap: "left"}, "rules[0].snap"},
+		{"negative margin", preset.Rule{App: "Code", Snap: "maximize", Margin: -4}, "rules[0].snap"},
+		{"combined with size", preset.Rule{App: "Code", Snap: "maximize", Size: []preset.Expr{"800", "600"}}, "rules[0].snap"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := preset.ValidatePresets([]preset.Preset{{Name: "snap", Rules: []preset.Rule{tt.rule}}})
+			if tt.field == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Field != tt.field {
+				t.Errorf("expected one error on %s, got %v", tt.field, errs)
+			}
+		})
+	}
+}
+
+func TestValidatePresets_ScreenIndex(t *testing.T) {
+	tests := []struct {
+		screen  string
+		wantErr bool
+	}{
+		{"#1", false},
+		{"#12", false},
+		{"Built-in", false},
+		{"#0", true},
+		{"#", true},
+		{"#left", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.screen, func(t *testing.T) {
+			rule := preset.Rule{App: "Code", Screen: tt.screen, Position: []preset.Expr{"0", "0"}}
+			errs := preset.ValidatePresets([]preset.Preset{{Name: "idx", Rules: []preset.Rule{rule}}})
+			if !tt.wantErr {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Field != "rules[0].screen" {
+				t.Errorf("expected one error on rules[0].screen, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestValidatePresets_Patterns(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  preset.Rule
+		field string
+	}{
+		{"valid", preset.Rule{App: "Google Chrome*", TitleRegex: `^PR #\d+`, ExcludeApp: "*Canary", ExcludeTitle: "DevTools"}, ""},
+		{"bad title_regex", preset.Rule{App: "Code", TitleRegex: "(main"}, "rules[0].title_regex"},
+		{"bad app glob", preset.Rule{App: "Code[", TitleRegex: ""}, "rules[0].app"},
+		{"bad exclude_app", preset.Rule{App: "Code", ExcludeApp: "[z-a]"}, "rules[0].exclude_app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Position = []preset.Expr{"0", "0"}
+			errs := preset.ValidatePresets([]preset.Preset{{Name: "pat", Rules: []preset.Rule{tt.rule}}})
+			if tt.field == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Field != tt.field {
+				t.Errorf("expected one error on %s, got %v", tt.field, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Package profile selects a preset by the set of connected displays.
+package profile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/preset"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// Profile maps a display configuration to the preset applied by preset auto.
+type Profile struct {
+	Name   string `json:"name"   yaml:"name"`
+	Preset string `json:"preset" yaml:"preset"`
+	// Screens is the fingerprint of the display configuration: one entry per
+	// connected screen, in any order.
+	Screens []Screen `json:"screens" yaml:"screens"`
+}
+
+// Screen describes one display of a fingerprint. Fields left empty match any display.
+type Screen struct {
+	Name   string `json:"name,omitempty"   yaml:"name,omitempty"`
+	Width  int    `json:"width,omitempty"  yaml:"width,omitempty"`
+	Height int    `json:"height,omitempty" yaml:"height,omitempty"`
+}
+
+// String returns the screen as "name WxH", leaving out unset fields.
+func (s Screen) String() string {
+	var parts []string
+	if s.Name != "" {
+		parts = append(parts, s.Name)
+	}
+	if s.Width > 0 || s.Height > 0 {
+		parts = append(parts, fmt.Sprintf("%dx%d", s.Width, s.Height))
+	}
+	if len(parts) == 0 {
+		return "any screen"
+	}
+	return strings.Join(parts, " ")
+}
+
+// NoMatchError is returned by Match when no profile fits the connected screens.
+type NoMatchError struct {
+	Screens []Screen // fingerprint of the connected screens
+}
+
+func (e *NoMatchError) Error() string {
+	names := make([]string, len(e.Screens))
+	for i, s := range e.Screens {
+		names[i] = s.String()
+	}
+	return fmt.Sprintf("no profile matches the connected screens (%d: %s)", len(e.Screens), strings.Join(names, ", "))
+}
+
+// Fingerprint returns the fingerprint of screens, ordered left-to-right, then
+// top-to-bottom as by window.OrderScreens.
+func Fingerprint(screens []ax.Screen) []Screen {
+	ordered := window.OrderScreens(screens)
+	fp := make([]Screen, len(ordered))
+	for i, s := range ordered {
+		fp[i] = Screen{Name: s.Name, Width: s.Width, Height: s.Height}
+	}
+	return fp
+}
+
+// Match returns the profile that best fits screens. A profile fits when it lists as
+// many screens as are connected and each of them can be paired with a different
+// connected screen. Among fitting profiles the one that pins down the most names and
+// sizes wins; ties go to the profile listed first.
+func Match(profiles []Profile, screens []ax.Screen) (Profile, error) {
+	best, bestScore := -1, -1
+	for i, p := range profiles {
+		if len(p.Screens) != len(screens) || !assign(p.Screens, screens, make([]bool, len(screens))) {
+			continue
+		}
+		if s := p.score(); s > bestScore {
+			best, bestScore = i, s
+		}
+	}
+	if best < 0 {
+		return Profile{}, &NoMatchError{Screens: Fingerprint(screens)}
+	}
+	return profiles[best], nil
+}
+
+// matches reports whether the connected screen scr fits the fingerprint entry s.
+func (s Screen) matches(scr ax.Screen) bool {
+	return (s.Name == "" || strings.EqualFold(s.Name, scr.Name)) &&
+		(s.Width == 0 || s.Width == scr.Width) &&
+		(s.Height == 0 || s.Height == scr.Height)
+}
+
+// score counts the names and dimensions p specifies.
+func (p Profile) score() int {
+	n := 0
+	for _, s := range p.Screens {
+		if s.Name != "" {
+			n++
+		}
+		if s.Width > 0 {
+			n++
+		}
+		if s.Height > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// assign reports whether every entry of fp can be paired with a different screen not
+// yet used. Display counts are small, so plain backtracking is fine.
+func assign(fp []Screen, screens []ax.Screen, used []bool) bool {
+	if len(fp) == 0 {
+		return true
+	}
+	for i, s := range screens {
+		if used[i] || !fp[0].matches(s) {
+			continue
+		}
+		used[i] = true
+		if assign(fp[1:], screens, used) {
+			return true
+		}
+		used[i] = false
+	}
+	return false
+}
+
+// ValidationError represents a single profile validation failure.
+type ValidationError struct {
+	Profile string `json:"profile"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("profile %q, %s: %s", e.Profile, e.Field, e.Message)
+}
+
+// ValidateProfiles checks profiles for structural validity and that every profile
+// refers to one of presets. Returns nil when all profiles are valid.
+func ValidateProfiles(profiles []Profile, presets []preset.Preset) []ValidationError {
+	var errs []ValidationError
+	seen := make(map[string]bool)
+
+	for i, p := range profiles {
+		name := p.Name
+		switch {
+		case p.Name == "":
+			name = fmt.Sprintf("profiles[%d]", i)
+			errs = append(errs, ValidationError{Profile: name, Field: "name", Message: "name is required"})
+		case seen[p.Name]:
+			errs = append(errs, ValidationError{Profile: name, Field: "name", Message: "duplicate profile name"})
+		default:
+			seen[p.Name] = true
+		}
+
+		if p.Preset == "" {
+			errs = append(errs, ValidationError{Profile: name, Field: "preset", Message: "preset is required"})
+		} else if !hasPreset(presets, p.Preset) {
+			errs = append(errs, ValidationError{Profile: name, Field: "preset", Message: fmt.Sprintf("preset %q not found", p.Preset)})
+		}
+
+		if len(p.Screens) == 0 {
+			errs = append(errs, ValidationError{Profile: name, Field: "screens", Message: "at least one screen is required"})
+		}
+		for j, s := range p.Screens {
+			if s.Width < 0 || s.Height < 0 {
+				errs = append(errs, ValidationError{
+					Profile: name,
+					Field:   fmt.Sprintf("screens[%d]", j),
+					Message: "width and height must not be negative",
+				})
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func hasPreset(presets []preset.Preset, name string) bool {
+	for _, p := range presets {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
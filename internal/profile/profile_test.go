@@ -0,0 +1,88 @@
+package profile_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/preset"
+	"github.com/peacock0803sz/mado/internal/profile"
+)
+
+var (
+	builtin  = ax.Screen{ID: 1, Name: "Built-in Retina Display", X: 0, Y: 0, Width: 1512, Height: 982, IsPrimary: true}
+	dell     = ax.Screen{ID: 2, Name: "DELL U2720Q", X: 1512, Y: 0, Width: 2560, Height: 1440}
+	beamer   = ax.Screen{ID: 3, Name: "EPSON PJ", X: -1920, Y: 0, Width: 1920, Height: 1080}
+	profiles = []profile.Profile{
+		{Name: "undocked", Preset: "solo", Screens: []profile.Screen{{Name: "Built-in Retina Display"}}},
+		{Name: "any-two", Preset: "dual", Screens: []profile.Screen{{}, {}}},
+		{Name: "docked", Preset: "coding", Screens: []profile.Screen{
+			{Name: "DELL U2720Q", Width: 2560, Height: 1440},
+			{Name: "built-in retina display"},
+		}},
+	}
+)
+
+func TestFingerprint(t *testing.T) {
+	got := profile.Fingerprint([]ax.Screen{dell, builtin})
+	want := []profile.Screen{
+		{Name: "Built-in Retina Display", Width: 1512, Height: 982},
+		{Name: "DELL U2720Q", Width: 2560, Height: 1440},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		screens []ax.Screen
+		want    string
+	}{
+		{"single screen", []ax.Screen{builtin}, "undocked"},
+		// both two-screen profiles fit; the more specific one wins
+		{"docked", []ax.Screen{dell, builtin}, "docked"},
+		{"projector", []ax.Screen{builtin, beamer}, "any-two"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := profile.Match(profiles, tt.screens)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Name != tt.want {
+				t.Errorf("matched %q, want %q", p.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch_NoMatch(t *testing.T) {
+	_, err := profile.Match(profiles, []ax.Screen{builtin, dell, beamer})
+	var noMatch *profile.NoMatchError
+	if !errors.As(err, &noMatch) {
+		t.Fatalf("expected NoMatchError, got %v", err)
+	}
+	if len(noMatch.Screens) != 3 || noMatch.Screens[0].Name != "EPSON PJ" {
+		t.Errorf("unexpected fingerprint: %+v", noMatch.Screens)
+	}
+}
+
+func TestValidateProfiles(t *testing.T) {
+	presets := []preset.Preset{{Name: "coding"}}
+	errs := profile.ValidateProfiles([]profile.Profile{
+		{Name: "docked", Preset: "coding", Screens: []profile.Screen{{Width: 2560, Height: 1440}}},
+		{Name: "docked", Preset: "missing", Screens: []profile.Screen{{Width: -1}}},
+		{Preset: "coding"},
+	}, presets)
+	want := []string{"name", "preset", "screens[0]", "name", "screens"}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %v", len(want), errs)
+	}
+	for i, e := range errs {
+		if e.Field != want[i] {
+			t.Errorf("errs[%d].Field = %q, want %q", i, e.Field, want[i])
+		}
+	}
+}
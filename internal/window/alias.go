@@ -0,0 +1,172 @@
+package window
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+)
+
+// Screen positions accepted by ScreenAlias.Position.
+const (
+	PositionLeftmost   = "leftmost"
+	PositionRightmost  = "rightmost"
+	PositionTopmost    = "topmost"
+	PositionBottommost = "bottommost"
+)
+
+// ScreenAlias names a screen by properties that hold on any machine, unlike screen
+// IDs. An alias can be used wherever a screen reference is accepted. Criteria left
+// empty are not checked; when several screens match, the first in index order wins.
+type ScreenAlias struct {
+	Alias string `json:"alias" yaml:"alias"`
+	ID    uint32 `json:"id,omitempty" yaml:"id,omitempty"`
+	// Name matches the screen name case-insensitively, exactly or as a glob ("DELL*").
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Resolution is the screen size as "WIDTHxHEIGHT", e.g. "2560x1440".
+	Resolution string `json:"resolution,omitempty" yaml:"resolution,omitempty"`
+	// Position is the place of the screen in the arrangement: leftmost, rightmost,
+	// topmost or bottommost.
+	Position string `json:"position,omitempty" yaml:"position,omitempty"`
+	Primary  bool   `json:"primary,omitempty" yaml:"primary,omitempty"`
+}
+
+// ValidateScreenAliases returns an error for the first alias that cannot be used.
+func ValidateScreenAliases(aliases []ScreenAlias) error {
+	seen := make(map[string]bool)
+	for i, a := range aliases {
+		field := fmt.Sprintf("screens[%d]", i)
+		name := strings.ToLower(a.Alias)
+		switch {
+		case a.Alias == "":
+			return fmt.Errorf("%s: alias is required", field)
+		case IsScreenIndex(a.Alias):
+			return fmt.Errorf("%s: alias %q must not start with #", field, a.Alias)
+		case isScreenID(a.Alias):
+			return fmt.Errorf("%s: alias %q must not be a number", field, a.Alias)
+		case seen[name]:
+			return fmt.Errorf("%s: duplicate alias %q", field, a.Alias)
+		case a.ID == 0 && a.Name == "" && a.Resolution == "" && a.Position == "" && !a.Primary:
+			return fmt.Errorf("%s: alias %q needs at least one of id, name, resolution, position or primary", field, a.Alias)
+		}
+		seen[name] = true
+		if _, err := compileAppPattern("name", a.Name); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if a.Resolution != "" {
+			if _, _, err := parseResolution(a.Resolution); err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+		}
+		switch a.Position {
+		case "", PositionLeftmost, PositionRightmost, PositionTopmost, PositionBottommost:
+		default:
+			return fmt.Errorf("%s: invalid position %q (want %s, %s, %s or %s)", field, a.Position,
+				PositionLeftmost, PositionRightmost, PositionTopmost, PositionBottommost)
+		}
+	}
+	return nil
+}
+
+// FindScreenAlias returns the alias named ref (case-insensitive).
+func FindScreenAlias(aliases []ScreenAlias, ref string) (ScreenAlias, bool) {
+	for _, a := range aliases {
+		if strings.EqualFold(a.Alias, ref) {
+			return a, true
+		}
+	}
+	return ScreenAlias{}, false
+}
+
+// ResolveScreenAlias rewrites ref to the ID of the screen it names when it is one of
+// aliases, so that it can be used with MatchScreen and FindScreen. Other references
+// are returned unchanged, as are aliases that match no connected screen.
+func ResolveScreenAlias(screens []ax.Screen, aliases []ScreenAlias, ref string) string {
+	a, ok := FindScreenAlias(aliases, ref)
+	if !ok {
+		return ref
+	}
+	if s, ok := a.Find(screens); ok {
+		return strconv.FormatUint(uint64(s.ID), 10)
+	}
+	return ref
+}
+
+// resolveScreenRef is ResolveScreenRef for a filter of List or Move. The screens
+// are only listed when ref needs them.
+func resolveScreenRef(ctx context.Context, svc ax.WindowService, aliases []ScreenAlias, ref string) (string, error) {
+	if !NeedsScreens(aliases, ref) {
+		return ref, nil
+	}
+	screens, err := svc.ListScreens(ctx)
+	if err != nil {
+		return "", err
+	}
+	return ResolveScreenRef(screens, aliases, ref), nil
+}
+
+// Find returns the first screen in index order that meets every criterion of a.
+func (a ScreenAlias) Find(screens []ax.Screen) (ax.Screen, bool) {
+	re, err := compileAppPattern("name", a.Name)
+	if err != nil {
+		return ax.Screen{}, false
+	}
+	width, height, _ := parseResolution(a.Resolution)
+	ordered := OrderScreens(screens)
+	for _, s := range ordered {
+		switch {
+		case a.ID != 0 && s.ID != a.ID,
+			re != nil && !re.MatchString(s.Name),
+			a.Resolution != "" && (s.Width != width || s.Height != height),
+			a.Primary && !s.IsPrimary,
+			a.Position != "" && !atEdge(ordered, s, a.Position):
+			continue
+		}
+		return s, true
+	}
+	return ax.Screen{}, false
+}
+
+// atEdge reports whether no screen reaches further toward the edge named by position than s.
+func atEdge(screens []ax.Screen, s ax.Screen, position string) bool {
+	for _, o := range screens {
+		switch position {
+		case PositionLeftmost:
+			if o.X < s.X {
+				return false
+			}
+		case PositionRightmost:
+			if o.X+o.Width > s.X+s.Width {
+				return false
+			}
+		case PositionTopmost:
+			if o.Y < s.Y {
+				return false
+			}
+		case PositionBottommost:
+			if o.Y+o.Height > s.Y+s.Height {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseResolution parses "WIDTHxHEIGHT".
+func parseResolution(s string) (int, int, error) {
+	ws, hs, ok := strings.Cut(strings.ToLower(s), "x")
+	w, errW := strconv.Atoi(strings.TrimSpace(ws))
+	h, errH := strconv.Atoi(strings.TrimSpace(hs))
+	if !ok || errW != nil || errH != nil || w <= 0 || h <= 0 {
+		return 0, 0, fmt.Errorf("invalid resolution %q (want WIDTHxHEIGHT, e.g. 2560x1440)", s)
+	}
+	return w, h, nil
+}
+
+// isScreenID reports whether ref is a screen ID, which MatchScreen compares as a number.
+func isScreenID(ref string) bool {
+	_, err := strconv.ParseUint(ref, 10, 32)
+	return err == nil
+}
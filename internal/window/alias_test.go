@@ -0,0 +1,112 @@
+package window_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+var testAliases = []window.ScreenAlias{
+	{Alias: "main", Primary: true},
+	{Alias: "external", Name: "dell*"},
+	{Alias: "left", Position: window.PositionLeftmost},
+	{Alias: "bottom", Position: window.PositionBottommost},
+	{Alias: "fullhd", Resolution: "1920x1080"},
+	{Alias: "desk", ID: 12345678},
+	{Alias: "missing", Resolution: "640x480"},
+}
+
+func TestScreenAlias_Find(t *testing.T) {
+	screens := []ax.Screen{
+		{ID: 69678592, Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900, IsPrimary: true},
+		{ID: 555, Name: "Projector", X: 0, Y: 900, Width: 1920, Height: 1080},
+		{ID: 12345678, Name: "DELL U2720Q", X: -2560, Y: 0, Width: 2560, Height: 1440},
+	}
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"main", "69678592"},
+		{"MAIN", "69678592"},
+		{"external", "12345678"},
+		{"left", "12345678"},
+		{"bottom", "555"},
+		{"fullhd", "555"},
+		{"desk", "12345678"},
+		// an alias matching no screen and plain references are left alone
+		{"missing", "missing"},
+		{"Projector", "Projector"},
+		{"#2", "#2"},
+	}
+	for _, tt := range tests {
+		if got := window.ResolveScreenAlias(screens, testAliases, tt.ref); got != tt.want {
+			t.Errorf("ResolveScreenAlias(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestValidateScreenAliases(t *testing.T) {
+	if err := window.ValidateScreenAliases(testAliases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	invalid := [][]window.ScreenAlias{
+		{{Primary: true}},
+		{{Alias: "#1"}},
+		{{Alias: "42"}},
+		{{Alias: "main", Primary: true}, {Alias: "Main", Primary: true}},
+		{{Alias: "any"}},
+		{{Alias: "wide", Resolution: "wide"}},
+		{{Alias: "middle", Position: "center"}},
+		{{Alias: "bad", Name: "[DELL"}},
+	}
+	for _, aliases := range invalid {
+		if err := window.ValidateScreenAliases(aliases); err == nil {
+			t.Errorf("expected error for %+v", aliases)
+		}
+	}
+}
+
+func TestList_ScreenAlias(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: []ax.Window{
+			{AppName: "Terminal", Title: "zsh", PID: 100, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
+			{AppName: "Safari", Title: "GitHub", PID: 200, State: ax.StateNormal, ScreenID: 2, ScreenName: "DELL U2720Q"},
+		},
+		Screens: snapScreens,
+	}
+	got, err := window.List(context.Background(), svc, window.ListOptions{
+		ScreenFilter:  "right",
+		ScreenAliases: []window.ScreenAlias{{Alias: "right", Position: window.PositionRightmost}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].AppName != "Safari" {
+		t.Errorf("expected only Safari, got %+v", got)
+	}
+}
+
+func TestMove_ToScreenAlias(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: []ax.Window{
+			{AppName: "Terminal", Title: "zsh", PID: 100, State: ax.StateNormal, Width: 800, Height: 600, ScreenID: 1},
+		},
+		Screens: snapScreens,
+	}
+	// the alias "left" takes precedence over the relative reference, which finds no
+	// screen to the left of Built-in
+	affected, err := window.Move(context.Background(), svc, window.MoveOptions{
+		AppFilter:     "Terminal",
+		ToScreen:      "left",
+		Snap:          &window.SnapOptions{Position: "maximize"},
+		ScreenAliases: []window.ScreenAlias{{Alias: "left", Name: "External"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := affected[0]; w.X != 1920 || w.Y != -200 || w.Width != 2560 || w.Height != 1440 {
+		t.Errorf("Terminal frame = (%d,%d %dx%d), want (1920,-200 2560x1440)", w.X, w.Y, w.Width, w.Height)
+	}
+}
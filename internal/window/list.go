@@ -11,27 +11,48 @@ import (
 
 // ListOptions holds filter options for the list command.
 type ListOptions struct {
-	AppFilter     string
-	ScreenFilter  string
-	IgnoreApps    []string
+	AppFilter    string // exact match or glob, case-insensitive
+	BundleID     string // exact match, case-insensitive
+	TitleRegex   string
+	NotTitle     string // exclude windows whose title contains this (case-insensitive)
+	Where        string // filter expression, e.g. `app == "Safari" && width > 800`
+	ScreenFilter string
+	IgnoreApps   []string
+	// ScreenAliases are the aliases ScreenFilter may refer to.
+	ScreenAliases []ScreenAlias
 	DesktopFilter int // 0 = no filter; N = only windows on desktop N (plus desktop=0 windows)
 }
 
 // List retrieves all windows and returns them after applying filters.
 func List(ctx context.Context, svc ax.WindowService, opts ListOptions) ([]ax.Window, error) {
+	m, err := CompileMatch(MatchSpec{
+		App:          opts.AppFilter,
+		BundleID:     opts.BundleID,
+		TitleRegex:   opts.TitleRegex,
+		ExcludeTitle: opts.NotTitle,
+		Where:        opts.Where,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ScreenFilter, err = resolveScreenRef(ctx, svc, opts.ScreenAliases, opts.ScreenFilter); err != nil {
+		return nil, err
+	}
+
 	windows, err := svc.ListWindows(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return filterWindows(windows, opts), nil
+	return filterWindows(windows, opts, m), nil
 }
 
 // filterWindows narrows down the window list based on filter options.
-func filterWindows(windows []ax.Window, opts ListOptions) []ax.Window {
+func filterWindows(windows []ax.Window, opts ListOptions, m *Matcher) []ax.Window {
 	result := make([]ax.Window, 0, len(windows))
 	for _, w := range windows {
-		if opts.AppFilter != "" && !strings.EqualFold(w.AppName, opts.AppFilter) {
+		if !m.Match(w) {
 			continue
 		}
 		if IsIgnoredApp(w.AppName, opts.IgnoreApps) {
@@ -71,9 +92,35 @@ func IsIgnoredApp(appName string, ignoreApps []string) bool {
 }
 
 // MatchScreen filters a window by screen ID (numeric string) or screen name (case-insensitive).
+// A filter of the form "!ref" matches windows on any other screen. Screen aliases,
+// selectors and "#N" indexes must be resolved to an ID first (see ResolveScreenRef).
 func MatchScreen(w ax.Window, filter string) bool {
+	if rest, ok := strings.CutPrefix(filter, "!"); ok && rest != "" {
+		return !MatchScreen(w, rest)
+	}
 	if strings.EqualFold(w.ScreenName, filter) {
 		return true
 	}
 	return strconv.FormatUint(uint64(w.ScreenID), 10) == filter
 }
+
+// FindScreen returns the screen identified by filter: a screen ID (numeric string),
+// a screen name (case-insensitive), a selector (see ResolveScreenSelector) or a "#N"
+// index (see OrderScreens). For "!ref" it returns the first other screen in index order.
+func FindScreen(screens []ax.Screen, filter string) (ax.Screen, bool) {
+	filter = ResolveScreenIndex(screens, ResolveScreenSelector(screens, filter))
+	if rest, ok := strings.CutPrefix(filter, "!"); ok && rest != "" {
+		for _, s := range OrderScreens(screens) {
+			if !strings.EqualFold(s.Name, rest) && strconv.FormatUint(uint64(s.ID), 10) != rest {
+				return s, true
+			}
+		}
+		return ax.Screen{}, false
+	}
+	for _, s := range screens {
+		if strings.EqualFold(s.Name, filter) || strconv.FormatUint(uint64(s.ID), 10) == filter {
+			return s, true
+		}
+	}
+	return ax.Screen{}, false
+}
@@ -9,10 +9,10 @@ import (
 )
 
 var testWindows = []ax.Window{
-	{AppName: "Terminal", Title: "peacock — zsh", PID: 100, State: ax.StateNormal, ScreenID: 42, ScreenName: "Built-in Retina Display"},
-	{AppName: "Safari", Title: "GitHub", PID: 200, State: ax.StateNormal, ScreenID: 42, ScreenName: "Built-in Retina Display"},
-	{AppName: "Safari", Title: "Apple", PID: 200, State: ax.StateMinimized},
-	{AppName: "Finder", Title: "", PID: 300, State: ax.StateHidden},
+	{ID: 1, AppName: "Terminal", BundleID: "com.apple.Terminal", Title: "peacock — zsh", PID: 100, State: ax.StateNormal, ScreenID: 42, ScreenName: "Built-in Retina Display"},
+	{ID: 2, AppName: "Safari", BundleID: "com.apple.Safari", Title: "GitHub", PID: 200, State: ax.StateNormal, ScreenID: 42, ScreenName: "Built-in Retina Display"},
+	{ID: 3, AppName: "Safari", BundleID: "com.apple.Safari", Title: "Apple", PID: 200, State: ax.StateMinimized},
+	{ID: 4, AppName: "Finder", Title: "", PID: 300, State: ax.StateHidden},
 }
 
 func TestList_NoFilter(t *testing.T) {
@@ -154,3 +154,34 @@ func TestList_IgnoreAppsNonExistent(t *testing.T) {
 		t.Errorf("expected %d windows (non-existent ignored app), got %d", len(testWindows), len(windows))
 	}
 }
+
+func TestList_TitleRegexAndNotTitle(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: testWindows}
+	windows, err := window.List(context.Background(), svc, window.ListOptions{
+		AppFilter:  "Saf*",
+		TitleRegex: `^[A-Z]`,
+		NotTitle:   "apple",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(windows) != 1 || windows[0].Title != "GitHub" {
+		t.Errorf("expected only GitHub, got %+v", windows)
+	}
+}
+
+func TestList_BundleID(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: testWindows}
+	windows, err := window.List(context.Background(), svc, window.ListOptions{BundleID: "com.apple.safari"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(windows) != 2 {
+		t.Fatalf("expected 2 Safari windows, got %+v", windows)
+	}
+	for _, w := range windows {
+		if w.AppName != "Safari" {
+			t.Errorf("unexpected window %+v", w)
+		}
+	}
+}
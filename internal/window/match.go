@@ -0,0 +1,158 @@
+package window
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+)
+
+// MatchSpec describes which windows a filter selects by app name and title.
+// Empty fields do not filter.
+type MatchSpec struct {
+	App          string // case-insensitive exact match, or a glob when it contains * ? or [
+	BundleID     string // case-insensitive exact match of the app's bundle identifier
+	Title        string // case-insensitive partial match
+	TitleRegex   string // regular expression (RE2 syntax) matched against the title
+	ExcludeApp   string // like App, but excludes matching windows
+	ExcludeTitle string // like Title, but excludes matching windows
+	Where        string // filter expression over window fields (see parseWhere)
+}
+
+// PatternError reports an invalid glob or regular expression in a MatchSpec.
+// Field names the offending MatchSpec field in snake_case (e.g. "title_regex").
+type PatternError struct {
+	Field   string
+	Pattern string
+	Err     error
+}
+
+func (e *PatternError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %v", e.Field, e.Pattern, e.Err)
+}
+
+func (e *PatternError) Unwrap() error { return e.Err }
+
+// Matcher is a compiled MatchSpec. The zero value matches every window.
+type Matcher struct {
+	app          *regexp.Regexp
+	titleRegex   *regexp.Regexp
+	excludeApp   *regexp.Regexp
+	bundleID     string
+	title        string
+	excludeTitle string
+	where        whereNode
+}
+
+// CompileMatch compiles spec once so that it can be matched against many windows.
+// Invalid patterns yield a *PatternError and an invalid Where a *WhereError.
+func CompileMatch(spec MatchSpec) (*Matcher, error) {
+	m := &Matcher{
+		bundleID:     spec.BundleID,
+		title:        strings.ToLower(spec.Title),
+		excludeTitle: strings.ToLower(spec.ExcludeTitle),
+	}
+	var err error
+	if m.app, err = compileAppPattern("app", spec.App); err != nil {
+		return nil, err
+	}
+	if m.excludeApp, err = compileAppPattern("exclude_app", spec.ExcludeApp); err != nil {
+		return nil, err
+	}
+	if spec.TitleRegex != "" {
+		if m.titleRegex, err = regexp.Compile(spec.TitleRegex); err != nil {
+			return nil, &PatternError{Field: "title_regex", Pattern: spec.TitleRegex, Err: err}
+		}
+	}
+	if spec.Where != "" {
+		if m.where, err = parseWhere(spec.Where); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Match reports whether w passes every filter of the matcher.
+func (m *Matcher) Match(w ax.Window) bool {
+	if m.app != nil && !m.app.MatchString(w.AppName) {
+		return false
+	}
+	if m.bundleID != "" && !strings.EqualFold(w.BundleID, m.bundleID) {
+		return false
+	}
+	if m.excludeApp != nil && m.excludeApp.MatchString(w.AppName) {
+		return false
+	}
+	title := strings.ToLower(w.Title)
+	if m.title != "" && !strings.Contains(title, m.title) {
+		return false
+	}
+	if m.excludeTitle != "" && strings.Contains(title, m.excludeTitle) {
+		return false
+	}
+	if m.titleRegex != nil && !m.titleRegex.MatchString(w.Title) {
+		return false
+	}
+	if m.where != nil && !m.where.match(w) {
+		return false
+	}
+	return true
+}
+
+// compileAppPattern turns an app name or glob into a case-insensitive anchored regexp.
+// An empty pattern yields nil.
+func compileAppPattern(field, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	expr, err := globToRegexp(pattern)
+	if err != nil {
+		return nil, &PatternError{Field: field, Pattern: pattern, Err: err}
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, &PatternError{Field: field, Pattern: pattern, Err: err}
+	}
+	return re, nil
+}
+
+// globToRegexp converts a glob (* any run, ? one character, [...] a class, [!...] a
+// negated class) into an anchored, case-insensitive regular expression.
+func globToRegexp(glob string) (string, error) {
+	var b strings.Builder
+	b.WriteString(`(?i)^`)
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '*':
+			b.WriteString(`.*`)
+		case '?':
+			b.WriteString(`.`)
+		case '[':
+			end := i + 1
+			if end < len(runes) && runes[end] == '!' {
+				end++
+			}
+			if end < len(runes) && runes[end] == ']' {
+				end++ // a leading ] is literal
+			}
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end >= len(runes) {
+				return "", fmt.Errorf("unterminated character class")
+			}
+			class := string(runes[i+1 : end])
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i = end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString(`$`)
+	return b.String(), nil
+}
@@ -0,0 +1,71 @@
+package window_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	chrome := ax.Window{AppName: "Google Chrome Canary", BundleID: "com.google.Chrome.canary", Title: "Pull Request #42 - GitHub"}
+	tests := []struct {
+		name string
+		spec window.MatchSpec
+		want bool
+	}{
+		{"zero spec", window.MatchSpec{}, true},
+		{"exact app", window.MatchSpec{App: "google chrome canary"}, true},
+		{"exact app is not a prefix match", window.MatchSpec{App: "Google Chrome"}, false},
+		{"glob star", window.MatchSpec{App: "Google Chrome*"}, true},
+		{"glob question", window.MatchSpec{App: "Google Chrome Canar?"}, true},
+		{"glob class", window.MatchSpec{App: "[gh]oogle*"}, true},
+		{"glob negated class", window.MatchSpec{App: "[!g]oogle*"}, false},
+		{"glob metachars are literal", window.MatchSpec{App: "Google.Chrome*"}, false},
+		{"title substring", window.MatchSpec{Title: "github"}, true},
+		{"title regex", window.MatchSpec{TitleRegex: `#\d+`}, true},
+		{"title regex is case-sensitive", window.MatchSpec{TitleRegex: `^pull`}, false},
+		{"title regex case flag", window.MatchSpec{TitleRegex: `(?i)^pull`}, true},
+		{"exclude title", window.MatchSpec{App: "Google*", ExcludeTitle: "GITHUB"}, false},
+		{"exclude title no hit", window.MatchSpec{ExcludeTitle: "gitlab"}, true},
+		{"exclude app glob", window.MatchSpec{ExcludeApp: "*canary"}, false},
+		{"bundle id", window.MatchSpec{BundleID: "COM.google.chrome.Canary"}, true},
+		{"bundle id is not a prefix match", window.MatchSpec{BundleID: "com.google.Chrome"}, false},
+		{"bundle id and app", window.MatchSpec{App: "Safari", BundleID: "com.google.Chrome.canary"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := window.CompileMatch(tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := m.Match(chrome); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileMatch_InvalidPattern(t *testing.T) {
+	tests := []struct {
+		spec  window.MatchSpec
+		field string
+	}{
+		{window.MatchSpec{TitleRegex: "(unclosed"}, "title_regex"},
+		{window.MatchSpec{App: "Chrome[abc"}, "app"},
+		{window.MatchSpec{ExcludeApp: "[z-a]*"}, "exclude_app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			_, err := window.CompileMatch(tt.spec)
+			var patErr *window.PatternError
+			if !errors.As(err, &patErr) {
+				t.Fatalf("expected *window.PatternError, got %T: %v", err, err)
+			}
+			if patErr.Field != tt.field {
+				t.Errorf("Field = %q, want %q", patErr.Field, tt.field)
+			}
+		})
+	}
+}
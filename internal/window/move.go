@@ -3,6 +3,8 @@ package window
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 
 	"github.com/peacock0803sz/mado/internal/ax"
@@ -20,79 +22,281 @@ type Size struct {
 
 // MoveOptions holds the options for the move command.
 type MoveOptions struct {
-	AppFilter     string
+	AppFilter     string // exact match or glob, case-insensitive
+	BundleID      string // exact match, case-insensitive
 	TitleFilter   string
+	TitleRegex    string
+	NotTitle      string // exclude windows whose title contains this (case-insensitive)
+	Where         string // filter expression, e.g. `app == "Safari" && width > 800`
+	IDFilter      uint32 // 0 = no filter; N = only the window with Window.ID N
 	ScreenFilter  string
 	DesktopFilter int // 0 = no filter; N = only windows on desktop N (plus desktop=0 windows)
 	Position      *Point
 	Size          *Size
+	// Snap, when set, computes the position and size of each target from a named
+	// snap position on the window's own screen. It is mutually exclusive with Position and Size.
+	Snap *SnapOptions
+	// ToScreen, when set, moves each target to another screen: a screen ID, name or
+	// "#N" index, or next, prev, left or right relative to the window's screen. The
+	// frame keeps its relative placement, scaled to the target screen (see
+	// TranslateFrame); with Snap, the window is snapped on the target screen instead.
+	ToScreen string
+	// MoveBy and ResizeBy change the current frame by a delta. ResizeFrom names the
+	// edge or corner that moves when resizing (see ResizeAnchors); the default is
+	// bottom-right, which keeps the top-left corner in place.
+	MoveBy     *Point
+	ResizeBy   *Size
+	ResizeFrom string
+	// Clamp keeps the resulting frame on the window's screen (the target screen with
+	// ToScreen), shrinking it when it is larger than the screen.
+	Clamp bool
+	// Area is the part of each screen that Snap, ToScreen and Clamp work within:
+	// AreaFrame (the default) or AreaVisible.
+	Area string
+	// ScreenAliases are the aliases ScreenFilter and ToScreen may refer to. An alias
+	// takes precedence over a screen of the same name, over selectors and over next,
+	// prev, left and right.
+	ScreenAliases []ScreenAlias
 	All           bool
 }
 
+// resizeAnchors maps each ResizeFrom value to the share of the width and height
+// change taken by the left and top edges; the rest goes to the right and bottom edges.
+var resizeAnchors = map[string][2]float64{
+	"left":         {1, 0},
+	"right":        {0, 0},
+	"top":          {0, 1},
+	"bottom":       {0, 0},
+	"top-left":     {1, 1},
+	"top-right":    {0, 1},
+	"bottom-left":  {1, 0},
+	"bottom-right": {0, 0},
+	"center":       {0.5, 0.5},
+}
+
+// ResizeAnchors returns the valid ResizeFrom values in sorted order.
+func ResizeAnchors() []string {
+	names := make([]string, 0, len(resizeAnchors))
+	for name := range resizeAnchors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateResizeFrom returns an error when anchor is not a valid ResizeFrom value.
+func ValidateResizeFrom(anchor string) error {
+	if _, ok := resizeAnchors[anchor]; !ok && anchor != "" {
+		return fmt.Errorf("unknown resize anchor %q (valid: %s)", anchor, strings.Join(ResizeAnchors(), ", "))
+	}
+	return nil
+}
+
+// nudgeFrame applies the relative move and resize of opts to r.
+func nudgeFrame(r Rect, opts MoveOptions) (Rect, error) {
+	if opts.MoveBy != nil {
+		r.X += opts.MoveBy.X
+		r.Y += opts.MoveBy.Y
+	}
+	if opts.ResizeBy != nil {
+		anchor, ok := resizeAnchors[opts.ResizeFrom]
+		if !ok {
+			anchor = resizeAnchors["bottom-right"]
+		}
+		r.X -= int(math.Round(float64(opts.ResizeBy.W) * anchor[0]))
+		r.Y -= int(math.Round(float64(opts.ResizeBy.H) * anchor[1]))
+		r.W += opts.ResizeBy.W
+		r.H += opts.ResizeBy.H
+		if r.W <= 0 || r.H <= 0 {
+			return r, fmt.Errorf("resizing by %d,%d leaves a %dx%d window", opts.ResizeBy.W, opts.ResizeBy.H, r.W, r.H)
+		}
+	}
+	return r, nil
+}
+
 // Move moves or resizes the target window(s).
 // Returns AmbiguousTargetError when multiple windows match and --all is not set.
 func Move(ctx context.Context, svc ax.WindowService, opts MoveOptions) ([]ax.Window, error) {
+	_, affected, err := MoveTracked(ctx, svc, opts)
+	return affected, err
+}
+
+// MoveTracked is Move that also returns the target windows with their frames before
+// the move (first), so that callers can record them (e.g. in history) without listing
+// the windows again.
+func MoveTracked(ctx context.Context, svc ax.WindowService, opts MoveOptions) ([]ax.Window, []ax.Window, error) {
+	m, err := CompileMatch(MatchSpec{
+		App:          opts.AppFilter,
+		BundleID:     opts.BundleID,
+		Title:        opts.TitleFilter,
+		TitleRegex:   opts.TitleRegex,
+		ExcludeTitle: opts.NotTitle,
+		Where:        opts.Where,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	// errors name the screens as given, not the IDs aliases and selectors resolve to
+	query, toRef := buildQuery(opts), opts.ToScreen
+	if opts.ScreenFilter, err = resolveScreenRef(ctx, svc, opts.ScreenAliases, opts.ScreenFilter); err != nil {
+		return nil, nil, err
+	}
+	if opts.ToScreen, err = resolveScreenRef(ctx, svc, opts.ScreenAliases, opts.ToScreen); err != nil {
+		return nil, nil, err
+	}
+
 	windows, err := svc.ListWindows(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	targets := filterForMove(windows, opts)
+	targets := filterForMove(windows, opts, m)
 
 	if len(targets) == 0 {
-		return nil, &ax.NotFoundError{Query: buildQuery(opts)}
+		return nil, nil, &ax.NotFoundError{Query: query}
 	}
 
 	if len(targets) > 1 && !opts.All {
-		return nil, &ax.AmbiguousTargetError{
-			Query:      buildQuery(opts),
+		return nil, nil, &ax.AmbiguousTargetError{
+			Query:      query,
 			Candidates: targets,
 		}
 	}
 
+	var screens []ax.Screen
+	if opts.Snap != nil {
+		if err := ValidateSnap(*opts.Snap); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := ValidateResizeFrom(opts.ResizeFrom); err != nil {
+		return nil, nil, err
+	}
+	if err := ValidateArea(opts.Area); err != nil {
+		return nil, nil, err
+	}
+	if opts.Snap != nil || opts.ToScreen != "" || opts.Clamp {
+		screens, err = svc.ListScreens(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range screens {
+			screens[i] = ScreenArea(screens[i], opts.Area)
+		}
+	}
+	// an unknown screen name fails before any window is moved
+	if opts.ToScreen != "" && !IsRelativeScreen(opts.ToScreen) {
+		if _, ok := FindScreen(screens, opts.ToScreen); !ok {
+			return nil, nil, &ScreenNotFoundError{Ref: toRef}
+		}
+	}
+
 	var affected []ax.Window
+	// fail reports err, upgrading it to a partial success when --all already moved some windows.
+	fail := func(err error) ([]ax.Window, []ax.Window, error) {
+		if opts.All && len(affected) > 0 {
+			return targets, affected, &ax.PartialSuccessError{Affected: affected, Cause: err}
+		}
+		return targets, affected, err
+	}
+
+	// fullscreen windows cannot be operated on (exit 5); fail before any window is moved
 	for _, w := range targets {
-		// fullscreen windows cannot be operated on (exit 5)
 		if w.State == ax.StateFullscreen {
-			return nil, &FullscreenError{Window: w}
+			return nil, nil, &FullscreenError{Window: w}
+		}
+	}
+
+	for _, w := range targets {
+		position, size := opts.Position, opts.Size
+		// dest is the screen the window ends up on when it changes screens
+		var dest *ax.Screen
+		if opts.Snap != nil || opts.ToScreen != "" {
+			s, ok := ScreenOf(screens, w)
+			if !ok {
+				return fail(fmt.Errorf("cannot move %s %q: window is not on a known screen (see mado rescue)", w.AppName, w.Title))
+			}
+			from := s
+			if opts.ToScreen != "" {
+				if s, err = TargetScreen(screens, from, opts.ToScreen); err != nil {
+					return fail(err)
+				}
+			}
+			r := TranslateFrame(w.Frame(), from, s)
+			if opts.Snap != nil {
+				if r, err = SnapFrame(w, s, *opts.Snap); err != nil {
+					return fail(err)
+				}
+			}
+			position = &Point{X: r.X, Y: r.Y}
+			size = &Size{W: r.W, H: r.H}
+			dest = &s
 		}
 
-		if opts.Position != nil {
-			if err := svc.MoveWindow(ctx, w.PID, w.Title, opts.Position.X, opts.Position.Y); err != nil {
-				if opts.All && len(affected) > 0 {
-					return affected, &ax.PartialSuccessError{Affected: affected, Cause: err}
+		if opts.MoveBy != nil || opts.ResizeBy != nil || opts.Clamp {
+			r := w.Frame()
+			if position != nil {
+				r.X, r.Y = position.X, position.Y
+			}
+			if size != nil {
+				r.W, r.H = size.W, size.H
+			}
+			if r, err = nudgeFrame(r, opts); err != nil {
+				return fail(err)
+			}
+			if opts.Clamp {
+				if dest == nil {
+					s, ok := ScreenOf(screens, w)
+					if !ok {
+						return fail(fmt.Errorf("cannot clamp %s %q: window is not on a known screen (see mado rescue)", w.AppName, w.Title))
+					}
+					dest = &s
 				}
-				return affected, err
+				r = ClampFrame(r, *dest)
+			}
+			// only touch what changed, or what was asked for explicitly
+			if position != nil || r.X != w.X || r.Y != w.Y {
+				position = &Point{X: r.X, Y: r.Y}
+			}
+			if size != nil || r.W != w.Width || r.H != w.Height {
+				size = &Size{W: r.W, H: r.H}
+			}
+		}
+
+		if position != nil {
+			if err := svc.MoveWindow(ctx, w.ID, position.X, position.Y); err != nil {
+				return fail(err)
 			}
-			w.X = opts.Position.X
-			w.Y = opts.Position.Y
+			w.X = position.X
+			w.Y = position.Y
 		}
 
-		if opts.Size != nil {
-			if err := svc.ResizeWindow(ctx, w.PID, w.Title, opts.Size.W, opts.Size.H); err != nil {
-				if opts.All && len(affected) > 0 {
-					return affected, &ax.PartialSuccessError{Affected: affected, Cause: err}
+		if size != nil {
+			if err := svc.ResizeWindow(ctx, w.ID, size.W, size.H); err != nil {
+				// the window may already have moved
+				if position != nil {
+					affected = append(affected, w)
 				}
-				return affected, err
+				return fail(err)
 			}
-			w.Width = opts.Size.W
-			w.Height = opts.Size.H
+			w.Width = size.W
+			w.Height = size.H
 		}
 
 		affected = append(affected, w)
 	}
 
-	return affected, nil
+	return targets, affected, nil
 }
 
 // filterForMove filters windows for the move command.
-func filterForMove(windows []ax.Window, opts MoveOptions) []ax.Window {
+func filterForMove(windows []ax.Window, opts MoveOptions, m *Matcher) []ax.Window {
 	result := make([]ax.Window, 0)
 	for _, w := range windows {
-		if opts.AppFilter != "" && !strings.EqualFold(w.AppName, opts.AppFilter) {
+		if !m.Match(w) {
 			continue
 		}
-		if opts.TitleFilter != "" && !strings.Contains(strings.ToLower(w.Title), strings.ToLower(opts.TitleFilter)) {
+		if opts.IDFilter != 0 && w.ID != opts.IDFilter {
 			continue
 		}
 		if opts.ScreenFilter != "" && !MatchScreen(w, opts.ScreenFilter) {
@@ -111,9 +315,24 @@ func buildQuery(opts MoveOptions) string {
 	if opts.AppFilter != "" {
 		parts = append(parts, `--app "`+opts.AppFilter+`"`)
 	}
+	if opts.BundleID != "" {
+		parts = append(parts, `--bundle-id "`+opts.BundleID+`"`)
+	}
 	if opts.TitleFilter != "" {
 		parts = append(parts, `--title "`+opts.TitleFilter+`"`)
 	}
+	if opts.Where != "" {
+		parts = append(parts, "--where '"+opts.Where+"'")
+	}
+	if opts.TitleRegex != "" {
+		parts = append(parts, `--title-regex "`+opts.TitleRegex+`"`)
+	}
+	if opts.NotTitle != "" {
+		parts = append(parts, `--not-title "`+opts.NotTitle+`"`)
+	}
+	if opts.IDFilter != 0 {
+		parts = append(parts, fmt.Sprintf("--id %d", opts.IDFilter))
+	}
 	if opts.ScreenFilter != "" {
 		parts = append(parts, `--screen "`+opts.ScreenFilter+`"`)
 	}
@@ -10,10 +10,10 @@ import (
 )
 
 var moveTestWindows = []ax.Window{
-	{AppName: "Terminal", Title: "peacock — zsh", PID: 100, State: ax.StateNormal, Width: 800, Height: 600},
-	{AppName: "Safari", Title: "GitHub", PID: 200, State: ax.StateNormal, Width: 1440, Height: 900},
-	{AppName: "Safari", Title: "Apple", PID: 200, State: ax.StateNormal, Width: 1200, Height: 800},
-	{AppName: "Code", Title: "README.md", PID: 300, State: ax.StateFullscreen, Width: 1440, Height: 900},
+	{ID: 1, AppName: "Terminal", Title: "peacock — zsh", PID: 100, State: ax.StateNormal, Width: 800, Height: 600},
+	{ID: 2, AppName: "Safari", Title: "GitHub", PID: 200, State: ax.StateNormal, Width: 1440, Height: 900},
+	{ID: 3, AppName: "Safari", Title: "Apple", PID: 200, State: ax.StateNormal, Width: 1200, Height: 800},
+	{ID: 4, AppName: "Code", Title: "README.md", PID: 300, State: ax.StateFullscreen, Width: 1440, Height: 900},
 }
 
 func TestMove_Position(t *testing.T) {
@@ -34,6 +34,26 @@ func TestMove_Position(t *testing.T) {
 	}
 }
 
+func TestMoveTracked_Before(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: moveTestWindows}
+	before, affected, err := window.MoveTracked(context.Background(), svc, window.MoveOptions{
+		AppFilter: "Safari",
+		Size:      &window.Size{W: 1024, H: 768},
+		All:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(before) != 2 || len(affected) != 2 {
+		t.Fatalf("expected 2 windows before and after, got %d and %d", len(before), len(affected))
+	}
+	for i := range before {
+		if before[i].ID != affected[i].ID || before[i].Width != moveTestWindows[i+1].Width || affected[i].Width != 1024 {
+			t.Errorf("window %d: before %+v, after %+v", i, before[i], affected[i])
+		}
+	}
+}
+
 func TestMove_Size(t *testing.T) {
 	svc := &ax.MockWindowService{Windows: moveTestWindows}
 	opts := window.MoveOptions{
@@ -124,6 +144,47 @@ func TestMove_Fullscreen(t *testing.T) {
 	}
 }
 
+func TestMove_FullscreenWithAll(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: []ax.Window{
+			{ID: 1, AppName: "Terminal", Title: "zsh", PID: 100, State: ax.StateNormal},
+			{ID: 2, AppName: "Terminal", Title: "vim", PID: 100, State: ax.StateFullscreen},
+		},
+		MoveErr: errors.New("must not be called"),
+	}
+	_, affected, err := window.MoveTracked(context.Background(), svc, window.MoveOptions{
+		AppFilter: "Terminal",
+		Position:  &window.Point{X: 0, Y: 0},
+		All:       true,
+	})
+	var fsErr *window.FullscreenError
+	if !errors.As(err, &fsErr) {
+		t.Fatalf("expected *window.FullscreenError before any move, got %T: %v", err, err)
+	}
+	if len(affected) != 0 {
+		t.Errorf("expected no affected windows, got %+v", affected)
+	}
+}
+
+func TestMove_ResizeError(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows:   moveTestWindows,
+		ResizeErr: errors.New("AX error"),
+	}
+	_, affected, err := window.MoveTracked(context.Background(), svc, window.MoveOptions{
+		AppFilter: "Terminal",
+		Position:  &window.Point{X: 10, Y: 20},
+		Size:      &window.Size{W: 800, H: 600},
+	})
+	if err == nil {
+		t.Fatal("expected resize error, got nil")
+	}
+	// the window moved before the resize failed
+	if len(affected) != 1 || affected[0].X != 10 || affected[0].Y != 20 {
+		t.Errorf("expected the moved window in affected, got %+v", affected)
+	}
+}
+
 func TestMove_ServiceError(t *testing.T) {
 	svc := &ax.MockWindowService{
 		Windows: moveTestWindows,
@@ -146,7 +207,7 @@ type partialMockService struct {
 	callCount    int
 }
 
-func (m *partialMockService) MoveWindow(_ context.Context, _ uint32, _ string, _, _ int) error {
+func (m *partialMockService) MoveWindow(_ context.Context, _ uint32, _, _ int) error {
 	m.callCount++
 	if m.callCount <= m.successCount {
 		return nil
@@ -206,7 +267,7 @@ func TestMove_TitleFilter(t *testing.T) {
 // moveは常に明示的なターゲット指定が必要なため、ignore listの影響を受けない
 func TestMove_NoIgnoreAppsField(t *testing.T) {
 	windows := []ax.Window{
-		{AppName: "Dock", Title: "Dock", PID: 500, State: ax.StateNormal, Width: 100, Height: 100},
+		{ID: 5, AppName: "Dock", Title: "Dock", PID: 500, State: ax.StateNormal, Width: 100, Height: 100},
 	}
 	svc := &ax.MockWindowService{Windows: windows}
 	opts := window.MoveOptions{
@@ -221,3 +282,153 @@ func TestMove_NoIgnoreAppsField(t *testing.T) {
 		t.Errorf("expected 1 affected, got %d", len(affected))
 	}
 }
+
+func TestMove_IDFilter(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: moveTestWindows}
+	opts := window.MoveOptions{
+		AppFilter: "Safari",
+		IDFilter:  3,
+		Position:  &window.Point{X: 0, Y: 0},
+	}
+	affected, err := window.Move(context.Background(), svc, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(affected) != 1 || affected[0].Title != "Apple" {
+		t.Fatalf("expected only window 3 (Apple), got %+v", affected)
+	}
+}
+
+func TestMove_IDFilterNotFound(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: moveTestWindows}
+	opts := window.MoveOptions{
+		IDFilter: 99,
+		Position: &window.Point{X: 0, Y: 0},
+	}
+	_, err := window.Move(context.Background(), svc, opts)
+	var notFound *ax.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected *ax.NotFoundError, got %T: %v", err, err)
+	}
+	if notFound.Query != "--id 99" {
+		t.Errorf("query = %q, want %q", notFound.Query, "--id 99")
+	}
+}
+
+func TestMove_InvalidTitleRegex(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: moveTestWindows}
+	_, err := window.Move(context.Background(), svc, window.MoveOptions{
+		TitleRegex: "[",
+		Position:   &window.Point{X: 0, Y: 0},
+	})
+	var patErr *window.PatternError
+	if !errors.As(err, &patErr) {
+		t.Fatalf("expected *window.PatternError, got %T: %v", err, err)
+	}
+}
+
+func TestMove_Where(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: moveTestWindows}
+	affected, err := window.Move(context.Background(), svc, window.MoveOptions{
+		Where:    `app == "Safari" && width > 1300`,
+		Position: &window.Point{X: 0, Y: 0},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(affected) != 1 || affected[0].ID != 2 {
+		t.Errorf("expected only window 2, got %+v", affected)
+	}
+}
+
+func TestMove_ToScreen(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Screens: []ax.Screen{
+			{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900, IsPrimary: true},
+			{ID: 2, Name: "External", X: 1440, Y: 0, Width: 2880, Height: 1800},
+		},
+		Windows: []ax.Window{
+			{ID: 1, AppName: "Terminal", Title: "zsh", State: ax.StateNormal, X: 720, Y: 450, Width: 720, Height: 450, ScreenID: 1},
+		},
+	}
+	affected, err := window.Move(context.Background(), svc, window.MoveOptions{AppFilter: "Terminal", ToScreen: "next"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := affected[0]
+	if w.X != 2880 || w.Y != 900 || w.Width != 1440 || w.Height != 900 {
+		t.Errorf("got (%d,%d) %dx%d, want (2880,900) 1440x900", w.X, w.Y, w.Width, w.Height)
+	}
+
+	// with --snap, the window is snapped on the target screen
+	affected, err = window.Move(context.Background(), svc, window.MoveOptions{
+		AppFilter: "Terminal",
+		ToScreen:  "External",
+		Snap:      &window.SnapOptions{Position: "left-half"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w = affected[0]
+	if w.X != 1440 || w.Y != 0 || w.Width != 1440 || w.Height != 1800 {
+		t.Errorf("got (%d,%d) %dx%d, want (1440,0) 1440x1800", w.X, w.Y, w.Width, w.Height)
+	}
+}
+
+func TestMove_ToScreenNotFound(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Screens: []ax.Screen{{ID: 1, Name: "Built-in", Width: 1440, Height: 900}},
+		Windows: moveTestWindows,
+	}
+	_, err := window.Move(context.Background(), svc, window.MoveOptions{AppFilter: "Terminal", ToScreen: "Sidecar"})
+	var notFound *window.ScreenNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected ScreenNotFoundError, got %v", err)
+	}
+}
+
+func TestMove_MoveByResizeBy(t *testing.T) {
+	screens := []ax.Screen{{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900}}
+	windows := []ax.Window{
+		{ID: 1, AppName: "Terminal", Title: "zsh", State: ax.StateNormal, X: 100, Y: 100, Width: 800, Height: 600, ScreenID: 1},
+	}
+	tests := []struct {
+		name string
+		opts window.MoveOptions
+		want window.Rect
+	}{
+		{"move by", window.MoveOptions{MoveBy: &window.Point{X: -50, Y: 20}}, window.Rect{X: 50, Y: 120, W: 800, H: 600}},
+		{"resize by", window.MoveOptions{ResizeBy: &window.Size{W: 100, H: -100}}, window.Rect{X: 100, Y: 100, W: 900, H: 500}},
+		{"resize from left", window.MoveOptions{ResizeBy: &window.Size{W: 100, H: 0}, ResizeFrom: "left"}, window.Rect{X: 0, Y: 100, W: 900, H: 600}},
+		{"resize from center", window.MoveOptions{ResizeBy: &window.Size{W: 100, H: 100}, ResizeFrom: "center"}, window.Rect{X: 50, Y: 50, W: 900, H: 700}},
+		{"move and resize", window.MoveOptions{MoveBy: &window.Point{X: 10, Y: 0}, ResizeBy: &window.Size{W: 0, H: 50}, ResizeFrom: "top"}, window.Rect{X: 110, Y: 50, W: 800, H: 650}},
+		{"clamped", window.MoveOptions{MoveBy: &window.Point{X: 1000, Y: 0}, Clamp: true}, window.Rect{X: 640, Y: 100, W: 800, H: 600}},
+		{"clamped and shrunk", window.MoveOptions{ResizeBy: &window.Size{W: 1000, H: 0}, Clamp: true}, window.Rect{X: 0, Y: 100, W: 1440, H: 600}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &ax.MockWindowService{Windows: windows, Screens: screens}
+			tt.opts.AppFilter = "Terminal"
+			affected, err := window.Move(context.Background(), svc, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := affected[0]
+			if got := w.Frame(); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMove_ResizeByErrors(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: moveTestWindows}
+	_, err := window.Move(context.Background(), svc, window.MoveOptions{AppFilter: "Terminal", ResizeBy: &window.Size{W: -800, H: 0}})
+	if err == nil {
+		t.Error("expected an error when the width drops to zero")
+	}
+	_, err = window.Move(context.Background(), svc, window.MoveOptions{AppFilter: "Terminal", ResizeBy: &window.Size{W: 10, H: 10}, ResizeFrom: "middle"})
+	if err == nil {
+		t.Error("expected an error for an unknown anchor")
+	}
+}
@@ -0,0 +1,260 @@
+package window
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+)
+
+// ScreenInfo describes a screen together with its place in the display arrangement.
+type ScreenInfo struct {
+	ax.Screen
+	// Index is the 1-based position of the screen ordered left-to-right, then top-to-bottom.
+	// It can be used as a screen reference of the form "#N".
+	Index       int `json:"index"`
+	WindowCount int `json:"window_count"`
+	// LeftOf and Above list the IDs of the screens lying entirely to the right of / below this one.
+	LeftOf []uint32 `json:"left_of"`
+	Above  []uint32 `json:"above"`
+}
+
+// ScreensOptions holds the options for the screens command.
+type ScreensOptions struct {
+	IgnoreApps []string
+}
+
+// Screens returns all connected screens in index order, with the number of
+// normal windows (excluding ignored apps) on each.
+func Screens(ctx context.Context, svc ax.WindowService, opts ScreensOptions) ([]ScreenInfo, error) {
+	screens, err := svc.ListScreens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := svc.ListWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return DescribeScreens(screens, filterWindows(windows, ListOptions{IgnoreApps: opts.IgnoreApps}, &Matcher{})), nil
+}
+
+// DescribeScreens builds a ScreenInfo for each screen in index order.
+// Only normal windows are counted.
+func DescribeScreens(screens []ax.Screen, windows []ax.Window) []ScreenInfo {
+	counts := make(map[uint32]int)
+	for _, w := range windows {
+		if w.State == ax.StateNormal {
+			counts[w.ScreenID]++
+		}
+	}
+
+	ordered := OrderScreens(screens)
+	infos := make([]ScreenInfo, len(ordered))
+	for i, s := range ordered {
+		info := ScreenInfo{
+			Screen:      s,
+			Index:       i + 1,
+			WindowCount: counts[s.ID],
+			LeftOf:      []uint32{},
+			Above:       []uint32{},
+		}
+		for _, o := range ordered {
+			if s.X+s.Width <= o.X {
+				info.LeftOf = append(info.LeftOf, o.ID)
+			}
+			if s.Y+s.Height <= o.Y {
+				info.Above = append(info.Above, o.ID)
+			}
+		}
+		infos[i] = info
+	}
+	return infos
+}
+
+// OrderScreens returns a copy of screens ordered left-to-right, then top-to-bottom.
+// Screens at the same origin are ordered by ID so the order is stable.
+func OrderScreens(screens []ax.Screen) []ax.Screen {
+	ordered := make([]ax.Screen, len(screens))
+	copy(ordered, screens)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.ID < b.ID
+	})
+	return ordered
+}
+
+// ParseScreenIndex parses a screen index reference of the form "#N" (N >= 1).
+func ParseScreenIndex(ref string) (int, bool) {
+	rest, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(rest)
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	return n, true
+}
+
+// IsScreenIndex reports whether ref looks like a screen index reference ("#...").
+// Use ParseScreenIndex to check that it is well-formed.
+func IsScreenIndex(ref string) bool {
+	return strings.HasPrefix(ref, "#")
+}
+
+// ResolveScreenIndex rewrites a "#N" screen reference to the ID of the N-th screen
+// so that it can be used with MatchScreen. Other references are returned unchanged,
+// as are indexes that do not refer to a connected screen.
+func ResolveScreenIndex(screens []ax.Screen, ref string) string {
+	n, ok := ParseScreenIndex(ref)
+	if !ok {
+		return ref
+	}
+	ordered := OrderScreens(screens)
+	if n > len(ordered) {
+		return ref
+	}
+	return strconv.FormatUint(uint64(ordered[n-1].ID), 10)
+}
+
+// Relative screen references accepted by TargetScreen.
+const (
+	ScreenNext  = "next"  // the next screen in index order, wrapping around
+	ScreenPrev  = "prev"  // the previous screen in index order, wrapping around
+	ScreenLeft  = "left"  // the nearest screen entirely to the left
+	ScreenRight = "right" // the nearest screen entirely to the right
+)
+
+// ScreenNotFoundError is returned when a screen reference does not resolve to a
+// connected screen. From is set for relative references.
+type ScreenNotFoundError struct {
+	Ref  string
+	From string
+}
+
+func (e *ScreenNotFoundError) Error() string {
+	if e.From != "" {
+		return fmt.Sprintf("no screen %s of %q", e.Ref, e.From)
+	}
+	return fmt.Sprintf("no screen matches %q", e.Ref)
+}
+
+// IsRelativeScreen reports whether ref is one of next, prev, left and right.
+func IsRelativeScreen(ref string) bool {
+	switch strings.ToLower(ref) {
+	case ScreenNext, ScreenPrev, ScreenLeft, ScreenRight:
+		return true
+	}
+	return false
+}
+
+// TargetScreen resolves ref against from: a relative reference (next, prev, left,
+// right) or anything FindScreen accepts.
+func TargetScreen(screens []ax.Screen, from ax.Screen, ref string) (ax.Screen, error) {
+	ordered := OrderScreens(screens)
+	switch strings.ToLower(ref) {
+	case ScreenNext, ScreenPrev:
+		for i, s := range ordered {
+			if s.ID != from.ID {
+				continue
+			}
+			step := 1
+			if strings.EqualFold(ref, ScreenPrev) {
+				step = len(ordered) - 1
+			}
+			return ordered[(i+step)%len(ordered)], nil
+		}
+		return ax.Screen{}, &ScreenNotFoundError{Ref: ref, From: from.Name}
+	case ScreenLeft, ScreenRight:
+		left := strings.EqualFold(ref, ScreenLeft)
+		var best ax.Screen
+		bestGap, bestDY := -1, 0
+		for _, s := range ordered {
+			gap := s.X - (from.X + from.Width)
+			if left {
+				gap = from.X - (s.X + s.Width)
+			}
+			if gap < 0 {
+				continue
+			}
+			// prefer the closest screen, then the one best aligned vertically
+			dy := abs((s.Y + s.Height/2) - (from.Y + from.Height/2))
+			if bestGap < 0 || gap < bestGap || gap == bestGap && dy < bestDY {
+				best, bestGap, bestDY = s, gap, dy
+			}
+		}
+		if bestGap < 0 {
+			return ax.Screen{}, &ScreenNotFoundError{Ref: "to the " + strings.ToLower(ref), From: from.Name}
+		}
+		return best, nil
+	}
+	if s, ok := FindScreen(screens, ref); ok {
+		return s, nil
+	}
+	return ax.Screen{}, &ScreenNotFoundError{Ref: ref}
+}
+
+// TranslateFrame maps r from screen from to screen to, scaling its offset and size
+// by the ratio of the screen sizes, then clamps it to fit on to.
+func TranslateFrame(r Rect, from, to ax.Screen) Rect {
+	sx := float64(to.Width) / float64(from.Width)
+	sy := float64(to.Height) / float64(from.Height)
+	out := Rect{
+		W: min(int(math.Round(float64(r.W)*sx)), to.Width),
+		H: min(int(math.Round(float64(r.H)*sy)), to.Height),
+	}
+	out.X = to.X + int(math.Round(float64(r.X-from.X)*sx))
+	out.Y = to.Y + int(math.Round(float64(r.Y-from.Y)*sy))
+	return ClampFrame(out, to)
+}
+
+// ClampFrame shrinks r to fit on s and moves it the shortest distance that puts it
+// entirely on s.
+func ClampFrame(r Rect, s ax.Screen) Rect {
+	r.W, r.H = min(r.W, s.Width), min(r.H, s.Height)
+	r.X = min(max(r.X, s.X), s.X+s.Width-r.W)
+	r.Y = min(max(r.Y, s.Y), s.Y+s.Height-r.H)
+	return r
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Reference areas of a screen for snap positions, relative geometry and clamping.
+const (
+	AreaFrame   = "frame"   // the full display bounds (the default)
+	AreaVisible = "visible" // the usable area without the menu bar and the Dock
+)
+
+// ValidateArea returns an error when area is not a reference area ("" means AreaFrame).
+func ValidateArea(area string) error {
+	switch area {
+	case "", AreaFrame, AreaVisible:
+		return nil
+	}
+	return fmt.Errorf("unknown screen area %q (valid: %s, %s)", area, AreaFrame, AreaVisible)
+}
+
+// ScreenArea returns s with its bounds replaced by the reference area, so that
+// frames computed from it stay within that area.
+func ScreenArea(s ax.Screen, area string) ax.Screen {
+	if area == AreaVisible {
+		v := s.Visible()
+		s.X, s.Y, s.Width, s.Height = v.X, v.Y, v.W, v.H
+	}
+	return s
+}
@@ -0,0 +1,194 @@
+package window_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+// Layout: External (left) | Built-in (right), with a projector below Built-in.
+var arrangedScreens = []ax.Screen{
+	{ID: 69678592, Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900, IsPrimary: true},
+	{ID: 555, Name: "Projector", X: 0, Y: 900, Width: 1920, Height: 1080},
+	{ID: 12345678, Name: "External", X: -2560, Y: 0, Width: 2560, Height: 1440},
+}
+
+func TestOrderScreens(t *testing.T) {
+	ordered := window.OrderScreens(arrangedScreens)
+	got := []uint32{ordered[0].ID, ordered[1].ID, ordered[2].ID}
+	want := []uint32{12345678, 69678592, 555}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	// input must not be reordered
+	if arrangedScreens[0].ID != 69678592 {
+		t.Error("OrderScreens modified its input")
+	}
+}
+
+func TestDescribeScreens(t *testing.T) {
+	windows := []ax.Window{
+		{AppName: "Terminal", State: ax.StateNormal, ScreenID: 69678592},
+		{AppName: "Safari", State: ax.StateNormal, ScreenID: 69678592},
+		{AppName: "Code", State: ax.StateNormal, ScreenID: 12345678},
+		{AppName: "Finder", State: ax.StateMinimized},
+	}
+	infos := window.DescribeScreens(arrangedScreens, windows)
+	if len(infos) != 3 {
+		t.Fatalf("expected 3 screens, got %d", len(infos))
+	}
+
+	ext, builtin, proj := infos[0], infos[1], infos[2]
+	if ext.Index != 1 || builtin.Index != 2 || proj.Index != 3 {
+		t.Errorf("indexes = %d,%d,%d, want 1,2,3", ext.Index, builtin.Index, proj.Index)
+	}
+	if ext.WindowCount != 1 || builtin.WindowCount != 2 || proj.WindowCount != 0 {
+		t.Errorf("window counts = %d,%d,%d, want 1,2,0", ext.WindowCount, builtin.WindowCount, proj.WindowCount)
+	}
+	if !reflect.DeepEqual(ext.LeftOf, []uint32{69678592, 555}) {
+		t.Errorf("External.LeftOf = %v", ext.LeftOf)
+	}
+	if !reflect.DeepEqual(builtin.Above, []uint32{555}) {
+		t.Errorf("Built-in.Above = %v", builtin.Above)
+	}
+	if len(builtin.LeftOf) != 0 || len(proj.LeftOf) != 0 || len(proj.Above) != 0 {
+		t.Errorf("unexpected relationships: %+v %+v", builtin, proj)
+	}
+}
+
+func TestScreens_IgnoreApps(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Screens: arrangedScreens,
+		Windows: []ax.Window{
+			{AppName: "Terminal", State: ax.StateNormal, ScreenID: 69678592},
+			{AppName: "Dock", State: ax.StateNormal, ScreenID: 69678592},
+		},
+	}
+	infos, err := window.Screens(context.Background(), svc, window.ScreensOptions{IgnoreApps: []string{"dock"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if infos[1].WindowCount != 1 {
+		t.Errorf("Built-in window count = %d, want 1", infos[1].WindowCount)
+	}
+}
+
+func TestFindScreen_Index(t *testing.T) {
+	tests := []struct {
+		ref    string
+		wantID uint32
+		wantOK bool
+	}{
+		{"#1", 12345678, true},
+		{"#3", 555, true},
+		{"#4", 0, false},
+		{"#0", 0, false},
+		{"projector", 555, true},
+		{"69678592", 69678592, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			s, ok := window.FindScreen(arrangedScreens, tt.ref)
+			if ok != tt.wantOK || s.ID != tt.wantID {
+				t.Errorf("FindScreen(%q) = (%d, %v), want (%d, %v)", tt.ref, s.ID, ok, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestResolveScreenIndex(t *testing.T) {
+	if got := window.ResolveScreenIndex(arrangedScreens, "#2"); got != "69678592" {
+		t.Errorf("ResolveScreenIndex(#2) = %q", got)
+	}
+	if got := window.ResolveScreenIndex(arrangedScreens, "External"); got != "External" {
+		t.Errorf("non-index references must be returned unchanged, got %q", got)
+	}
+	if got := window.ResolveScreenIndex(nil, "#1"); got != "#1" {
+		t.Errorf("unresolvable index must be returned unchanged, got %q", got)
+	}
+}
+
+func TestTargetScreen(t *testing.T) {
+	byName := func(name string) ax.Screen {
+		s, _ := window.FindScreen(arrangedScreens, name)
+		return s
+	}
+	tests := []struct {
+		from    string
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"Built-in", "next", "Projector", false},
+		{"External", "prev", "Projector", false},
+		{"Projector", "next", "External", false},
+		{"Built-in", "left", "External", false},
+		// Built-in and Projector both touch External; Built-in is better aligned
+		{"External", "right", "Built-in", false},
+		{"Built-in", "RIGHT", "", true},
+		{"Built-in", "#3", "Projector", false},
+		{"Built-in", "External", "External", false},
+		{"Built-in", "Sidecar", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" "+tt.ref, func(t *testing.T) {
+			s, err := window.TargetScreen(arrangedScreens, byName(tt.from), tt.ref)
+			if tt.wantErr {
+				var notFound *window.ScreenNotFoundError
+				if !errors.As(err, &notFound) {
+					t.Fatalf("expected ScreenNotFoundError, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.Name != tt.want {
+				t.Errorf("got %q, want %q", s.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateFrame(t *testing.T) {
+	from := ax.Screen{X: 0, Y: 0, Width: 1440, Height: 900}
+	to := ax.Screen{X: 1440, Y: 0, Width: 2880, Height: 1800}
+	// the right half of a screen stays the right half of the other one
+	got := window.TranslateFrame(window.Rect{X: 720, Y: 0, W: 720, H: 900}, from, to)
+	if want := (window.Rect{X: 2880, Y: 0, W: 1440, H: 1800}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	// a frame hanging off the bottom-right corner is clamped onto the target
+	got = window.TranslateFrame(window.Rect{X: 1000, Y: 700, W: 800, H: 400}, from, from)
+	if want := (window.Rect{X: 640, Y: 500, W: 800, H: 400}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestScreenArea(t *testing.T) {
+	s := ax.Screen{ID: 1, X: 0, Y: 0, Width: 1440, Height: 900,
+		VisibleFrame: ax.Rect{X: 0, Y: 25, W: 1440, H: 805}}
+	if got := window.ScreenArea(s, window.AreaFrame); got != s {
+		t.Errorf("frame area changed the screen: %+v", got)
+	}
+	got := window.ScreenArea(s, window.AreaVisible)
+	if got.X != 0 || got.Y != 25 || got.Width != 1440 || got.Height != 805 || got.ID != 1 {
+		t.Errorf("visible area = %+v", got)
+	}
+	// without a visible frame the full bounds are used
+	bare := ax.Screen{ID: 2, Width: 1920, Height: 1080}
+	if got := window.ScreenArea(bare, window.AreaVisible); got != bare {
+		t.Errorf("visible area without visible frame = %+v", got)
+	}
+
+	if err := window.ValidateArea(""); err != nil {
+		t.Errorf("empty area: %v", err)
+	}
+	if err := window.ValidateArea("dock"); err == nil {
+		t.Error("expected error for unknown area")
+	}
+}
@@ -0,0 +1,100 @@
+package window
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+)
+
+// Screen selectors pick a screen by its role in the current arrangement rather than
+// by name or ID, so that the same reference works with any set of displays. The
+// positions leftmost, rightmost, topmost and bottommost are selectors as well.
+const (
+	SelectorPrimary    = "primary"     // the primary screen (with the menu bar)
+	SelectorNotPrimary = "not-primary" // any screen but the primary one
+	SelectorLargest    = "largest"     // the screen with the largest area
+	SelectorSmallest   = "smallest"    // the screen with the smallest area
+)
+
+// ScreenSelectors returns the selectors accepted by ResolveScreenSelector.
+func ScreenSelectors() []string {
+	return []string{
+		SelectorPrimary, SelectorNotPrimary, SelectorLargest, SelectorSmallest,
+		PositionLeftmost, PositionRightmost, PositionTopmost, PositionBottommost,
+	}
+}
+
+// IsScreenSelector reports whether ref is one of ScreenSelectors (case-insensitive).
+func IsScreenSelector(ref string) bool {
+	for _, s := range ScreenSelectors() {
+		if strings.EqualFold(ref, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveScreenSelector rewrites a selector to the ID of the screen it picks on
+// screens, so that it can be used with MatchScreen and FindScreen. not-primary
+// becomes "!ID" of the primary screen, which matches every other screen. Ties go to
+// the first screen in index order. Other references are returned unchanged, as are
+// selectors that pick no screen.
+func ResolveScreenSelector(screens []ax.Screen, ref string) string {
+	sel := strings.ToLower(ref)
+	ordered := OrderScreens(screens)
+	var (
+		picked ax.Screen
+		found  bool
+	)
+	switch sel {
+	case SelectorPrimary, SelectorNotPrimary:
+		for _, s := range ordered {
+			if s.IsPrimary {
+				picked, found = s, true
+				break
+			}
+		}
+	case SelectorLargest, SelectorSmallest:
+		for _, s := range ordered {
+			area := s.Width * s.Height
+			if !found ||
+				(sel == SelectorLargest && area > picked.Width*picked.Height) ||
+				(sel == SelectorSmallest && area < picked.Width*picked.Height) {
+				picked, found = s, true
+			}
+		}
+	case PositionLeftmost, PositionRightmost, PositionTopmost, PositionBottommost:
+		for _, s := range ordered {
+			if atEdge(ordered, s, sel) {
+				picked, found = s, true
+				break
+			}
+		}
+	}
+	if !found {
+		return ref
+	}
+	id := strconv.FormatUint(uint64(picked.ID), 10)
+	if sel == SelectorNotPrimary {
+		return "!" + id
+	}
+	return id
+}
+
+// ResolveScreenRef rewrites a screen reference that depends on the connected screens
+// (an alias, a selector or a "#N" index, in that order of precedence) to the ID of
+// the screen it refers to. Names, IDs and relative references are returned unchanged.
+func ResolveScreenRef(screens []ax.Screen, aliases []ScreenAlias, ref string) string {
+	if _, ok := FindScreenAlias(aliases, ref); ok {
+		return ResolveScreenAlias(screens, aliases, ref)
+	}
+	return ResolveScreenIndex(screens, ResolveScreenSelector(screens, ref))
+}
+
+// NeedsScreens reports whether ResolveScreenRef needs the connected screens to
+// resolve ref.
+func NeedsScreens(aliases []ScreenAlias, ref string) bool {
+	_, alias := FindScreenAlias(aliases, ref)
+	return alias || IsScreenSelector(ref) || IsScreenIndex(ref)
+}
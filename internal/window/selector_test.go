@@ -0,0 +1,136 @@
+package window_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+func TestResolveScreenRef(t *testing.T) {
+	screens := []ax.Screen{
+		{ID: 69678592, Name: "Built-in", X: 0, Y: 0, Width: 1440, Height: 900, IsPrimary: true},
+		{ID: 555, Name: "Projector", X: 0, Y: 900, Width: 1920, Height: 1080},
+		{ID: 12345678, Name: "DELL U2720Q", X: -2560, Y: 0, Width: 2560, Height: 1440},
+	}
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"primary", "69678592"},
+		{"Primary", "69678592"},
+		{"not-primary", "!69678592"},
+		{"largest", "12345678"},
+		{"smallest", "69678592"},
+		{"leftmost", "12345678"},
+		{"rightmost", "555"},
+		{"topmost", "12345678"},
+		{"bottommost", "555"},
+		{"#1", "12345678"},
+		// aliases take precedence over selectors
+		{"main", "69678592"},
+		{"left", "12345678"},
+		// plain and relative references are left alone
+		{"Projector", "Projector"},
+		{"next", "next"},
+	}
+	for _, tt := range tests {
+		if got := window.ResolveScreenRef(screens, testAliases, tt.ref); got != tt.want {
+			t.Errorf("ResolveScreenRef(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+
+	// nothing to select without a primary screen
+	if got := window.ResolveScreenSelector(screens[1:], "not-primary"); got != "not-primary" {
+		t.Errorf("ResolveScreenSelector(not-primary) without primary = %q, want it unchanged", got)
+	}
+}
+
+func TestFindScreen_Selector(t *testing.T) {
+	s, ok := window.FindScreen(snapScreens, "largest")
+	if !ok || s.ID != 2 {
+		t.Errorf("FindScreen(largest) = %d, %v, want 2", s.ID, ok)
+	}
+	s, ok = window.FindScreen(snapScreens, "!1")
+	if !ok || s.ID != 2 {
+		t.Errorf("FindScreen(!1) = %d, %v, want 2", s.ID, ok)
+	}
+	if _, ok := window.FindScreen(snapScreens[:1], "not-primary"); ok {
+		t.Error("FindScreen(not-primary) with a single screen: expected no screen")
+	}
+}
+
+func TestList_ScreenSelector(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: []ax.Window{
+			{AppName: "Terminal", Title: "zsh", PID: 100, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
+			{AppName: "Safari", Title: "GitHub", PID: 200, State: ax.StateNormal, ScreenID: 2, ScreenName: "External"},
+		},
+		Screens: snapScreens,
+	}
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		{"primary", "Terminal"},
+		{"not-primary", "Safari"},
+		{"largest", "Safari"},
+		{"#1", "Terminal"},
+	}
+	for _, tt := range tests {
+		got, err := window.List(context.Background(), svc, window.ListOptions{ScreenFilter: tt.filter})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].AppName != tt.want {
+			t.Errorf("--screen %s: expected only %s, got %+v", tt.filter, tt.want, got)
+		}
+	}
+}
+
+func TestMove_ToScreenSelector(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: []ax.Window{
+			{AppName: "Terminal", Title: "zsh", PID: 100, State: ax.StateNormal, Width: 800, Height: 600, ScreenID: 1},
+		},
+		Screens: snapScreens,
+	}
+	affected, err := window.Move(context.Background(), svc, window.MoveOptions{
+		AppFilter: "Terminal",
+		ToScreen:  "not-primary",
+		Snap:      &window.SnapOptions{Position: "maximize"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := affected[0]; w.X != 1920 || w.Y != -200 || w.Width != 2560 || w.Height != 1440 {
+		t.Errorf("Terminal frame = (%d,%d %dx%d), want (1920,-200 2560x1440)", w.X, w.Y, w.Width, w.Height)
+	}
+}
+
+func TestMove_NotFoundKeepsScreenRef(t *testing.T) {
+	svc := &ax.MockWindowService{
+		Windows: []ax.Window{
+			{AppName: "Terminal", Title: "zsh", PID: 100, State: ax.StateNormal, ScreenID: 1, ScreenName: "Built-in"},
+		},
+		Screens: snapScreens,
+	}
+	for _, ref := range []string{"not-primary", "laptop"} {
+		_, err := window.Move(context.Background(), svc, window.MoveOptions{
+			AppFilter:     "Terminal",
+			ScreenFilter:  ref,
+			ScreenAliases: []window.ScreenAlias{{Alias: "laptop", Name: "External"}},
+			Position:      &window.Point{X: 0, Y: 0},
+		})
+		var notFound *ax.NotFoundError
+		if !errors.As(err, &notFound) {
+			t.Fatalf("--screen %s: expected NotFoundError, got %v", ref, err)
+		}
+		if want := `--screen "` + ref + `"`; !strings.Contains(notFound.Query, want) {
+			t.Errorf("query = %q, want it to contain %s", notFound.Query, want)
+		}
+	}
+}
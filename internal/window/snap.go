@@ -0,0 +1,129 @@
+package window
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+)
+
+// Rect is a window frame in global coordinates.
+type Rect = ax.Rect
+
+// SnapOptions holds the parameters for snapping a window to a named position.
+type SnapOptions struct {
+	Position string // named snap position, e.g. "left-half" (see SnapPositions)
+	Gap      int    // pixels between adjacent snapped windows
+	Margin   int    // pixels between the screen edge and snapped windows
+}
+
+// snapCell describes a snap position as a cell span on a cols×rows grid.
+type snapCell struct {
+	cols, rows int
+	col, row   int
+	colSpan    int
+	rowSpan    int
+}
+
+// snapCenter is handled separately: it keeps the window size and centers it.
+const snapCenter = "center"
+
+var snapCells = map[string]snapCell{
+	"maximize":             {1, 1, 0, 0, 1, 1},
+	"left-half":            {2, 1, 0, 0, 1, 1},
+	"right-half":           {2, 1, 1, 0, 1, 1},
+	"top-half":             {1, 2, 0, 0, 1, 1},
+	"bottom-half":          {1, 2, 0, 1, 1, 1},
+	"left-third":           {3, 1, 0, 0, 1, 1},
+	"center-third":         {3, 1, 1, 0, 1, 1},
+	"right-third":          {3, 1, 2, 0, 1, 1},
+	"left-two-thirds":      {3, 1, 0, 0, 2, 1},
+	"right-two-thirds":     {3, 1, 1, 0, 2, 1},
+	"top-left-quarter":     {2, 2, 0, 0, 1, 1},
+	"top-right-quarter":    {2, 2, 1, 0, 1, 1},
+	"bottom-left-quarter":  {2, 2, 0, 1, 1, 1},
+	"bottom-right-quarter": {2, 2, 1, 1, 1, 1},
+}
+
+// SnapPositions returns all valid snap position names in sorted order.
+func SnapPositions() []string {
+	names := make([]string, 0, len(snapCells)+1)
+	for name := range snapCells {
+		names = append(names, name)
+	}
+	names = append(names, snapCenter)
+	sort.Strings(names)
+	return names
+}
+
+// ValidateSnap returns an error when opts is not a usable snap specification.
+func ValidateSnap(opts SnapOptions) error {
+	if _, ok := snapCells[opts.Position]; !ok && opts.Position != snapCenter {
+		return fmt.Errorf("unknown snap position %q (valid: %s)", opts.Position, strings.Join(SnapPositions(), ", "))
+	}
+	if opts.Gap < 0 {
+		return fmt.Errorf("gap must be >= 0")
+	}
+	if opts.Margin < 0 {
+		return fmt.Errorf("margin must be >= 0")
+	}
+	return nil
+}
+
+// SnapFrame computes the target frame for w snapped to opts.Position on screen s.
+// "center" keeps the window size (shrunk to fit within the margins) and centers it.
+func SnapFrame(w ax.Window, s ax.Screen, opts SnapOptions) (Rect, error) {
+	if err := ValidateSnap(opts); err != nil {
+		return Rect{}, err
+	}
+
+	areaX := s.X + opts.Margin
+	areaY := s.Y + opts.Margin
+	areaW := s.Width - 2*opts.Margin
+	areaH := s.Height - 2*opts.Margin
+	if areaW <= 0 || areaH <= 0 {
+		return Rect{}, fmt.Errorf("margin %d leaves no usable area on screen %q", opts.Margin, s.Name)
+	}
+
+	if opts.Position == snapCenter {
+		width := min(w.Width, areaW)
+		height := min(w.Height, areaH)
+		return Rect{
+			X: areaX + (areaW-width)/2,
+			Y: areaY + (areaH-height)/2,
+			W: width,
+			H: height,
+		}, nil
+	}
+
+	c := snapCells[opts.Position]
+	x0 := GridEdge(c.col, c.cols, areaW, opts.Gap)
+	x1 := GridEdge(c.col+c.colSpan, c.cols, areaW, opts.Gap)
+	y0 := GridEdge(c.row, c.rows, areaH, opts.Gap)
+	y1 := GridEdge(c.row+c.rowSpan, c.rows, areaH, opts.Gap)
+
+	r := Rect{X: areaX + x0, Y: areaY + y0, W: x1 - x0 - opts.Gap, H: y1 - y0 - opts.Gap}
+	if r.W <= 0 || r.H <= 0 {
+		return Rect{}, fmt.Errorf("gap %d leaves no usable area for %q on screen %q", opts.Gap, opts.Position, s.Name)
+	}
+	return r, nil
+}
+
+// GridEdge returns the offset of grid line i out of n cells across length,
+// where cells are separated by gap. Line n lies at length+gap so every cell
+// can be computed as [GridEdge(i), GridEdge(i+span)-gap).
+func GridEdge(i, n, length, gap int) int {
+	return int(math.Round(float64(i) * float64(length+gap) / float64(n)))
+}
+
+// ScreenOf returns the screen w is currently on.
+func ScreenOf(screens []ax.Screen, w ax.Window) (ax.Screen, bool) {
+	for _, s := range screens {
+		if s.ID == w.ScreenID {
+			return s, true
+		}
+	}
+	return ax.Screen{}, false
+}
@@ -0,0 +1,162 @@
+package window_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+var snapScreens = []ax.Screen{
+	{ID: 1, Name: "Built-in", X: 0, Y: 0, Width: 1920, Height: 1080, IsPrimary: true},
+	{ID: 2, Name: "External", X: 1920, Y: -200, Width: 2560, Height: 1440},
+}
+
+func TestSnapFrame(t *testing.T) {
+	w := ax.Window{AppName: "Terminal", Width: 800, Height: 600}
+	screen := snapScreens[0]
+	tests := []struct {
+		name string
+		opts window.SnapOptions
+		want window.Rect
+	}{
+		{"maximize", window.SnapOptions{Position: "maximize"}, window.Rect{X: 0, Y: 0, W: 1920, H: 1080}},
+		{"left-half", window.SnapOptions{Position: "left-half"}, window.Rect{X: 0, Y: 0, W: 960, H: 1080}},
+		{"right-half", window.SnapOptions{Position: "right-half"}, window.Rect{X: 960, Y: 0, W: 960, H: 1080}},
+		{"bottom-half", window.SnapOptions{Position: "bottom-half"}, window.Rect{X: 0, Y: 540, W: 1920, H: 540}},
+		{"center-third", window.SnapOptions{Position: "center-third"}, window.Rect{X: 640, Y: 0, W: 640, H: 1080}},
+		{"right-two-thirds", window.SnapOptions{Position: "right-two-thirds"}, window.Rect{X: 640, Y: 0, W: 1280, H: 1080}},
+		{"top-right-quarter", window.SnapOptions{Position: "top-right-quarter"}, window.Rect{X: 960, Y: 0, W: 960, H: 540}},
+		{"center", window.SnapOptions{Position: "center"}, window.Rect{X: 560, Y: 240, W: 800, H: 600}},
+		{
+			"left-half with gap and margin",
+			window.SnapOptions{Position: "left-half", Gap: 10, Margin: 20},
+			window.Rect{X: 20, Y: 20, W: 935, H: 1040},
+		},
+		{
+			"right-half with gap and margin",
+			window.SnapOptions{Position: "right-half", Gap: 10, Margin: 20},
+			window.Rect{X: 965, Y: 20, W: 935, H: 1040},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := window.SnapFrame(w, screen, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("SnapFrame = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapFrame_SecondaryScreen(t *testing.T) {
+	got, err := window.SnapFrame(ax.Window{}, snapScreens[1], window.SnapOptions{Position: "bottom-left-quarter"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := window.Rect{X: 1920, Y: 520, W: 1280, H: 720}
+	if got != want {
+		t.Errorf("SnapFrame = %+v, want %+v", got, want)
+	}
+}
+
+func TestSnapFrame_CenterLargerThanScreen(t *testing.T) {
+	w := ax.Window{Width: 3000, Height: 2000}
+	got, err := window.SnapFrame(w, snapScreens[0], window.SnapOptions{Position: "center", Margin: 40})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := window.Rect{X: 40, Y: 40, W: 1840, H: 1000}
+	if got != want {
+		t.Errorf("SnapFrame = %+v, want %+v", got, want)
+	}
+}
+
+func TestValidateSnap(t *testing.T) {
+	if err := window.ValidateSnap(window.SnapOptions{Position: "left-half"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := window.ValidateSnap(window.SnapOptions{Position: "left-quarter"}); err == nil {
+		t.Error("expected error for unknown snap position")
+	}
+	if err := window.ValidateSnap(window.SnapOptions{Position: "maximize", Gap: -1}); err == nil {
+		t.Error("expected error for negative gap")
+	}
+}
+
+func TestMove_Snap(t *testing.T) {
+	windows := []ax.Window{
+		{AppName: "Terminal", Title: "zsh", PID: 100, State: ax.StateNormal, Width: 800, Height: 600, ScreenID: 1},
+		{AppName: "Safari", Title: "GitHub", PID: 200, State: ax.StateNormal, Width: 800, Height: 600, ScreenID: 2},
+	}
+	svc := &ax.MockWindowService{Windows: windows, Screens: snapScreens}
+	opts := window.MoveOptions{
+		All:  true,
+		Snap: &window.SnapOptions{Position: "left-half"},
+	}
+	affected, err := window.Move(context.Background(), svc, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(affected) != 2 {
+		t.Fatalf("expected 2 affected, got %d", len(affected))
+	}
+	// Each window snaps on its own screen.
+	if affected[0].X != 0 || affected[0].Width != 960 {
+		t.Errorf("Terminal frame = (%d, %dx%d)", affected[0].X, affected[0].Width, affected[0].Height)
+	}
+	if affected[1].X != 1920 || affected[1].Y != -200 || affected[1].Width != 1280 || affected[1].Height != 1440 {
+		t.Errorf("Safari frame = (%d,%d %dx%d)", affected[1].X, affected[1].Y, affected[1].Width, affected[1].Height)
+	}
+}
+
+func TestMove_SnapUnknownScreen(t *testing.T) {
+	windows := []ax.Window{
+		{AppName: "Terminal", Title: "zsh", PID: 100, State: ax.StateNormal, ScreenID: 99},
+	}
+	svc := &ax.MockWindowService{Windows: windows, Screens: snapScreens}
+	_, err := window.Move(context.Background(), svc, window.MoveOptions{
+		AppFilter: "Terminal",
+		Snap:      &window.SnapOptions{Position: "maximize"},
+	})
+	if err == nil {
+		t.Fatal("expected error for window on unknown screen")
+	}
+}
+
+func TestMove_SnapScreensError(t *testing.T) {
+	svc := &ax.MockWindowService{Windows: moveTestWindows, ScreensErr: errors.New("display error")}
+	_, err := window.Move(context.Background(), svc, window.MoveOptions{
+		AppFilter: "Terminal",
+		Snap:      &window.SnapOptions{Position: "maximize"},
+	})
+	if err == nil {
+		t.Fatal("expected ListScreens error to propagate")
+	}
+}
+
+func TestMove_SnapVisibleArea(t *testing.T) {
+	windows := []ax.Window{
+		{AppName: "Terminal", Title: "zsh", PID: 100, State: ax.StateNormal, Width: 800, Height: 600, ScreenID: 1},
+	}
+	screens := []ax.Screen{{ID: 1, Name: "Built-in", Width: 1920, Height: 1080, IsPrimary: true,
+		VisibleFrame: ax.Rect{X: 0, Y: 25, W: 1920, H: 985}}}
+	svc := &ax.MockWindowService{Windows: windows, Screens: screens}
+	affected, err := window.Move(context.Background(), svc, window.MoveOptions{
+		AppFilter: "Terminal",
+		Snap:      &window.SnapOptions{Position: "left-half"},
+		Area:      window.AreaVisible,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// below the menu bar and above the Dock
+	if w := affected[0]; w.X != 0 || w.Y != 25 || w.Width != 960 || w.Height != 985 {
+		t.Errorf("Terminal frame = (%d,%d %dx%d), want (0,25 960x985)", w.X, w.Y, w.Width, w.Height)
+	}
+}
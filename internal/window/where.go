@@ -0,0 +1,357 @@
+package window
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+)
+
+// WhereError reports a syntax or type error in a --where expression.
+// Column is the 1-based byte offset of the offending token in Expr.
+type WhereError struct {
+	Expr   string
+	Column int
+	Msg    string
+}
+
+func (e *WhereError) Error() string {
+	return fmt.Sprintf("invalid where expression %q at column %d: %s", e.Expr, e.Column, e.Msg)
+}
+
+// whereField describes a window field that can be used in a where expression.
+// Exactly one of str and num is set.
+type whereField struct {
+	str func(ax.Window) string
+	num func(ax.Window) int
+}
+
+// whereFields maps field names (the JSON names of ax.Window, plus the app and screen
+// shorthands) to their accessors.
+var whereFields = map[string]whereField{
+	"id":          {num: func(w ax.Window) int { return int(w.ID) }},
+	"app":         {str: func(w ax.Window) string { return w.AppName }},
+	"app_name":    {str: func(w ax.Window) string { return w.AppName }},
+	"bundle_id":   {str: func(w ax.Window) string { return w.BundleID }},
+	"title":       {str: func(w ax.Window) string { return w.Title }},
+	"pid":         {num: func(w ax.Window) int { return int(w.PID) }},
+	"x":           {num: func(w ax.Window) int { return w.X }},
+	"y":           {num: func(w ax.Window) int { return w.Y }},
+	"width":       {num: func(w ax.Window) int { return w.Width }},
+	"height":      {num: func(w ax.Window) int { return w.Height }},
+	"state":       {str: func(w ax.Window) string { return string(w.State) }},
+	"screen":      {str: func(w ax.Window) string { return w.ScreenName }},
+	"screen_name": {str: func(w ax.Window) string { return w.ScreenName }},
+	"screen_id":   {num: func(w ax.Window) int { return int(w.ScreenID) }},
+	"desktop":     {num: func(w ax.Window) int { return w.Desktop }},
+}
+
+var windowStates = []string{
+	string(ax.StateNormal), string(ax.StateMinimized), string(ax.StateFullscreen), string(ax.StateHidden),
+}
+
+// --- where expression parser ---
+//
+// Grammar:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | "(" or ")" | compare
+//	compare = field op value
+//	op      = "==" | "!=" | "<" | "<=" | ">" | ">=" | "=~" | "!~"
+//	value   = '"' string '"' | "'" string "'" | integer
+//
+// String fields compare case-insensitively with == and !=, and match a regular
+// expression (RE2 syntax) with =~ and !~. Numeric fields support all comparisons but =~ and !~.
+
+type whereNode interface {
+	match(w ax.Window) bool
+}
+
+type andNode struct{ l, r whereNode }
+
+func (n andNode) match(w ax.Window) bool { return n.l.match(w) && n.r.match(w) }
+
+type orNode struct{ l, r whereNode }
+
+func (n orNode) match(w ax.Window) bool { return n.l.match(w) || n.r.match(w) }
+
+type notNode struct{ x whereNode }
+
+func (n notNode) match(w ax.Window) bool { return !n.x.match(w) }
+
+type cmpNode struct {
+	field whereField
+	op    string
+	str   string
+	num   int
+	re    *regexp.Regexp
+}
+
+func (n cmpNode) match(w ax.Window) bool {
+	if n.field.str != nil {
+		v := n.field.str(w)
+		switch n.op {
+		case "==":
+			return strings.EqualFold(v, n.str)
+		case "!=":
+			return !strings.EqualFold(v, n.str)
+		case "=~":
+			return n.re.MatchString(v)
+		default: // "!~"
+			return !n.re.MatchString(v)
+		}
+	}
+	v := n.field.num(w)
+	switch n.op {
+	case "==":
+		return v == n.num
+	case "!=":
+		return v != n.num
+	case "<":
+		return v < n.num
+	case "<=":
+		return v <= n.num
+	case ">":
+		return v > n.num
+	default: // ">="
+		return v >= n.num
+	}
+}
+
+type whereParser struct {
+	src string
+	pos int
+}
+
+// parseWhere parses a filter expression such as `app == "Safari" && width > 800`.
+func parseWhere(src string) (whereNode, error) {
+	p := &whereParser{src: src}
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("empty expression")
+	}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q (want && or ||)", p.src[p.pos])
+	}
+	return n, nil
+}
+
+func (p *whereParser) errorf(format string, args ...any) error {
+	return &WhereError{Expr: p.src, Column: p.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *whereParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+// consume skips spaces and advances past tok when the input continues with it.
+func (p *whereParser) consume(tok string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], tok) {
+		p.pos += len(tok)
+		return true
+	}
+	return false
+}
+
+func (p *whereParser) parseOr() (whereNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *whereParser) parseAnd() (whereNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *whereParser) parseUnary() (whereNode, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of expression")
+	}
+	switch p.src[p.pos] {
+	case '!':
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x: x}, nil
+	case '(':
+		p.pos++
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, p.errorf("missing closing parenthesis")
+		}
+		return n, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *whereParser) parseCompare() (whereNode, error) {
+	start := p.pos
+	for p.pos < len(p.src) && isFieldChar(p.src[p.pos]) {
+		p.pos++
+	}
+	name := p.src[start:p.pos]
+	if name == "" {
+		return nil, p.errorf("unexpected %q (want a field name)", p.src[p.pos])
+	}
+	field, ok := whereFields[name]
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("unknown field %q (want one of %s)", name, strings.Join(whereFieldNames(), ", "))
+	}
+
+	p.skipSpace()
+	opStart := p.pos
+	op := p.parseOp()
+	if op == "" {
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unexpected end of expression (want a comparison operator)")
+		}
+		return nil, p.errorf("unexpected %q (want ==, !=, <, <=, >, >=, =~ or !~)", p.src[p.pos])
+	}
+	isStr := field.str != nil
+	if isStr && (op == "<" || op == "<=" || op == ">" || op == ">=") {
+		p.pos = opStart
+		return nil, p.errorf("%s is a text field and does not support %s", name, op)
+	}
+	if !isStr && (op == "=~" || op == "!~") {
+		p.pos = opStart
+		return nil, p.errorf("%s is a numeric field and does not support %s", name, op)
+	}
+
+	p.skipSpace()
+	valStart := p.pos
+	n := cmpNode{field: field, op: op}
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of expression (want a value)")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '"' || c == '\'':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		if !isStr {
+			p.pos = valStart
+			return nil, p.errorf("%s is a numeric field; compare it with a number", name)
+		}
+		n.str = s
+	case c == '-' || c >= '0' && c <= '9':
+		p.pos++
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		text := p.src[valStart:p.pos]
+		v, err := strconv.Atoi(text)
+		if err != nil {
+			p.pos = valStart
+			return nil, p.errorf("invalid number %q", text)
+		}
+		if isStr {
+			p.pos = valStart
+			return nil, p.errorf("%s is a text field; compare it with a quoted string", name)
+		}
+		n.num = v
+	default:
+		return nil, p.errorf("unexpected %q (want a quoted string or a number)", c)
+	}
+
+	if op == "=~" || op == "!~" {
+		re, err := regexp.Compile(n.str)
+		if err != nil {
+			p.pos = valStart
+			return nil, p.errorf("invalid regular expression: %v", err)
+		}
+		n.re = re
+	} else if name == "state" && !slices.Contains(windowStates, strings.ToLower(n.str)) {
+		p.pos = valStart
+		return nil, p.errorf("unknown state %q (want one of %s)", n.str, strings.Join(windowStates, ", "))
+	}
+	return n, nil
+}
+
+// parseOp reads a comparison operator, returning "" when none follows.
+func (p *whereParser) parseOp() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "=~", "!~", "<", ">"} {
+		if strings.HasPrefix(p.src[p.pos:], op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+// parseString reads a string quoted with " or '. A backslash escapes the quote and
+// itself; other backslashes are kept so that regular expressions such as "\d+" work.
+func (p *whereParser) parseString() (string, error) {
+	start := p.pos
+	quote := p.src[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\' && p.pos+1 < len(p.src) && (p.src[p.pos+1] == quote || p.src[p.pos+1] == '\\'):
+			b.WriteByte(p.src[p.pos+1])
+			p.pos += 2
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
+
+func isFieldChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
+
+func whereFieldNames() []string {
+	names := make([]string, 0, len(whereFields))
+	for name := range whereFields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,94 @@
+package window_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/peacock0803sz/mado/internal/ax"
+	"github.com/peacock0803sz/mado/internal/window"
+)
+
+func TestWhere_Match(t *testing.T) {
+	safari := ax.Window{
+		ID: 7, AppName: "Safari", BundleID: "com.apple.Safari", Title: "PR #42 - GitHub",
+		X: 100, Y: 50, Width: 1440, Height: 900, State: ax.StateNormal,
+		ScreenID: 1, ScreenName: "Built-in", Desktop: 2,
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`app == "Safari"`, true},
+		{`app == 'safari'`, true},
+		{`app_name != "Safari"`, false},
+		{`width > 800`, true},
+		{`width > 1440`, false},
+		{`width >= 1440 && height <= 900`, true},
+		{`x < 0 || y == 50`, true},
+		{`desktop == 2 && screen_id == 1 && id != 8`, true},
+		{`x > -10`, true},
+		{`state != "minimized"`, true},
+		{`app == "Safari" && width > 800 && state != "minimized"`, true},
+		{`!(app == "Safari")`, false},
+		{`!app == "Terminal"`, true},
+		{`title =~ "#\d+"`, true},
+		{`title !~ "(?i)github"`, false},
+		{`bundle_id == "com.apple.safari"`, true},
+		{`screen == "built-in"`, true},
+		{`app == "Terminal" || app == "Safari" && width < 100`, false},
+		{`(app == "Terminal" || app == "Safari") && width > 100`, true},
+		{`title == 'say \'hi\''`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			m, err := window.CompileMatch(window.MatchSpec{Where: tt.expr})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := m.Match(safari); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWhere_Errors(t *testing.T) {
+	tests := []struct {
+		expr   string
+		column int
+		msg    string
+	}{
+		{` `, 2, "empty expression"},
+		{`widht > 800`, 1, `unknown field "widht"`},
+		{`app == "Safari" && widht > 800`, 20, `unknown field "widht"`},
+		{`width 800`, 7, "want ==, !=, <, <=, >, >=, =~ or !~"},
+		{`width > "800"`, 9, "width is a numeric field"},
+		{`app == Safari`, 8, "want a quoted string or a number"},
+		{`app == 1`, 8, "app is a text field"},
+		{`app > "A"`, 5, "does not support >"},
+		{`width =~ "8"`, 7, "does not support =~"},
+		{`title == "unterminated`, 10, "unterminated string"},
+		{`title =~ "(main"`, 10, "invalid regular expression"},
+		{`state == "minimised"`, 10, `unknown state "minimised"`},
+		{`(app == "Safari"`, 17, "missing closing parenthesis"},
+		{`app == "Safari" and width > 1`, 17, "want && or ||"},
+		{`app == "Safari" &&`, 19, "unexpected end of expression"},
+		{`width >`, 8, "want a value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := window.CompileMatch(window.MatchSpec{Where: tt.expr})
+			var whereErr *window.WhereError
+			if !errors.As(err, &whereErr) {
+				t.Fatalf("expected *window.WhereError, got %T: %v", err, err)
+			}
+			if whereErr.Column != tt.column {
+				t.Errorf("Column = %d, want %d (%v)", whereErr.Column, tt.column, err)
+			}
+			if !strings.Contains(whereErr.Msg, tt.msg) {
+				t.Errorf("Msg = %q, want it to contain %q", whereErr.Msg, tt.msg)
+			}
+		})
+	}
+}
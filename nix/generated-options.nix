@@ -4,9 +4,9 @@
 {
   options = {
     format = lib.mkOption {
-      type = lib.types.nullOr (lib.types.enum [ "text" "json" ]);
+      type = lib.types.nullOr (lib.types.str);
       default = null;
-      description = "Default output format";
+      description = "Default output format: text, json, ndjson, csv, tsv, yaml, map, svg, or template=<go template>";
     };
     ignore_apps = lib.mkOption {
       type = lib.types.nullOr (lib.types.listOf (lib.types.str));
@@ -29,43 +29,245 @@
             type = lib.types.listOf (lib.types.submodule {
               options = {
                 app = lib.mkOption {
-                  type = lib.types.str;
-                  description = "Application name (case-insensitive exact match)";
+                  type = lib.types.nullOr (lib.types.str);
+                  default = null;
+                  description = "Application name (case-insensitive exact match, or a glob such as \"Google Chrome*\")";
+                };
+                area = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.enum [ "frame" "visible" ]);
+                  default = null;
+                  description = "Part of the target screen that snap and relative geometry refer to: frame (full display) or visible (without the menu bar and Dock)";
+                };
+                bundle_id = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.str);
+                  default = null;
+                  description = "Bundle identifier (case-insensitive exact match, e.g. \"com.microsoft.VSCode\")";
                 };
                 desktop = lib.mkOption {
                   type = lib.types.nullOr (lib.types.ints.unsigned);
                   default = null;
                   description = "Desktop number to scope this rule to (0 = windows assigned to all desktops)";
                 };
+                exclude_app = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.str);
+                  default = null;
+                  description = "Skip windows of apps matching this name or glob (case-insensitive)";
+                };
+                exclude_title = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.str);
+                  default = null;
+                  description = "Skip windows whose title contains this (case-insensitive partial match)";
+                };
+                gap = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.ints.unsigned);
+                  default = null;
+                  description = "Pixels between adjacent snapped windows (with snap)";
+                };
+                margin = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.ints.unsigned);
+                  default = null;
+                  description = "Pixels between the screen edge and the snapped window (with snap)";
+                };
                 position = lib.mkOption {
-                  type = lib.types.nullOr (lib.types.listOf (lib.types.int));
+                  type = lib.types.nullOr (lib.types.listOf (lib.types.oneOf [ lib.types.int lib.types.number lib.types.str ]));
+                  default = null;
+                  description = "Target position [x, y]: integers are global coordinates; fractions (0.5), percentages (\"50%\") and expressions (\"screen.width - 400\") are relative to the target screen";
+                };
+                reference = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.submodule {
+                    options = {
+                      height = lib.mkOption {
+                        type = lib.types.ints.positive;
+                        description = "height";
+                      };
+                      width = lib.mkOption {
+                        type = lib.types.ints.positive;
+                        description = "width";
+                      };
+                      x = lib.mkOption {
+                        type = lib.types.int;
+                        description = "x";
+                      };
+                      y = lib.mkOption {
+                        type = lib.types.int;
+                        description = "y";
+                      };
+                    };
+                  });
                   default = null;
-                  description = "Target position [x, y] in global coordinates";
+                  description = "Screen the absolute position and size were recorded on (written by preset rec); they are scaled to target screens of other sizes according to the preset's scale";
                 };
                 screen = lib.mkOption {
                   type = lib.types.nullOr (lib.types.str);
                   default = null;
-                  description = "Screen ID or name filter";
+                  description = "Screen ID, name, alias, \"#N\" index or selector (primary, not-primary, largest, smallest, leftmost, rightmost, topmost, bottommost) filter (also the reference screen for relative geometry)";
                 };
                 size = lib.mkOption {
-                  type = lib.types.nullOr (lib.types.listOf (lib.types.ints.positive));
+                  type = lib.types.nullOr (lib.types.listOf (lib.types.oneOf [ lib.types.ints.positive lib.types.number lib.types.str ]));
+                  default = null;
+                  description = "Target size [width, height]: positive integers in pixels, or fractions (0.5), percentages (\"50%\") and expressions (\"screen.height - 100\") of the target screen";
+                };
+                snap = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.enum [ "bottom-half" "bottom-left-quarter" "bottom-right-quarter" "center" "center-third" "left-half" "left-third" "left-two-thirds" "maximize" "right-half" "right-third" "right-two-thirds" "top-half" "top-left-quarter" "top-right-quarter" ]);
                   default = null;
-                  description = "Target size [width, height] (positive integers)";
+                  description = "Named snap position on the target screen (replaces position and size)";
                 };
                 title = lib.mkOption {
                   type = lib.types.nullOr (lib.types.str);
                   default = null;
                   description = "Window title filter (case-insensitive partial match)";
                 };
+                title_regex = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.str);
+                  default = null;
+                  description = "Window title filter (regular expression, RE2 syntax)";
+                };
               };
             });
             description = "Window operation rules (evaluated in order, first match wins)";
           };
+          scale = lib.mkOption {
+            type = lib.types.nullOr (lib.types.enum [ "none" "proportional" "fit" ]);
+            default = null;
+            description = "How absolute geometry of rules with a reference screen is adapted to a screen of another size: proportional (stretch each axis), fit (keep the aspect ratio, centered) or none";
+          };
+          screens = lib.mkOption {
+            type = lib.types.nullOr (lib.types.listOf (lib.types.submodule {
+              options = {
+                height = lib.mkOption {
+                  type = lib.types.ints.positive;
+                  description = "Height in pixels";
+                };
+                name = lib.mkOption {
+                  type = lib.types.str;
+                  description = "Screen name, matched by the screen field of rules";
+                };
+                primary = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.bool);
+                  default = null;
+                  description = "Whether this is the primary screen (rules without a screen are drawn on it)";
+                };
+                visible = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.submodule {
+                    options = {
+                      height = lib.mkOption {
+                        type = lib.types.ints.positive;
+                        description = "height";
+                      };
+                      width = lib.mkOption {
+                        type = lib.types.ints.positive;
+                        description = "width";
+                      };
+                      x = lib.mkOption {
+                        type = lib.types.int;
+                        description = "x";
+                      };
+                      y = lib.mkOption {
+                        type = lib.types.int;
+                        description = "y";
+                      };
+                    };
+                  });
+                  default = null;
+                  description = "Usable area without the menu bar and Dock (as visible_frame in mado screens --format json); defaults to the full screen";
+                };
+                width = lib.mkOption {
+                  type = lib.types.ints.positive;
+                  description = "Width in pixels";
+                };
+                x = lib.mkOption {
+                  type = lib.types.int;
+                  description = "Left edge in global coordinates";
+                };
+                y = lib.mkOption {
+                  type = lib.types.int;
+                  description = "Top edge in global coordinates";
+                };
+              };
+            }));
+            default = null;
+            description = "Screen arrangement the preset was designed for, used to draw its layout (preset show --format svg) without querying the connected displays";
+          };
         };
       }));
       default = null;
       description = "Named window layout presets";
     };
+    profiles = lib.mkOption {
+      type = lib.types.nullOr (lib.types.listOf (lib.types.submodule {
+        options = {
+          name = lib.mkOption {
+            type = lib.types.str;
+            description = "Profile name";
+          };
+          preset = lib.mkOption {
+            type = lib.types.str;
+            description = "Name of the preset to apply when the profile matches";
+          };
+          screens = lib.mkOption {
+            type = lib.types.listOf (lib.types.submodule {
+              options = {
+                height = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.ints.positive);
+                  default = null;
+                  description = "Screen height in pixels; omit to match any height";
+                };
+                name = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.str);
+                  default = null;
+                  description = "Screen name as shown by mado screens (case-insensitive); omit to match any screen";
+                };
+                width = lib.mkOption {
+                  type = lib.types.nullOr (lib.types.ints.positive);
+                  default = null;
+                  description = "Screen width in pixels; omit to match any width";
+                };
+              };
+            });
+            description = "One entry per connected screen, in any order; the profile matches only when as many screens are connected";
+          };
+        };
+      }));
+      default = null;
+      description = "Display profiles: the preset that preset auto applies for a set of connected screens (see profile detect)";
+    };
+    screens = lib.mkOption {
+      type = lib.types.nullOr (lib.types.listOf (lib.types.submodule {
+        options = {
+          alias = lib.mkOption {
+            type = lib.types.str;
+            description = "Alias name (case-insensitive); takes precedence over screen names and next, prev, left and right";
+          };
+          id = lib.mkOption {
+            type = lib.types.nullOr (lib.types.ints.positive);
+            default = null;
+            description = "Screen ID as shown by mado screens";
+          };
+          name = lib.mkOption {
+            type = lib.types.nullOr (lib.types.str);
+            default = null;
+            description = "Screen name, case-insensitive exact match or glob (e.g. \"DELL*\")";
+          };
+          position = lib.mkOption {
+            type = lib.types.nullOr (lib.types.enum [ "leftmost" "rightmost" "topmost" "bottommost" ]);
+            default = null;
+            description = "Place of the screen in the display arrangement";
+          };
+          primary = lib.mkOption {
+            type = lib.types.nullOr (lib.types.bool);
+            default = null;
+            description = "Match only the primary screen";
+          };
+          resolution = lib.mkOption {
+            type = lib.types.nullOr (lib.types.str);
+            default = null;
+            description = "Screen size as WIDTHxHEIGHT (e.g. 2560x1440)";
+          };
+        };
+      }));
+      default = null;
+      description = "Screen aliases usable wherever a screen is referenced (--screen, --to-screen, rule screen). Set criteria must all match; the first matching screen in index order is used";
+    };
     timeout = lib.mkOption {
       type = lib.types.nullOr (lib.types.str);
       default = null;
@@ -73,6 +275,10 @@
     };
   };
   assertions = cfg: [
+    {
+      assertion = cfg.settings.format == null || builtins.match "^(text|json|ndjson|csv|tsv|yaml|map|svg|template=.+)$" cfg.settings.format != null;
+      message = "format must match pattern ^(text|json|ndjson|csv|tsv|yaml|map|svg|template=.+)$";
+    }
     {
       assertion = cfg.settings.ignore_apps == null || builtins.all (s: builtins.stringLength s >= 1) cfg.settings.ignore_apps;
       message = "ignore_apps items must be non-empty strings";
@@ -86,8 +292,8 @@
       message = "rules must have at least 1 item(s)";
     }
     {
-      assertion = cfg.settings.presets == null || builtins.all (p: builtins.all (r: (r.position != null) || (r.size != null)) p.rules) cfg.settings.presets;
-      message = "Each preset rule must have at least 'position' or 'size'";
+      assertion = cfg.settings.presets == null || builtins.all (p: builtins.all (r: (r.position != null) || (r.size != null) || (r.snap != null)) p.rules) cfg.settings.presets;
+      message = "Each preset rule must have at least 'position', 'size' or 'snap'";
     }
     {
       assertion = cfg.settings.presets == null || builtins.all (p: builtins.all (r: r.position == null || builtins.length r.position >= 2) p.rules) cfg.settings.presets;
@@ -105,6 +311,22 @@
       assertion = cfg.settings.presets == null || builtins.all (p: builtins.all (r: r.size == null || builtins.length r.size <= 2) p.rules) cfg.settings.presets;
       message = "size must have at most 2 item(s)";
     }
+    {
+      assertion = cfg.settings.profiles == null || builtins.all (p: builtins.length p.screens >= 1) cfg.settings.profiles;
+      message = "screens must have at least 1 item(s)";
+    }
+    {
+      assertion = cfg.settings.screens == null || builtins.all (s: (s.id != null) || (s.name != null) || (s.resolution != null) || (s.position != null) || (s.primary != null)) cfg.settings.screens;
+      message = "Each screen alias must have at least 'id', 'name', 'resolution', 'position' or 'primary'";
+    }
+    {
+      assertion = cfg.settings.screens == null || builtins.all (s: builtins.match "^[^#]" s.alias != null) cfg.settings.screens;
+      message = "alias must match pattern ^[^#]";
+    }
+    {
+      assertion = cfg.settings.screens == null || builtins.all (s: s.resolution == null || builtins.match "^[0-9]+[xX][0-9]+$" s.resolution != null) cfg.settings.screens;
+      message = "resolution must match pattern ^[0-9]+[xX][0-9]+$";
+    }
     {
       assertion = cfg.settings.timeout == null || builtins.match "^[0-9]+(ns|us|ms|s|m|h)$" cfg.settings.timeout != null;
       message = "timeout must match pattern ^[0-9]+(ns|us|ms|s|m|h)$";